@@ -2,38 +2,99 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 )
 
 // App struct
 type App struct {
-	ctx            context.Context
-	processManager *ProcessManager // 进程管理器
-	configManager  *ConfigManager  // 配置管理器
-	exitOnce       sync.Once       // 确保退出逻辑只执行一次
-	appDataDir     string          // 应用数据目录
+	ctx             context.Context
+	processManager  *ProcessManager       // 进程管理器
+	configManager   *ConfigManager        // 配置管理器
+	journal         *EventJournal         // 生命周期事件日志
+	binaryManager   *BinaryManager        // 服务二进制文件管理器
+	fileServer      *FileServer           // 内置静态文件服务器
+	gateway         *Gateway              // 单端口本地网关
+	certAuthority   *CertificateAuthority // 本地 HTTPS 证书颁发机构
+	secrets         *SecretStore          // API Key 等敏感配置的加密存储
+	exitOnce        sync.Once             // 确保退出逻辑只执行一次
+	stopConfigWatch func()                // 停止监视配置文件
+	restartMu       sync.Mutex            // 保护 restartRequired
+	restartRequired map[string][]string   // 配置已变化、需要重启才能生效的服务及变化的配置段
+	startupErrors   []string              // 启动时初始化失败的组件及原因
+	errorsMu        sync.Mutex            // 保护 lostErrors
+	lostErrors      []string              // 无法写入事件日志的后台错误
+	appDataDir      string                // 应用数据目录
 }
 
 // NewApp creates a new App application struct
-func NewApp() *App {
+func NewApp(configOptions ConfigOptions) *App {
 	app := &App{}
 
-	// 初始化配置管理器
-	var err error
-	app.configManager, err = NewConfigManager()
+	// 获取应用数据目录
+	app.appDataDir = app.getAppDataDir()
+
+	// 初始化配置管理器和密钥存储，加载配置前设置加密，配置文件中的明文 API Key 在写入配置文件和历史版本之前迁移到密钥存储
+	manager, err := newConfigManager(configOptions)
+	secretsDir := app.appDataDir
+	if err == nil {
+		secretsDir = manager.GetConfigDir()
+	}
+	var secretsErr error
+	app.secrets, secretsErr = NewSecretStore(secretsDir)
+	if secretsErr != nil {
+		// 密钥存储不可用时仍然允许应用启动，保存和使用 API Key 时会报错
+		app.startupErrors = append(app.startupErrors, fmt.Sprintf("secrets store: %v", secretsErr))
+	}
+	if err == nil {
+		app.configManager = manager
+		manager.sealSecrets = app.sealChangedSecrets
+		manager.secrets = app.secrets
+		manager.onError = func(err error) { app.reportError("", err) }
+		if err = manager.load(); err != nil {
+			app.configManager = nil
+		}
+	}
 	if err != nil {
 		// 如果配置管理器初始化失败，记录错误但不阻止应用启动
-		// TODO: 这里可以添加日志记录
-		_ = err
+		app.startupErrors = append(app.startupErrors, fmt.Sprintf("config manager: %v", err))
 	}
 
-	// 获取应用数据目录
-	app.appDataDir = app.getAppDataDir()
+	// 初始化事件日志
+	app.journal, err = NewEventJournal(filepath.Join(app.appDataDir, "logs"))
+	if err != nil {
+		// 事件日志不可用时仍然允许应用启动
+		app.startupErrors = append(app.startupErrors, fmt.Sprintf("event journal: %v", err))
+	}
+	app.recordConfigRecovery()
+
+	// 初始化二进制文件管理器
+	app.binaryManager = NewBinaryManager(filepath.Join(app.appDataDir, "bin"))
+	app.binaryManager.verifyExec = func(path string) error {
+		_, err := app.verifyExecutable(path)
+		return err
+	}
+	app.binaryManager.isTrusted = app.isTrustedBinary
+
+	// 初始化内置文件服务器
+	app.fileServer = NewFileServer()
+
+	// 初始化本地网关
+	app.gateway = NewGateway()
+
+	// 初始化本地证书颁发机构，证书保存在配置目录下
+	tlsDir := filepath.Join(app.appDataDir, "tls")
+	if app.configManager != nil {
+		tlsDir = filepath.Join(app.configManager.GetConfigDir(), "tls")
+	}
+	app.certAuthority = NewCertificateAuthority(tlsDir)
+	app.certAuthority.hosts = app.certificateHosts
 
 	// 在 Wails 中，主要依赖 OnShutdown 和 OnBeforeClose 钩子
 	// 但仍然保留系统信号监听作为备用
@@ -72,18 +133,26 @@ func (a *App) getAppDataDir() string {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	// 创建进程管理器
-	a.processManager = NewProcessManager(ctx)
+	a.processManager = NewProcessManager(ctx, a.journal)
+	a.processManager.onError = a.keepUnrecordedError
 
 	// 注册新的进程
 	a.registerProcesses()
+
+	// 订阅配置变更
+	a.watchConfigChanges()
+
+	// 自动更新组件后启动网关和默认服务组
+	go func() {
+		a.autoUpdateComponents()
+		a.startGatewayIfEnabled()
+		a.startDefaultProfile()
+	}()
 }
 
 // getExecutableName 根据操作系统返回可执行文件名
 func (a *App) getExecutableName(baseName string) string {
-	if runtime.GOOS == "windows" {
-		return baseName + ".exe"
-	}
-	return baseName
+	return executableName(baseName)
 }
 
 // registerProcesses 注册应用进程
@@ -93,11 +162,15 @@ func (a *App) registerProcesses() {
 	// 注册 workflowui 进程
 	workflowuiDataDir := filepath.Join(a.appDataDir, "data", "workflowui")
 	workflowuiExe := a.getExecutableName("workflowui")
+	// config.json 包含明文 API Key，进程退出后删除，并删除上次异常退出时残留的文件
+	workflowuiConfigFile := filepath.Join(workflowuiDataDir, "config.json")
+	os.Remove(workflowuiConfigFile)
 	a.processManager.RegisterProcess("workflowui", &ProcessConfig{
-		Name:    "workflowui",
-		Command: filepath.Join(binDir, workflowuiExe),
-		Args:    []string{},
-		WorkDir: workflowuiDataDir,
+		Name:      "workflowui",
+		Command:   filepath.Join(binDir, workflowuiExe),
+		Args:      []string{},
+		WorkDir:   workflowuiDataDir,
+		Generated: []string{workflowuiConfigFile},
 	})
 
 	// 注册 edu-tools 进程
@@ -110,6 +183,22 @@ func (a *App) registerProcesses() {
 		WorkDir: eduToolsDataDir,
 	})
 
+	// 注册内置文件服务器
+	a.processManager.RegisterProcess("fileserver", &ProcessConfig{
+		Name:    "fileserver",
+		Args:    []string{},
+		Service: a.fileServer,
+	})
+
+	// 注册本地网关
+	a.processManager.RegisterProcess("gateway", &ProcessConfig{
+		Name:    "gateway",
+		Args:    []string{},
+		Service: a.gateway,
+	})
+
+	a.applyGatewayDependencies()
+
 	// 确保数据目录存在
 	os.MkdirAll(workflowuiDataDir, 0755)
 	os.MkdirAll(eduToolsDataDir, 0755)
@@ -144,6 +233,9 @@ func (a *App) setupExitHandler() {
 // cleanup 统一的清理逻辑，确保只执行一次
 func (a *App) cleanup() {
 	a.exitOnce.Do(func() {
+		if a.stopConfigWatch != nil {
+			a.stopConfigWatch()
+		}
 		if a.processManager != nil {
 			a.processManager.StopAllProcesses()
 			a.processManager.ReleaseResources()
@@ -178,16 +270,57 @@ func (a *App) GetRegisteredProcessNames() []string {
 
 // StartProcess 启动指定进程
 func (a *App) StartProcess(processName string, extraArgs ...string) string {
-	if a.processManager != nil {
-		return a.processManager.StartProcess(processName, extraArgs...)
-	}
-	return "Process manager not initialized"
+	return a.startProcessAs(EventOrigin{Actor: ActorUI}, processName, extraArgs...)
 }
 
 // StopProcess 停止指定进程
 func (a *App) StopProcess(processName string) string {
+	return a.stopProcessAs(EventOrigin{Actor: ActorUI}, processName)
+}
+
+// startProcessAs 以指定来源启动进程，启动前按策略校验二进制文件签名
+func (a *App) startProcessAs(origin EventOrigin, processName string, extraArgs ...string) string {
+	if a.processManager == nil {
+		return "Process manager not initialized"
+	}
+
+	warning, err := a.verifyBeforeStart(processName)
+	if err != nil {
+		return fmt.Sprintf("Refused to start process '%s': %v", processName, err)
+	}
+	return a.startVerifiedProcess(origin, processName, warning, extraArgs...)
+}
+
+// startVerifiedProcess 启动已按策略校验过签名的进程，warning 为校验时的警告
+// 启动前需要执行组件（如检查版本）的服务先校验再生成启动参数，避免未通过校验的二进制文件被执行
+func (a *App) startVerifiedProcess(origin EventOrigin, processName string, warning string, extraArgs ...string) string {
+	if a.processManager == nil {
+		return "Process manager not initialized"
+	}
+
+	result := a.processManager.StartProcessAs(origin, processName, extraArgs...)
+	if strings.Contains(result, "started successfully") {
+		a.updateGatewayTarget(processName, extraArgs)
+		a.clearRestartRequired(processName)
+	}
+	if warning != "" {
+		result = warning + "\n" + result
+	}
+	return result
+}
+
+// joinWarnings 合并启动前产生的警告，每条警告一行
+func joinWarnings(warning, more string) string {
+	if warning == "" || more == "" {
+		return warning + more
+	}
+	return warning + "\n" + more
+}
+
+// stopProcessAs 以指定来源停止进程
+func (a *App) stopProcessAs(origin EventOrigin, processName string) string {
 	if a.processManager != nil {
-		return a.processManager.StopProcess(processName)
+		return a.processManager.StopProcessAs(origin, processName)
 	}
 	return "Process manager not initialized"
 }
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ===============================
+// 组件二进制管理相关接口
+// ===============================
+
+// GetBinariesConfig 获取二进制文件管理配置
+func (a *App) GetBinariesConfig() *BinariesConfig {
+	if a.configManager == nil {
+		return nil
+	}
+	config := a.configManager.GetConfig()
+	if config == nil {
+		return nil
+	}
+	return &config.Binaries
+}
+
+// UpdateBinariesConfig 更新二进制文件管理配置
+func (a *App) UpdateBinariesConfig(binaries BinariesConfig) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	err := a.configManager.UpdateBinariesConfig(binaries)
+	if err != nil {
+		return fmt.Sprintf("Failed to update binaries config: %v", err)
+	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI}, "binaries")
+
+	return "Binaries configuration updated successfully"
+}
+
+// GetReleaseManifest 从配置的地址获取发布清单
+func (a *App) GetReleaseManifest() (*ReleaseManifest, error) {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return nil, fmt.Errorf("Configuration manager not initialized")
+	}
+	return a.binaryManager.FetchManifest(binaries.ManifestURL)
+}
+
+// GetInstalledComponents 获取已安装组件的版本和兼容性信息
+func (a *App) GetInstalledComponents() []InstalledComponent {
+	return a.binaryManager.InstalledComponents()
+}
+
+// VerifyInstalledComponents 校验所有受管组件的签名和完整性
+func (a *App) VerifyInstalledComponents() []BinaryVerification {
+	results := make([]BinaryVerification, 0, len(managedComponents))
+	for _, name := range managedComponents {
+		results = append(results, a.binaryManager.VerifyComponent(name))
+	}
+	return results
+}
+
+// InstallComponent 下载并安装组件，版本为空时安装最新版本
+func (a *App) InstallComponent(name string, version string) string {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return "Configuration manager not initialized"
+	}
+
+	result, err := a.binaryManager.Install(binaries.ManifestURL, name, InstallOptions{
+		Version:      version,
+		Policy:       binaries.SignaturePolicy,
+		KeepVersions: binaries.KeepVersions,
+	})
+	if err != nil {
+		return fmt.Sprintf("Failed to install component '%s': %v", name, err)
+	}
+
+	a.recordInstall(EventOrigin{Actor: ActorUI}, result)
+
+	message := fmt.Sprintf("Component '%s' version %s installed successfully", name, result.Version)
+	if !result.Signed && binaries.SignaturePolicy != SignaturePolicyOff {
+		message += "; WARNING: the release manifest is not signed by a trusted key"
+	}
+	if a.processManager != nil && a.processManager.IsRunning(name) {
+		message += "; restart the service to use the new version"
+	}
+	return message
+}
+
+// verifyBeforeStart 按签名策略校验即将运行的 bin 目录中的二进制文件
+// enforce 策略下校验失败返回错误，warn 策略下返回警告信息
+func (a *App) verifyBeforeStart(processName string) (string, error) {
+	if a.processManager == nil {
+		return "", nil
+	}
+	config, exists := a.processManager.GetProcessConfig(processName)
+	if !exists {
+		return "", nil
+	}
+	return a.verifyExecutable(config.Command)
+}
+
+// verifyExecutable 按签名策略校验 bin 目录中的二进制文件，执行受管组件前调用
+// 用户确认信任的文件不校验签名；enforce 策略下校验失败返回错误，warn 策略下返回警告信息
+func (a *App) verifyExecutable(path string) (string, error) {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil || binaries.SignaturePolicy == SignaturePolicyOff || !a.binaryManager.InBinDir(path) {
+		return "", nil
+	}
+	for _, name := range managedComponents {
+		if a.binaryManager.ExecutablePath(name) != path {
+			continue
+		}
+		if hash, err := fileSHA256(path); err == nil && a.isTrustedBinary(name, hash) {
+			return "", nil
+		}
+	}
+
+	verification := a.binaryManager.VerifyBinary(path)
+	if verification.Status == VerificationSigned || verification.Status == VerificationMissing {
+		return "", nil
+	}
+
+	problem := fmt.Sprintf("binary '%s' failed signature verification (%s): %s",
+		path, verification.Status, verification.Message)
+	if binaries.SignaturePolicy == SignaturePolicyEnforce {
+		return "", fmt.Errorf("%s", problem)
+	}
+	return "WARNING: " + problem, nil
+}
+
+// GetComponentVersions 获取组件归档的历史版本
+func (a *App) GetComponentVersions(name string) []ArchivedVersion {
+	return a.binaryManager.ArchivedVersions(name)
+}
+
+// RollbackComponent 将组件回滚到上一个版本并固定该版本，服务正在运行时使用恢复的版本重启
+// 固定版本避免自动更新重新安装有问题的最新版本，可通过 UnpinComponent 取消
+func (a *App) RollbackComponent(name string) string {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return "Configuration manager not initialized"
+	}
+
+	result, err := a.binaryManager.Rollback(name, binaries.KeepVersions)
+	if err != nil {
+		return fmt.Sprintf("Failed to roll back component '%s': %v", name, err)
+	}
+
+	origin := EventOrigin{Actor: ActorUI, Reason: "rollback"}
+	a.recordInstall(origin, result)
+
+	message := fmt.Sprintf("Component '%s' rolled back to version %s", name, result.Version)
+	if pin := a.PinComponent(name, result.Version); strings.Contains(pin, "pinned to") {
+		message += fmt.Sprintf("\nVersion %s pinned so updates will not replace it; unpin the component to resume updates", result.Version)
+	} else {
+		message += "\nWARNING: " + pin + "; updates will replace the restored version"
+	}
+	if restart := a.restartIfRunning(origin, name); restart != "" {
+		message += "\n" + restart
+	}
+	return message
+}
+
+// PinComponent 固定组件版本，自动更新将跳过该组件；version 为空时固定为当前安装的版本
+func (a *App) PinComponent(name string, version string) string {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return "Configuration manager not initialized"
+	}
+	if !isManagedComponent(name) {
+		return fmt.Sprintf("Component '%s' is not managed by the binary manager", name)
+	}
+
+	if version == "" {
+		version = a.binaryManager.InspectComponent(name).Version
+		if version == "" {
+			return fmt.Sprintf("Failed to pin component '%s': installed version is unknown", name)
+		}
+	}
+
+	updated := *binaries
+	updated.Pinned = make(map[string]string, len(binaries.Pinned)+1)
+	for component, pinned := range binaries.Pinned {
+		updated.Pinned[component] = pinned
+	}
+	updated.Pinned[name] = version
+
+	if result := a.UpdateBinariesConfig(updated); !strings.Contains(result, "successfully") {
+		return result
+	}
+	return fmt.Sprintf("Component '%s' pinned to version %s", name, version)
+}
+
+// UnpinComponent 取消组件的版本固定
+func (a *App) UnpinComponent(name string) string {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return "Configuration manager not initialized"
+	}
+	if _, pinned := binaries.Pinned[name]; !pinned {
+		return fmt.Sprintf("Component '%s' is not pinned", name)
+	}
+
+	updated := *binaries
+	updated.Pinned = make(map[string]string, len(binaries.Pinned))
+	for component, pinned := range binaries.Pinned {
+		if component != name {
+			updated.Pinned[component] = pinned
+		}
+	}
+
+	if result := a.UpdateBinariesConfig(updated); !strings.Contains(result, "successfully") {
+		return result
+	}
+	return fmt.Sprintf("Component '%s' unpinned", name)
+}
+
+// TrustComponent 信任组件当前的二进制文件，允许运行未通过组件管理器安装的文件（如手动复制到 bin 目录的版本）
+// 信任按文件哈希记录，文件被替换后需要重新确认
+func (a *App) TrustComponent(name string) string {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return "Configuration manager not initialized"
+	}
+	if !isManagedComponent(name) {
+		return fmt.Sprintf("Component '%s' is not managed by the binary manager", name)
+	}
+
+	hash, err := fileSHA256(a.binaryManager.ExecutablePath(name))
+	if err != nil {
+		return fmt.Sprintf("Failed to trust component '%s': %v", name, err)
+	}
+
+	updated := *binaries
+	updated.Trusted = make(map[string]string, len(binaries.Trusted)+1)
+	for component, trusted := range binaries.Trusted {
+		updated.Trusted[component] = trusted
+	}
+	updated.Trusted[name] = hash
+
+	if result := a.UpdateBinariesConfig(updated); !strings.Contains(result, "successfully") {
+		return result
+	}
+	a.binaryManager.forgetVersion(name)
+	return fmt.Sprintf("Component '%s' trusted (SHA-256 %s)", name, hash)
+}
+
+// UntrustComponent 取消对组件二进制文件的信任
+func (a *App) UntrustComponent(name string) string {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return "Configuration manager not initialized"
+	}
+	if _, trusted := binaries.Trusted[name]; !trusted {
+		return fmt.Sprintf("Component '%s' is not trusted", name)
+	}
+
+	updated := *binaries
+	updated.Trusted = make(map[string]string, len(binaries.Trusted))
+	for component, trusted := range binaries.Trusted {
+		if component != name {
+			updated.Trusted[component] = trusted
+		}
+	}
+
+	if result := a.UpdateBinariesConfig(updated); !strings.Contains(result, "successfully") {
+		return result
+	}
+	a.binaryManager.forgetVersion(name)
+	return fmt.Sprintf("Component '%s' is no longer trusted", name)
+}
+
+// isTrustedBinary 判断组件的二进制文件是否由用户确认信任
+func (a *App) isTrustedBinary(component, hash string) bool {
+	binaries := a.GetBinariesConfig()
+	return binaries != nil && hash != "" && strings.EqualFold(binaries.Trusted[component], hash)
+}
+
+// UpdateComponents 将所有未固定版本的组件更新到最新版本
+func (a *App) UpdateComponents() []ServiceResult {
+	return a.updateComponents(EventOrigin{Actor: ActorUI, Reason: "update components"})
+}
+
+// autoUpdateComponents 启动时按配置自动更新组件
+func (a *App) autoUpdateComponents() {
+	binaries := a.GetBinariesConfig()
+	if binaries == nil || !binaries.AutoUpdate || binaries.ManifestURL == "" {
+		return
+	}
+	a.updateComponents(EventOrigin{Actor: ActorSystem, Reason: "automatic update"})
+}
+
+// updateComponents 更新组件，跳过固定版本和已是最新版本的组件，更新后重启正在运行的服务
+func (a *App) updateComponents(origin EventOrigin) []ServiceResult {
+	results := make([]ServiceResult, 0, len(managedComponents))
+
+	binaries := a.GetBinariesConfig()
+	if binaries == nil {
+		return results
+	}
+
+	manifest, err := a.binaryManager.FetchManifest(binaries.ManifestURL)
+	if err != nil {
+		for _, name := range managedComponents {
+			results = append(results, ServiceResult{Service: name, Message: err.Error()})
+		}
+		return results
+	}
+
+	for _, name := range managedComponents {
+		result := ServiceResult{Service: name}
+
+		latest := manifest.Components[name].Latest
+		installed := a.binaryManager.InspectComponent(name)
+		switch {
+		case binaries.Pinned[name] != "":
+			result.Success, result.Skipped = true, true
+			result.Message = fmt.Sprintf("Pinned to version %s", binaries.Pinned[name])
+		case latest == "":
+			result.Message = "Component not found in release manifest"
+		case installed.Version != "" && compareVersions(installed.Version, latest) >= 0:
+			result.Success, result.Skipped = true, true
+			result.Message = fmt.Sprintf("Already up to date (%s)", installed.Version)
+		default:
+			installResult, err := a.binaryManager.Install(binaries.ManifestURL, name, InstallOptions{
+				Version:      latest,
+				Policy:       binaries.SignaturePolicy,
+				KeepVersions: binaries.KeepVersions,
+			})
+			if err != nil {
+				result.Message = fmt.Sprintf("Failed to install version %s: %v", latest, err)
+				break
+			}
+			a.recordInstall(origin, installResult)
+			result.Success = true
+			result.Message = fmt.Sprintf("Updated to version %s", installResult.Version)
+			if restart := a.restartIfRunning(origin, name); restart != "" {
+				result.Message += "\n" + restart
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// restartIfRunning 服务正在运行时按配置的重启方式重启，使其加载新的二进制文件，返回重启结果
+func (a *App) restartIfRunning(origin EventOrigin, name string) string {
+	if a.processManager == nil || !a.processManager.IsRunning(name) {
+		return ""
+	}
+	return a.restartService(origin, name, a.restartMode())
+}
+
+// recordInstall 记录组件安装事件
+func (a *App) recordInstall(origin EventOrigin, result *InstallResult) {
+	err := a.journal.Record(JournalEvent{
+		Type:    EventInstall,
+		Service: result.Component,
+		Actor:   origin.Actor,
+		Reason:  origin.Reason,
+		Details: fmt.Sprintf("version %s (sha256 %s)", result.Version, result.SHA256),
+	})
+	if err != nil {
+		a.keepUnrecordedError(fmt.Sprintf("failed to record install of '%s': %v", result.Component, err))
+	}
+}
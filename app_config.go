@@ -1,6 +1,23 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 发送给前端的配置事件
+const (
+	ConfigChangedEvent          = "config:changed"          // 配置变更，数据为变更的配置段名称
+	ConfigExternalChangeEvent   = "config:external-change"  // 配置文件被外部修改，数据为 ExternalConfigChange
+	ServiceRestartRequiredEvent = "config:restart-required" // 正在运行的服务需要重启才能使用新配置，数据为服务到变化配置段的映射
+)
+
+// configWatchInterval 检查配置文件外部修改的间隔
+const configWatchInterval = 2 * time.Second
 
 // ===============================
 // 配置管理相关接口
@@ -28,6 +45,7 @@ func (a *App) UpdateGlobalConfig(global GlobalConfig) string {
 	if err != nil {
 		return fmt.Sprintf("Failed to update global config: %v", err)
 	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI}, "global")
 
 	return "Global configuration updated successfully"
 }
@@ -54,6 +72,7 @@ func (a *App) UpdateEduExpConfig(eduexp EduExpConfig) string {
 	if err != nil {
 		return fmt.Sprintf("Failed to update EduExp config: %v", err)
 	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI}, "eduexp")
 
 	return "EduExp configuration updated successfully"
 }
@@ -70,7 +89,7 @@ func (a *App) GetWorkflowConfig() *WorkflowConfig {
 	return &config.Workflow
 }
 
-// UpdateWorkflowConfig 更新工作流配置
+// UpdateWorkflowConfig 更新工作流配置中的 API Key 和端口，工作流定义通过 AddWorkflow、UpdateWorkflow 等接口修改
 func (a *App) UpdateWorkflowConfig(workflow WorkflowConfig) string {
 	if a.configManager == nil {
 		return "Configuration manager not initialized"
@@ -80,6 +99,7 @@ func (a *App) UpdateWorkflowConfig(workflow WorkflowConfig) string {
 	if err != nil {
 		return fmt.Sprintf("Failed to update workflow config: %v", err)
 	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI}, "workflow")
 
 	return "Workflow configuration updated successfully"
 }
@@ -106,6 +126,7 @@ func (a *App) UpdateLicenseConfig(license LicenseConfig) string {
 	if err != nil {
 		return fmt.Sprintf("Failed to update license config: %v", err)
 	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI}, "license")
 
 	return "License configuration updated successfully"
 }
@@ -128,10 +149,85 @@ func (a *App) ResetConfigToDefault() string {
 	if err != nil {
 		return fmt.Sprintf("Failed to reset config to default: %v", err)
 	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI, Reason: "reset to default"}, "all")
 
 	return "Configuration reset to default successfully"
 }
 
+// ValidateConfig 校验配置但不保存，前端可在保存前检查字段错误
+func (a *App) ValidateConfig(config Config) []ValidationError {
+	return append([]ValidationError{}, ValidateConfig(&config)...)
+}
+
+// GetConfigProblems 获取当前配置中的校验错误，用于提示配置文件中手动编辑导致的问题
+func (a *App) GetConfigProblems() []ValidationError {
+	if a.configManager == nil {
+		return []ValidationError{}
+	}
+	return append([]ValidationError{}, a.configManager.Problems()...)
+}
+
+// GetConfigSources 获取每个配置项当前生效的值及其来源（默认值、配置文件、环境变量或命令行参数）
+func (a *App) GetConfigSources() []ConfigValueSource {
+	if a.configManager == nil {
+		return []ConfigValueSource{}
+	}
+	return a.configManager.Sources()
+}
+
+// watchConfigChanges 订阅配置变更：通知前端，并将认证相关的变更立即应用到网关；
+// 同时监视配置文件的外部修改
+func (a *App) watchConfigChanges() {
+	if a.configManager == nil {
+		return
+	}
+
+	a.configManager.OnExternalChange(func(change ExternalConfigChange) {
+		reason := "config file edited externally"
+		if len(change.Conflicts) > 0 {
+			reason = "config file edit conflicted with in-app changes: " + strings.Join(change.Conflicts, ", ")
+		} else if change.Error != "" {
+			reason = "invalid external config edit: " + change.Error
+		}
+		a.recordConfigUpdate(EventOrigin{Actor: ActorSystem, Reason: reason}, strings.Join(change.Sections, ","))
+		wailsruntime.EventsEmit(a.ctx, ConfigExternalChangeEvent, change)
+	})
+	a.stopConfigWatch = a.configManager.Watch(configWatchInterval)
+
+	a.configManager.Subscribe(ConfigSectionAll, func(change ConfigChange) {
+		wailsruntime.EventsEmit(a.ctx, ConfigChangedEvent, change.Section)
+	})
+
+	// 令牌、密码和用户数上限变化后立即生效
+	applyAuth := func(ConfigChange) {
+		a.gateway.SetAuth(a.gatewayAuth())
+	}
+	a.configManager.Subscribe("lan", applyAuth)
+	a.configManager.Subscribe("license", applyAuth)
+
+	// 启用或停用网关后更新服务对网关的依赖
+	a.configManager.Subscribe("gateway", func(ConfigChange) {
+		a.applyGatewayDependencies()
+	})
+
+	// 端口、监听地址和证书等变化：更新未运行服务的网关路由，标记需要重启的服务
+	a.configManager.Subscribe(ConfigSectionAll, func(change ConfigChange) {
+		affected := configSectionServices[change.Section]
+		switch change.Section {
+		case "services":
+			// 服务编排配置中只有监听地址需要重启才能生效
+			affected = changedBindAddresses(change.Previous.Services, change.Config.Services)
+		case "lan":
+			// 令牌、密码等认证配置已直接应用到网关
+			previous, next := change.Previous.LAN, change.Config.LAN
+			if previous.Enabled == next.Enabled && previous.BindAddress == next.BindAddress {
+				affected = nil
+			}
+		}
+		a.applyServiceConfigChange(change.Section, affected)
+	})
+}
+
 // GetConfigFilePath 获取配置文件路径
 func (a *App) GetConfigFilePath() string {
 	if a.configManager == nil {
@@ -155,3 +251,110 @@ func (a *App) GetConfigInfo() map[string]string {
 
 	return info
 }
+
+// ===============================
+// 配置导入导出相关接口
+// ===============================
+
+// ExportConfig 将配置导出为 TOML 或 JSON 文件，用于在多台机器上部署相同的配置
+// 敏感配置项（API Key、局域网令牌和密码）按选项脱敏或使用口令加密
+func (a *App) ExportConfig(path string, options ConfigExportOptions) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	format, err := configFileFormat(path, options.Format)
+	if err != nil {
+		return fmt.Sprintf("Failed to export config: %v", err)
+	}
+	data, err := exportConfig(a.configManager.FileConfig(), options, format, a.resolveSecret)
+	if err != nil {
+		return fmt.Sprintf("Failed to export config: %v", err)
+	}
+
+	// 包含加密密钥的文件只允许当前用户读取
+	perm := os.FileMode(0644)
+	if options.Secrets == SecretExportEncrypt {
+		perm = 0600
+	}
+	if err := writeFileAtomic(path, data, perm); err != nil {
+		return fmt.Sprintf("Failed to export config: %v", err)
+	}
+	return fmt.Sprintf("Configuration exported to %s", path)
+}
+
+// PreviewConfigImport 预览导入配置文件后将发生的变化和校验错误，不修改当前配置
+func (a *App) PreviewConfigImport(path, mode, passphrase string) (*ConfigImportPreview, error) {
+	if a.configManager == nil {
+		return nil, fmt.Errorf("configuration manager not initialized")
+	}
+	_, preview, err := a.prepareConfigImport(path, mode, passphrase)
+	return preview, err
+}
+
+// ImportConfig 导入配置文件，mode 为 merge（合并配置项）或 replace（替换配置段）
+// 导入的配置段存在校验错误时不做修改
+func (a *App) ImportConfig(path, mode, passphrase string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	next, preview, err := a.prepareConfigImport(path, mode, passphrase)
+	if err != nil {
+		return fmt.Sprintf("Failed to import config: %v", err)
+	}
+	if len(preview.Errors) > 0 {
+		return fmt.Sprintf("Failed to import config: %v", ValidationErrors(preview.Errors))
+	}
+	if len(preview.Changes) == 0 {
+		return "Imported configuration is identical to the current configuration"
+	}
+
+	// 导入的明文 API Key 保存到密钥存储
+	for id, value := range configSecretValues(next) {
+		if *value == "" || IsSecretRef(*value) {
+			continue
+		}
+		if err := a.sealSecret(id, value); err != nil {
+			return fmt.Sprintf("Failed to import config: %v", err)
+		}
+	}
+
+	if err := a.configManager.ReplaceConfig(next); err != nil {
+		return fmt.Sprintf("Failed to import config: %v", err)
+	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI, Reason: fmt.Sprintf("%s import from %s", preview.Mode, path)}, strings.Join(preview.Sections, ","))
+
+	return fmt.Sprintf("Configuration imported successfully (%d changes)", len(preview.Changes))
+}
+
+// prepareConfigImport 读取导入文件，返回导入后的配置和预览
+func (a *App) prepareConfigImport(path, mode, passphrase string) (*Config, *ConfigImportPreview, error) {
+	format, err := configFileFormat(path, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read import file: %v", err)
+	}
+
+	current := a.configManager.FileConfig()
+	next, preview, err := prepareConfigImport(current, data, format, mode, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 与当前值相同的 API Key 保留原引用，避免预览中出现无意义的修改
+	currentValues := configSecretValues(current)
+	for id, value := range configSecretValues(next) {
+		if *value == "" || IsSecretRef(*value) {
+			continue
+		}
+		if plain, err := a.resolveSecret(*currentValues[id]); err == nil && plain == *value {
+			*value = *currentValues[id]
+		}
+	}
+	preview.Changes = diffConfigs(current, next)
+	return next, preview, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+)
+
+// ===============================
+// 配置历史相关接口
+// ===============================
+
+// ListConfigHistory 列出配置历史版本，最新的版本在前
+func (a *App) ListConfigHistory() []ConfigVersion {
+	if a.configManager == nil {
+		return []ConfigVersion{}
+	}
+	versions, err := a.configManager.ListHistory()
+	if err != nil {
+		return []ConfigVersion{}
+	}
+	return versions
+}
+
+// DiffConfigVersions 比较两个配置版本，版本ID为 current 时表示当前配置
+func (a *App) DiffConfigVersions(from, to string) ([]ConfigFieldChange, error) {
+	if a.configManager == nil {
+		return nil, fmt.Errorf("configuration manager not initialized")
+	}
+	return a.configManager.DiffVersions(from, to)
+}
+
+// RestoreConfigVersion 将配置恢复为历史版本，并重启受影响的正在运行的服务
+func (a *App) RestoreConfigVersion(id string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	previous := a.configManager.GetConfig()
+	before := a.resolveConfigSecrets(previous)
+	if err := a.configManager.RestoreVersion(id); err != nil {
+		return fmt.Sprintf("Failed to restore config version: %v", err)
+	}
+
+	// 只恢复了密钥时配置中的引用不变，按解密后的值判断受影响的配置段
+	var secretSections []string
+	for secretID, value := range a.resolveConfigSecrets(a.configManager.GetConfig()) {
+		if value != before[secretID] {
+			secretSections = append(secretSections, secretSection(secretID))
+		}
+	}
+
+	origin := EventOrigin{Actor: ActorUI, Reason: fmt.Sprintf("restored config version %s", id)}
+	return fmt.Sprintf("Configuration restored to version %s", id) + a.afterConfigReplaced(origin, previous, secretSections...)
+}
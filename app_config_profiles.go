@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// configSectionServices 配置段对应的服务，配置段变化后需要重启这些服务才能生效
+// license 和 lan 中的认证配置由订阅者直接应用到网关，lan 的开关和监听地址需要重启网关
+var configSectionServices = map[string][]string{
+	"lan":        {"gateway"},
+	"eduexp":     {"edu-tools"},
+	"workflow":   {"workflowui"},
+	"fileserver": {"fileserver"},
+	"gateway":    {"gateway"},
+	"tls":        {"gateway", "fileserver"},
+	"services":   configServices,
+}
+
+// ===============================
+// 配置档案相关接口
+// ===============================
+
+// ListConfigProfiles 列出所有配置档案
+func (a *App) ListConfigProfiles() []ConfigProfile {
+	if a.configManager == nil {
+		return []ConfigProfile{}
+	}
+	profiles, err := a.configManager.ListProfiles()
+	if err != nil {
+		return []ConfigProfile{}
+	}
+	return profiles
+}
+
+// GetActiveConfigProfile 获取当前配置档案名称
+func (a *App) GetActiveConfigProfile() string {
+	if a.configManager == nil {
+		return ""
+	}
+	return a.configManager.ActiveProfile()
+}
+
+// CreateConfigProfile 使用默认配置创建配置档案
+func (a *App) CreateConfigProfile(name string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+	if a.configManager.ProfileExists(name) {
+		return fmt.Sprintf("Failed to create profile: profile '%s' already exists", name)
+	}
+
+	if err := a.configManager.SaveProfile(name, GetDefaultConfig()); err != nil {
+		return fmt.Sprintf("Failed to create profile: %v", err)
+	}
+	return fmt.Sprintf("Profile '%s' created successfully", name)
+}
+
+// CloneConfigProfile 复制配置档案，密钥一并复制，修改副本不影响原档案
+func (a *App) CloneConfigProfile(source, name string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+	if err := validateProfileName(name); err != nil {
+		return fmt.Sprintf("Failed to clone profile: %v", err)
+	}
+	if a.configManager.ProfileExists(name) {
+		return fmt.Sprintf("Failed to clone profile: profile '%s' already exists", name)
+	}
+
+	config, err := a.configManager.LoadProfile(source)
+	if err != nil {
+		return fmt.Sprintf("Failed to clone profile: %v", err)
+	}
+	if err := a.copyProfileSecrets(config, name); err != nil {
+		return fmt.Sprintf("Failed to clone profile: %v", err)
+	}
+	if err := a.configManager.SaveProfile(name, config); err != nil {
+		a.deleteProfileSecrets(name)
+		return fmt.Sprintf("Failed to clone profile: %v", err)
+	}
+	return fmt.Sprintf("Profile '%s' cloned to '%s' successfully", source, name)
+}
+
+// RenameConfigProfile 重命名配置档案，档案的密钥随之改名
+func (a *App) RenameConfigProfile(oldName, newName string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+	if err := validateProfileName(newName); err != nil {
+		return fmt.Sprintf("Failed to rename profile: %v", err)
+	}
+	if a.configManager.ProfileExists(newName) {
+		return fmt.Sprintf("Failed to rename profile: profile '%s' already exists", newName)
+	}
+
+	config, err := a.configManager.LoadProfile(oldName)
+	if err != nil {
+		return fmt.Sprintf("Failed to rename profile: %v", err)
+	}
+	if err := a.copyProfileSecrets(config, newName); err != nil {
+		return fmt.Sprintf("Failed to rename profile: %v", err)
+	}
+	if err := a.configManager.SaveProfile(oldName, config); err != nil {
+		a.deleteProfileSecrets(newName)
+		return fmt.Sprintf("Failed to rename profile: %v", err)
+	}
+	if err := a.configManager.RenameProfile(oldName, newName); err != nil {
+		return fmt.Sprintf("Failed to rename profile: %v", err)
+	}
+	a.deleteProfileSecrets(oldName)
+	return fmt.Sprintf("Profile '%s' renamed to '%s' successfully", oldName, newName)
+}
+
+// DeleteConfigProfile 删除配置档案及其密钥，不能删除当前档案
+func (a *App) DeleteConfigProfile(name string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+	if err := a.configManager.DeleteProfile(name); err != nil {
+		return fmt.Sprintf("Failed to delete profile: %v", err)
+	}
+	a.deleteProfileSecrets(name)
+	return fmt.Sprintf("Profile '%s' deleted successfully", name)
+}
+
+// ActivateConfigProfile 切换配置档案，并重启受配置变化影响的正在运行的服务
+func (a *App) ActivateConfigProfile(name string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	previous := a.configManager.GetConfig()
+	if err := a.configManager.ActivateProfile(name); err != nil {
+		return fmt.Sprintf("Failed to activate profile: %v", err)
+	}
+	a.sealConfigSecrets()
+
+	origin := EventOrigin{Actor: ActorUI, Reason: fmt.Sprintf("activated config profile '%s'", name)}
+	return fmt.Sprintf("Profile '%s' activated successfully", name) + a.afterConfigReplaced(origin, previous)
+}
+
+// afterConfigReplaced 记录整体替换配置（切换档案、恢复历史版本）的操作，
+// 并重启受配置变化影响的正在运行的服务，返回重启结果；secretSections 为引用不变、密钥值变化的配置段
+func (a *App) afterConfigReplaced(origin EventOrigin, previous *Config, secretSections ...string) string {
+	sections := mergeSections(changedSections(previous, a.configManager.GetConfig()), secretSections)
+	a.recordConfigUpdate(origin, strings.Join(sections, ","))
+
+	message := ""
+	for _, service := range affectedServices(sections) {
+		if restart := a.restartIfRunning(origin, service); restart != "" {
+			message += "\n" + restart
+		}
+	}
+	return message
+}
+
+// affectedServices 返回受配置段变化影响的服务，按启动顺序排列
+func affectedServices(sections []string) []string {
+	affected := make(map[string]bool)
+	for _, section := range sections {
+		for _, service := range configSectionServices[section] {
+			affected[service] = true
+		}
+	}
+
+	var services []string
+	for _, service := range configServices {
+		if affected[service] {
+			services = append(services, service)
+		}
+	}
+	return services
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StartupDiagnostics 启动诊断信息，用于提示启动时发生的配置恢复和初始化失败
+type StartupDiagnostics struct {
+	ConfigFile     string            // 使用的配置文件
+	ConfigRecovery *ConfigRecovery   // 配置文件损坏时的恢复结果，未损坏时为空
+	ConfigProblems []ValidationError // 当前配置中的校验错误
+	Errors         []string          // 初始化失败的组件及原因
+	Unrecorded     []string          // 事件日志不可用时发生的后台错误
+}
+
+// ===============================
+// 启动诊断相关接口
+// ===============================
+
+// GetStartupDiagnostics 获取启动诊断信息：配置文件损坏时恢复了哪些配置段、丢失了哪些配置段，初始化失败的组件，以及无法写入事件日志的错误
+func (a *App) GetStartupDiagnostics() StartupDiagnostics {
+	diagnostics := StartupDiagnostics{
+		ConfigProblems: []ValidationError{},
+		Errors:         append([]string{}, a.startupErrors...),
+	}
+	a.errorsMu.Lock()
+	diagnostics.Unrecorded = append([]string{}, a.lostErrors...)
+	a.errorsMu.Unlock()
+	if a.configManager != nil {
+		diagnostics.ConfigFile = a.configManager.GetConfigFile()
+		diagnostics.ConfigRecovery = a.configManager.Recovery()
+		diagnostics.ConfigProblems = append(diagnostics.ConfigProblems, a.configManager.Problems()...)
+	}
+	return diagnostics
+}
+
+// recordConfigRecovery 将启动时的配置文件恢复记录到事件日志
+func (a *App) recordConfigRecovery() {
+	if a.configManager == nil {
+		return
+	}
+	recovery := a.configManager.Recovery()
+	if recovery == nil {
+		return
+	}
+
+	lost := make([]string, 0, len(recovery.Lost))
+	for _, loss := range recovery.Lost {
+		section := loss.Section
+		if section == "" {
+			section = "(top level)"
+		}
+		lost = append(lost, section)
+	}
+	reason := fmt.Sprintf("recovered corrupted config file (backup %s); reset to defaults: %s", recovery.BackupFile, strings.Join(lost, ", "))
+	a.recordConfigUpdate(EventOrigin{Actor: ActorSystem, Reason: reason}, strings.Join(lost, ","))
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"net"
+)
+
+// ===============================
+// 本地网关相关接口
+// ===============================
+
+// GatewayRouteInfo 网关路由及其当前状态
+type GatewayRouteInfo struct {
+	Prefix  string // 路径前缀或子域名
+	Service string // 转发到的服务
+	URL     string // 通过网关访问服务的地址
+	Target  string // 当前转发的服务地址，服务未启动过时为空
+	Running bool   // 服务是否正在运行
+}
+
+// GatewayInfo 网关状态
+type GatewayInfo struct {
+	Enabled bool               // 是否在应用启动时启动
+	Running bool               // 是否正在运行
+	BaseURL string             // 网关地址，前端只需使用该地址访问所有服务
+	Routing string             // 路由方式
+	Routes  []GatewayRouteInfo // 路由列表
+}
+
+// GetGatewayConfig 获取网关配置
+func (a *App) GetGatewayConfig() *GatewayConfig {
+	if a.configManager == nil {
+		return nil
+	}
+	config := a.configManager.GetConfig()
+	if config == nil {
+		return nil
+	}
+	return &config.Gateway
+}
+
+// UpdateGatewayConfig 更新网关配置，网关正在运行时使用新配置重启
+func (a *App) UpdateGatewayConfig(gateway GatewayConfig) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	err := a.configManager.UpdateGatewayConfig(gateway)
+	if err != nil {
+		return fmt.Sprintf("Failed to update gateway config: %v", err)
+	}
+	origin := EventOrigin{Actor: ActorUI, Reason: "gateway config updated"}
+	a.recordConfigUpdate(origin, "gateway")
+
+	message := "Gateway configuration updated successfully"
+	if restart := a.restartIfRunning(origin, "gateway"); restart != "" {
+		message += "\n" + restart
+	}
+	return message
+}
+
+// StartGateway 启动本地网关
+func (a *App) StartGateway() string {
+	return a.startGateway(EventOrigin{Actor: ActorUI})
+}
+
+// StopGateway 停止本地网关
+func (a *App) StopGateway() string {
+	return a.StopProcess("gateway")
+}
+
+// GetGatewayInfo 获取网关状态和各服务的访问地址
+func (a *App) GetGatewayInfo() GatewayInfo {
+	gateway := a.effectiveGatewayConfig()
+	info := GatewayInfo{
+		Enabled: gateway.Enabled,
+		Running: a.processManager != nil && a.processManager.IsRunning("gateway"),
+		BaseURL: a.serviceScheme() + "://localhost:" + gateway.Port,
+		Routing: gateway.Routing,
+		Routes:  make([]GatewayRouteInfo, 0, len(gatewayRoutes)),
+	}
+
+	for _, route := range gatewayRoutes {
+		info.Routes = append(info.Routes, GatewayRouteInfo{
+			Prefix:  route.Prefix,
+			Service: route.Service,
+			URL:     gatewayRouteURL(a.serviceScheme(), gateway, route),
+			Target:  a.gateway.Target(route.Service),
+			Running: a.processManager != nil && a.processManager.IsRunning(route.Service),
+		})
+	}
+	return info
+}
+
+// GetServiceURL 获取服务的访问地址，网关运行时返回网关地址，否则返回服务直连地址
+func (a *App) GetServiceURL(service string) string {
+	gateway := a.effectiveGatewayConfig()
+	if a.processManager != nil && a.processManager.IsRunning("gateway") {
+		for _, route := range gatewayRoutes {
+			if route.Service == service {
+				return gatewayRouteURL(a.serviceScheme(), gateway, route)
+			}
+		}
+	}
+
+	// 文件服务器直连时同样使用 HTTPS
+	scheme := "http"
+	if service == "fileserver" {
+		scheme = a.serviceScheme()
+	}
+	if target := a.gateway.Target(service); target != "" {
+		if _, port, err := net.SplitHostPort(target); err == nil {
+			return scheme + "://localhost:" + port
+		}
+	}
+	return scheme + "://localhost:" + a.configuredPort(service)
+}
+
+// startGatewayIfEnabled 按配置在应用启动时启动网关
+func (a *App) startGatewayIfEnabled() {
+	if !a.effectiveGatewayConfig().Enabled {
+		return
+	}
+	a.startGateway(EventOrigin{Actor: ActorSystem, Reason: "application startup"})
+}
+
+// startGateway 以指定来源启动网关，局域网模式下监听局域网地址并启用认证
+func (a *App) startGateway(origin EventOrigin) string {
+	gateway := a.effectiveGatewayConfig()
+	host := a.bindAddress("gateway")
+	if lan := a.effectiveLANConfig(); lan.Enabled {
+		host = lan.BindAddress
+	}
+
+	args := []string{"--listen", net.JoinHostPort(host, gateway.Port)}
+	tlsArgs, err := a.tlsArgs("gateway")
+	if err != nil {
+		return fmt.Sprintf("Failed to start process 'gateway': %v", err)
+	}
+	if len(tlsArgs) > 0 {
+		args = append(append(args, tlsArgs...), "--ca-cert", a.certAuthority.caCertPath())
+	}
+
+	a.gateway.SetAuth(a.gatewayAuth())
+	return a.startProcessAs(origin, "gateway", args...)
+}
+
+// updateGatewayTarget 服务启动后将网关路由切换到服务实际监听的端口
+func (a *App) updateGatewayTarget(processName string, args []string) {
+	for _, route := range gatewayRoutes {
+		if route.Service != processName {
+			continue
+		}
+		if port := listenPort(args); port != "" {
+			a.gateway.SetTarget(processName, net.JoinHostPort(dialHost(a.bindAddress(processName)), port))
+		}
+		return
+	}
+}
+
+// applyGatewayDependencies 启用网关时，通过网关访问的服务依赖网关，按服务组启动时先启动网关
+func (a *App) applyGatewayDependencies() {
+	var dependsOn []string
+	if a.effectiveGatewayConfig().Enabled {
+		dependsOn = []string{"gateway"}
+	}
+	for _, route := range gatewayRoutes {
+		a.processManager.SetDependencies(route.Service, dependsOn)
+	}
+}
+
+// effectiveGatewayConfig 获取补全默认值后的网关配置
+func (a *App) effectiveGatewayConfig() GatewayConfig {
+	defaults := GetDefaultConfig().Gateway
+	gateway := defaults
+	if current := a.GetGatewayConfig(); current != nil {
+		gateway = *current
+	}
+	if gateway.Port == "" {
+		gateway.Port = defaults.Port
+	}
+	if gateway.Routing == "" {
+		gateway.Routing = defaults.Routing
+	}
+	return gateway
+}
+
+// configuredPort 获取配置中服务的端口
+func (a *App) configuredPort(service string) string {
+	defaults := GetDefaultConfig()
+	config := defaults
+	if a.configManager != nil && a.configManager.GetConfig() != nil {
+		config = a.configManager.GetConfig()
+	}
+
+	port := ""
+	switch service {
+	case "workflowui":
+		port = config.Workflow.WorkflowUIPort
+		if port == "" {
+			port = defaults.Workflow.WorkflowUIPort
+		}
+	case "edu-tools":
+		port = config.EduExp.EduToolsPort
+		if port == "" {
+			port = defaults.EduExp.EduToolsPort
+		}
+	case "fileserver":
+		port = config.FileServer.Port
+		if port == "" {
+			port = defaults.FileServer.Port
+		}
+	case "gateway":
+		port = a.effectiveGatewayConfig().Port
+	}
+	return port
+}
+
+// gatewayRouteURL 生成通过网关访问服务的地址
+func gatewayRouteURL(scheme string, gateway GatewayConfig, route GatewayRoute) string {
+	if gateway.Routing == GatewayRoutingSubdomain {
+		return fmt.Sprintf("%s://%s.localhost:%s/", scheme, route.Prefix, gateway.Port)
+	}
+	return fmt.Sprintf("%s://localhost:%s/%s/", scheme, gateway.Port, route.Prefix)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+)
+
+// maxUnrecordedErrors 内存中保留的无法写入事件日志的错误数量
+const maxUnrecordedErrors = 100
+
+// ===============================
+// 事件日志相关接口
+// ===============================
+
+// QueryEventJournal 查询生命周期和配置变更事件
+func (a *App) QueryEventJournal(query EventQuery) EventQueryResult {
+	if a.journal == nil {
+		return EventQueryResult{Events: []JournalEvent{}, Error: "Event journal not initialized"}
+	}
+
+	result, err := a.journal.Query(query)
+	if err != nil {
+		result.Error = fmt.Sprintf("Failed to query event journal: %v", err)
+	}
+	return result
+}
+
+// recordConfigUpdate 记录配置变更事件
+func (a *App) recordConfigUpdate(origin EventOrigin, section string) {
+	err := a.journal.Record(JournalEvent{
+		Type:    EventConfigUpdate,
+		Actor:   origin.Actor,
+		Reason:  origin.Reason,
+		Details: section,
+	})
+	if err != nil {
+		a.keepUnrecordedError(fmt.Sprintf("failed to record config update for '%s': %v", section, err))
+	}
+}
+
+// reportError 将后台操作的失败记录到事件日志，事件日志不可用时保留在启动诊断中
+func (a *App) reportError(service string, err error) {
+	if a.journal == nil {
+		a.keepUnrecordedError(err.Error())
+		return
+	}
+	recordErr := a.journal.Record(JournalEvent{
+		Type:    EventError,
+		Service: service,
+		Actor:   ActorSystem,
+		Reason:  err.Error(),
+	})
+	if recordErr != nil {
+		a.keepUnrecordedError(fmt.Sprintf("%v (failed to record: %v)", err, recordErr))
+	}
+}
+
+// keepUnrecordedError 保留无法写入事件日志的错误，只保留最近的 maxUnrecordedErrors 条
+func (a *App) keepUnrecordedError(message string) {
+	a.errorsMu.Lock()
+	defer a.errorsMu.Unlock()
+	a.lostErrors = append(a.lostErrors, message)
+	if len(a.lostErrors) > maxUnrecordedErrors {
+		a.lostErrors = a.lostErrors[len(a.lostErrors)-maxUnrecordedErrors:]
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// ===============================
+// 局域网共享相关接口
+// ===============================
+
+// defaultLANMaxClients 默认同时在线的局域网设备数上限，足够一个班级使用
+const defaultLANMaxClients = 60
+
+// LANURL 局域网访问地址
+type LANURL struct {
+	Address string // 本机的局域网 IP
+	Service string // 服务名称
+	URL     string // 访问地址，令牌认证时包含令牌
+	QRCode  string // 访问地址的二维码（PNG data URL）
+}
+
+// LANAccessInfo 局域网共享状态
+type LANAccessInfo struct {
+	Enabled        bool     // 是否开启局域网模式
+	Running        bool     // 网关是否正在运行
+	AuthMode       string   // 认证方式
+	URLs           []LANURL // 各网卡地址上的服务访问地址
+	ActiveSessions int      // 当前在线的局域网设备数
+	UserLimit      int      // 同时在线的设备数上限，0 表示不限制
+	Message        string   // 说明信息
+}
+
+// GetLANConfig 获取局域网共享配置
+func (a *App) GetLANConfig() *LANConfig {
+	if a.configManager == nil {
+		return nil
+	}
+	config := a.configManager.GetConfig()
+	if config == nil {
+		return nil
+	}
+	return &config.LAN
+}
+
+// UpdateLANConfig 更新局域网共享配置，并使用新配置重启网关
+func (a *App) UpdateLANConfig(lan LANConfig) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	// 开启令牌认证时自动生成令牌
+	if lan.Enabled && (lan.AuthMode == "" || lan.AuthMode == LANAuthToken) && lan.Token == "" {
+		lan.Token = randomHex(16)
+	}
+
+	err := a.configManager.UpdateLANConfig(lan)
+	if err != nil {
+		return fmt.Sprintf("Failed to update LAN config: %v", err)
+	}
+	origin := EventOrigin{Actor: ActorUI, Reason: "LAN config updated"}
+	a.recordConfigUpdate(origin, "lan")
+
+	message := "LAN configuration updated successfully"
+	if restart := a.restartIfRunning(origin, "gateway"); restart != "" {
+		message += "\n" + restart
+	} else if lan.Enabled {
+		// 局域网访问依赖网关
+		message += "\n" + a.startGateway(origin)
+	}
+	return message
+}
+
+// RegenerateLANToken 重新生成访问令牌，已有的局域网会话全部失效
+func (a *App) RegenerateLANToken() string {
+	lan := a.GetLANConfig()
+	if lan == nil {
+		return "Configuration manager not initialized"
+	}
+
+	updated := *lan
+	updated.Token = randomHex(16)
+	if err := a.configManager.UpdateLANConfig(updated); err != nil {
+		return fmt.Sprintf("Failed to regenerate LAN token: %v", err)
+	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI, Reason: "LAN token regenerated"}, "lan")
+
+	return "LAN token regenerated successfully"
+}
+
+// RevokeLANSessions 注销所有局域网会话
+func (a *App) RevokeLANSessions() string {
+	a.gateway.RevokeSessions()
+	return "All LAN sessions revoked"
+}
+
+// GetLANAccess 获取局域网访问地址及其二维码
+func (a *App) GetLANAccess() LANAccessInfo {
+	lan := a.effectiveLANConfig()
+	auth := a.gatewayAuth()
+	info := LANAccessInfo{
+		Enabled:        lan.Enabled,
+		Running:        a.processManager != nil && a.processManager.IsRunning("gateway"),
+		AuthMode:       auth.Mode,
+		URLs:           []LANURL{},
+		ActiveSessions: a.gateway.ActiveSessions(),
+		UserLimit:      auth.UserLimit,
+	}
+
+	if !lan.Enabled {
+		info.Message = "LAN mode is disabled"
+		return info
+	}
+	if !info.Running {
+		info.Message = "Gateway is not running"
+	} else if auth.UserLimit > 0 && info.ActiveSessions >= auth.UserLimit {
+		info.Message = fmt.Sprintf("%d LAN device(s) are signed in, which is the limit; new devices are refused until one signs out at %s", auth.UserLimit, gatewayLogoutPath)
+	}
+
+	addresses := lanAddresses(lan.BindAddress)
+	if len(addresses) == 0 {
+		info.Message = "No LAN network address found"
+		return info
+	}
+
+	port := a.effectiveGatewayConfig().Port
+	for _, address := range addresses {
+		for _, route := range gatewayRoutes {
+			accessURL := fmt.Sprintf("%s://%s/%s/", a.serviceScheme(), net.JoinHostPort(address, port), route.Prefix)
+			if auth.Mode == LANAuthToken {
+				accessURL += "?" + gatewayTokenParam + "=" + url.QueryEscape(auth.Token)
+			}
+
+			entry := LANURL{Address: address, Service: route.Service, URL: accessURL}
+			if png, err := qrcode.Encode(accessURL, qrcode.Medium, 256); err == nil {
+				entry.QRCode = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+			}
+			info.URLs = append(info.URLs, entry)
+		}
+	}
+	return info
+}
+
+// gatewayAuth 根据局域网配置和许可生成网关认证设置，未开启局域网模式时不认证
+func (a *App) gatewayAuth() GatewayAuth {
+	lan := a.effectiveLANConfig()
+	if !lan.Enabled {
+		return GatewayAuth{}
+	}
+
+	auth := GatewayAuth{
+		Mode:       lan.AuthMode,
+		Token:      lan.Token,
+		Password:   lan.Password,
+		SessionTTL: time.Duration(lan.SessionTTL) * time.Minute,
+		UserLimit:  lan.MaxClients,
+	}
+	// 填写许可证密钥后，许可的用户数量进一步限制在线设备数
+	if a.configManager != nil && a.configManager.GetConfig() != nil {
+		license := a.configManager.GetConfig().License
+		if license.LicenseKey != "" && license.UserLimit > 0 && license.UserLimit < auth.UserLimit {
+			auth.UserLimit = license.UserLimit
+		}
+	}
+	return auth
+}
+
+// effectiveLANConfig 获取补全默认值后的局域网共享配置
+func (a *App) effectiveLANConfig() LANConfig {
+	defaults := GetDefaultConfig().LAN
+	lan := defaults
+	if current := a.GetLANConfig(); current != nil {
+		lan = *current
+	}
+	if lan.BindAddress == "" {
+		lan.BindAddress = defaults.BindAddress
+	}
+	if lan.AuthMode == "" {
+		lan.AuthMode = defaults.AuthMode
+	}
+	if lan.SessionTTL <= 0 {
+		lan.SessionTTL = defaults.SessionTTL
+	}
+	if lan.MaxClients <= 0 {
+		lan.MaxClients = defaults.MaxClients
+	}
+	return lan
+}
+
+// bindAddress 获取服务配置的监听地址，为空时由服务使用自身的默认值
+func (a *App) bindAddress(service string) string {
+	return a.effectiveServicesConfig().BindAddresses[service]
+}
+
+// hostArgs 生成限制服务监听地址的 --host 参数，返回参数和需要提示用户的警告
+// 组件不支持 --host 时无法限制监听地址：开启局域网模式时拒绝启动，避免局域网设备绕过网关认证直接访问
+func (a *App) hostArgs(service string) ([]string, string, error) {
+	bind := a.bindAddress(service)
+	if bind == "" {
+		return nil, "", nil
+	}
+	if a.binaryManager.SupportsFlag(service, "--host") {
+		return []string{"--host", bind}, "", nil
+	}
+	if a.effectiveLANConfig().Enabled {
+		return nil, "", fmt.Errorf("%s does not support --host, so it cannot be restricted to %s and would be reachable from the LAN without gateway authentication; update the component or disable LAN mode", service, bind)
+	}
+	return nil, fmt.Sprintf("WARNING: %s does not support --host, listening on its default address instead of %s", service, bind), nil
+}
+
+// dialHost 根据服务的监听地址返回网关连接服务时使用的地址
+func dialHost(bind string) string {
+	ip := net.ParseIP(bind)
+	if ip == nil || ip.IsUnspecified() {
+		return "127.0.0.1"
+	}
+	return bind
+}
+
+// lanAddresses 获取可供局域网设备访问的本机 IPv4 地址
+// 网关绑定到具体地址时只返回该地址
+func lanAddresses(bind string) []string {
+	if ip := net.ParseIP(bind); ip != nil && !ip.IsUnspecified() {
+		return []string{bind}
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var addresses []string
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			addresses = append(addresses, ipNet.IP.String())
+		}
+	}
+	return addresses
+}
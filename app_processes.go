@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,14 +26,58 @@ type CozeConfig struct {
 
 // StartWorkflowUI 启动 WorkflowUI 进程
 func (a *App) StartWorkflowUI(extraArgs []string) string {
+	return a.startWorkflowUI(EventOrigin{Actor: ActorUI}, extraArgs)
+}
+
+// startWorkflowUI 以指定来源启动 WorkflowUI 进程
+func (a *App) startWorkflowUI(origin EventOrigin, extraArgs []string) string {
+	// 使用配置中的端口
+	port := a.configuredPort("workflowui")
+
+	// 先校验签名，生成启动参数时会执行组件检查版本和参数支持
+	warning, err := a.verifyBeforeStart("workflowui")
+	if err != nil {
+		return fmt.Sprintf("Refused to start process 'workflowui': %v", err)
+	}
+
+	args, argsWarning, err := a.workflowUILaunchArgs(port)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	warning = joinWarnings(warning, argsWarning)
+
+	// 添加额外参数
+	args = append(args, extraArgs...)
+
+	// 启动进程并返回结果
+	result := a.startVerifiedProcess(origin, "workflowui", warning, args...)
+
+	// 如果启动失败，删除生成的 config.json 并添加更多调试信息
+	if !strings.Contains(result, "successfully") {
+		if !a.processManager.IsRunning("workflowui") {
+			os.Remove(filepath.Join(a.appDataDir, "data", "workflowui", "config.json"))
+		}
+		workflowuiDataDir := filepath.Join(a.appDataDir, "data", "workflowui")
+		debugInfo := fmt.Sprintf("\nDEBUG INFO:\n- Executable: %s\n- Working Directory: %s\n- Config File: %s\n- Port: %s\n- Arguments: %v",
+			filepath.Join(a.appDataDir, "bin", a.getExecutableName("workflowui")), workflowuiDataDir,
+			filepath.Join(workflowuiDataDir, "config.json"), port, args)
+		result += debugInfo
+	}
+
+	return result
+}
+
+// workflowUILaunchArgs 检查可执行文件并生成 config.json，返回在指定端口启动 WorkflowUI 的参数和需要提示用户的警告
+// config.json 在 WorkflowUI 的所有实例退出后删除
+func (a *App) workflowUILaunchArgs(port string) ([]string, string, error) {
 	// 从配置管理器获取配置
 	if a.configManager == nil {
-		return "ERROR: Configuration manager not initialized"
+		return nil, "", fmt.Errorf("Configuration manager not initialized")
 	}
 
 	config := a.configManager.GetConfig()
 	if config == nil {
-		return "ERROR: Failed to get configuration - please check your configuration file"
+		return nil, "", fmt.Errorf("Failed to get configuration - please check your configuration file")
 	}
 
 	// 获取 workflowui 的数据目录
@@ -40,13 +85,7 @@ func (a *App) StartWorkflowUI(extraArgs []string) string {
 
 	// 确保数据目录存在
 	if err := os.MkdirAll(workflowuiDataDir, 0755); err != nil {
-		return fmt.Sprintf("ERROR: Failed to create workflowui data directory '%s': %v", workflowuiDataDir, err)
-	}
-
-	// 生成 config.json 文件
-	configFile := filepath.Join(workflowuiDataDir, "config.json")
-	if err := a.generateWorkflowUIConfig(configFile, config); err != nil {
-		return fmt.Sprintf("ERROR: Failed to generate config.json file '%s': %v", configFile, err)
+		return nil, "", fmt.Errorf("Failed to create workflowui data directory '%s': %v", workflowuiDataDir, err)
 	}
 
 	// 检查 workflowui 可执行文件是否存在
@@ -54,43 +93,44 @@ func (a *App) StartWorkflowUI(extraArgs []string) string {
 	workflowuiExe := a.getExecutableName("workflowui")
 	workflowuiPath := filepath.Join(binDir, workflowuiExe)
 	if _, err := os.Stat(workflowuiPath); os.IsNotExist(err) {
-		return fmt.Sprintf("ERROR: WorkflowUI executable not found at '%s'. Please ensure the workflowui binary is installed in the bin directory", workflowuiPath)
+		return nil, "", fmt.Errorf("WorkflowUI executable not found at '%s'. Please install the workflowui component or copy the binary into the bin directory", workflowuiPath)
+	}
+
+	// 检查组件版本是否兼容
+	if err := a.binaryManager.CheckCompatibility("workflowui"); err != nil {
+		return nil, "", err
 	}
 
 	// 构建启动参数
 	args := []string{
 		"--config", "config.json", // 使用相对路径，因为工作目录就是数据目录
+		"--port", port,
 	}
-
-	// 使用配置中的端口
-	port := config.Workflow.WorkflowUIPort
-	if port == "" {
-		port = "8080" // 默认端口
+	hostArgs, warning, err := a.hostArgs("workflowui")
+	if err != nil {
+		return nil, "", err
 	}
-	args = append(args, "--port", port)
 
-	// 添加额外参数
-	args = append(args, extraArgs...)
-
-	// 启动进程并返回结果
-	result := a.StartProcess("workflowui", args...)
-
-	// 如果启动失败，添加更多调试信息
-	if !strings.Contains(result, "successfully") {
-		debugInfo := fmt.Sprintf("\nDEBUG INFO:\n- Executable: %s\n- Working Directory: %s\n- Config File: %s\n- Port: %s\n- Arguments: %v",
-			workflowuiPath, workflowuiDataDir, configFile, port, args)
-		result += debugInfo
+	// 最后生成包含明文 API Key 的 config.json，检查失败时不留下文件
+	configFile := filepath.Join(workflowuiDataDir, "config.json")
+	if err := a.generateWorkflowUIConfig(configFile, config); err != nil {
+		return nil, "", fmt.Errorf("Failed to generate config.json file '%s': %v", configFile, err)
 	}
-
-	return result
+	return append(args, hostArgs...), warning, nil
 }
 
 // generateWorkflowUIConfig 生成 WorkflowUI 的配置文件
 func (a *App) generateWorkflowUIConfig(configFile string, config *Config) error {
+	// 启动时才解密 API Key
+	apiKey, err := a.resolveSecret(config.Workflow.ApiKey)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt API key: %v", err)
+	}
+
 	// 转换配置格式
 	workflowUIConfig := WorkflowUIConfig{
 		Coze: CozeConfig{
-			APIKey:    config.Workflow.ApiKey,
+			APIKey:    apiKey,
 			Workflows: make(map[string]interface{}),
 		},
 	}
@@ -111,8 +151,8 @@ func (a *App) generateWorkflowUIConfig(configFile string, config *Config) error
 		return fmt.Errorf("failed to marshal config: %v", err)
 	}
 
-	// 写入文件
-	if err := os.WriteFile(configFile, data, 0644); err != nil {
+	// 写入文件，文件中包含明文 API Key，仅当前用户可读
+	if err := writeFileAtomic(configFile, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %v", err)
 	}
 
@@ -136,14 +176,52 @@ func (a *App) GetWorkflowUIOutput() string {
 
 // StartEduTools 启动 EduTools 进程
 func (a *App) StartEduTools(extraArgs []string) string {
+	return a.startEduTools(EventOrigin{Actor: ActorUI}, extraArgs)
+}
+
+// startEduTools 以指定来源启动 EduTools 进程
+func (a *App) startEduTools(origin EventOrigin, extraArgs []string) string {
+	// 使用配置中的端口
+	port := a.configuredPort("edu-tools")
+
+	// 先校验签名，生成启动参数时会执行组件检查版本和参数支持
+	warning, err := a.verifyBeforeStart("edu-tools")
+	if err != nil {
+		return fmt.Sprintf("Refused to start process 'edu-tools': %v", err)
+	}
+
+	args, argsWarning, err := a.eduToolsLaunchArgs(port)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	warning = joinWarnings(warning, argsWarning)
+
+	// 添加额外参数
+	args = append(args, extraArgs...)
+
+	// 启动进程并返回结果
+	result := a.startVerifiedProcess(origin, "edu-tools", warning, args...)
+
+	// 如果启动失败，添加更多调试信息
+	if !strings.Contains(result, "successfully") {
+		debugInfo := fmt.Sprintf("\nDEBUG INFO:\n- Executable: %s\n- Working Directory: %s\n- Port: %s\n- Arguments: %v",
+			filepath.Join(a.appDataDir, "bin", a.getExecutableName("edu-tools")),
+			filepath.Join(a.appDataDir, "data", "edu-tools"), port, args)
+		result += debugInfo
+	}
+
+	return result
+}
+
+// eduToolsLaunchArgs 检查可执行文件和数据目录，返回在指定端口启动 EduTools 的参数和需要提示用户的警告
+func (a *App) eduToolsLaunchArgs(port string) ([]string, string, error) {
 	// 从配置管理器获取配置
 	if a.configManager == nil {
-		return "ERROR: Configuration manager not initialized"
+		return nil, "", fmt.Errorf("Configuration manager not initialized")
 	}
 
-	config := a.configManager.GetConfig()
-	if config == nil {
-		return "ERROR: Failed to get configuration - please check your configuration file"
+	if a.configManager.GetConfig() == nil {
+		return nil, "", fmt.Errorf("Failed to get configuration - please check your configuration file")
 	}
 
 	// 检查 edu-tools 可执行文件是否存在
@@ -151,37 +229,27 @@ func (a *App) StartEduTools(extraArgs []string) string {
 	eduToolsExe := a.getExecutableName("edu-tools")
 	eduToolsPath := filepath.Join(binDir, eduToolsExe)
 	if _, err := os.Stat(eduToolsPath); os.IsNotExist(err) {
-		return fmt.Sprintf("ERROR: EduTools executable not found at '%s'. Please ensure the edu-tools binary is installed in the bin directory", eduToolsPath)
+		return nil, "", fmt.Errorf("EduTools executable not found at '%s'. Please install the edu-tools component or copy the binary into the bin directory", eduToolsPath)
+	}
+
+	// 检查组件版本是否兼容
+	if err := a.binaryManager.CheckCompatibility("edu-tools"); err != nil {
+		return nil, "", err
 	}
 
 	// 确保数据目录存在
 	eduToolsDataDir := filepath.Join(a.appDataDir, "data", "edu-tools")
 	if err := os.MkdirAll(eduToolsDataDir, 0755); err != nil {
-		return fmt.Sprintf("ERROR: Failed to create edu-tools data directory '%s': %v", eduToolsDataDir, err)
-	}
-
-	// 构建启动参数 - 使用配置中的端口
-	port := config.EduExp.EduToolsPort
-	if port == "" {
-		port = "8080" // 默认端口
+		return nil, "", fmt.Errorf("Failed to create edu-tools data directory '%s': %v", eduToolsDataDir, err)
 	}
 
+	// 构建启动参数
 	args := []string{"--port", port}
-
-	// 添加额外参数
-	args = append(args, extraArgs...)
-
-	// 启动进程并返回结果
-	result := a.StartProcess("edu-tools", args...)
-
-	// 如果启动失败，添加更多调试信息
-	if !strings.Contains(result, "successfully") {
-		debugInfo := fmt.Sprintf("\nDEBUG INFO:\n- Executable: %s\n- Working Directory: %s\n- Port: %s\n- Arguments: %v",
-			eduToolsPath, eduToolsDataDir, port, args)
-		result += debugInfo
+	hostArgs, warning, err := a.hostArgs("edu-tools")
+	if err != nil {
+		return nil, "", err
 	}
-
-	return result
+	return append(args, hostArgs...), warning, nil
 }
 
 // StopEduTools 停止 EduTools 进程
@@ -203,22 +271,54 @@ func (a *App) GetEduToolsOutput() string {
 // 兼容性方法：保持与原有API的兼容
 // ===============================
 
-// StartGinServer 启动服务（兼容旧接口，默认启动caddy-fileserver）
+// StartGinServer 启动服务（兼容旧接口，启动内置文件服务器）
 func (a *App) StartGinServer(port string) string {
-	return a.StartProcess("caddy-fileserver", "--listen", "0.0.0.0:"+port)
+	return a.startGinServer(EventOrigin{Actor: ActorUI}, port)
+}
+
+// startGinServer 以指定来源启动文件服务器，其余选项从文件服务器配置中读取
+func (a *App) startGinServer(origin EventOrigin, port string) string {
+	fileServer := GetDefaultConfig().FileServer
+	if a.configManager != nil {
+		if config := a.configManager.GetConfig(); config != nil {
+			fileServer = config.FileServer
+		}
+	}
+
+	root := fileServer.Root
+	if root == "" {
+		root = filepath.Join(a.appDataDir, "data", "files")
+	}
+
+	args := []string{"--listen", net.JoinHostPort(a.bindAddress("fileserver"), port), "--root", root}
+	if fileServer.Browse {
+		args = append(args, "--browse")
+	}
+	if fileServer.Gzip {
+		args = append(args, "--gzip")
+	}
+	if fileServer.AccessLog {
+		args = append(args, "--access-log")
+	}
+	tlsArgs, err := a.tlsArgs("fileserver")
+	if err != nil {
+		return fmt.Sprintf("Failed to start process 'fileserver': %v", err)
+	}
+	args = append(args, tlsArgs...)
+	return a.startProcessAs(origin, "fileserver", args...)
 }
 
 // StopGinServer 停止服务（兼容旧接口）
 func (a *App) StopGinServer() string {
-	return a.StopProcess("caddy-fileserver")
+	return a.StopProcess("fileserver")
 }
 
 // GetServerStatus 获取状态（兼容旧接口）
 func (a *App) GetServerStatus() string {
-	return a.GetProcessStatus("caddy-fileserver")
+	return a.GetProcessStatus("fileserver")
 }
 
 // GetServerOutput 获取输出日志（兼容旧接口）
 func (a *App) GetServerOutput() string {
-	return a.GetProcessOutput("caddy-fileserver")
+	return a.GetProcessOutput("fileserver")
 }
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ===============================
+// 服务组管理相关接口
+// ===============================
+
+// GetServicesConfig 获取服务编排配置
+func (a *App) GetServicesConfig() *ServicesConfig {
+	if a.configManager == nil {
+		return nil
+	}
+	config := a.configManager.GetConfig()
+	if config == nil {
+		return nil
+	}
+	return &config.Services
+}
+
+// UpdateServicesConfig 更新服务编排配置
+func (a *App) UpdateServicesConfig(services ServicesConfig) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	err := a.configManager.UpdateServicesConfig(services)
+	if err != nil {
+		return fmt.Sprintf("Failed to update services config: %v", err)
+	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI}, "services")
+
+	return "Services configuration updated successfully"
+}
+
+// GetServiceProfiles 获取所有服务组
+func (a *App) GetServiceProfiles() map[string]ServiceProfile {
+	services := a.GetServicesConfig()
+	if services == nil || services.Profiles == nil {
+		return map[string]ServiceProfile{}
+	}
+	return services.Profiles
+}
+
+// StartProfile 按依赖顺序启动服务组中的所有服务
+func (a *App) StartProfile(profileName string) ProfileResult {
+	result := ProfileResult{Profile: profileName, Results: []ServiceResult{}}
+
+	profile, err := a.getServiceProfile(profileName)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	order, err := a.processManager.ResolveStartOrder(profile.Services)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to resolve start order for profile '%s': %v", profileName, err)
+		return result
+	}
+
+	listed := make(map[string]bool, len(profile.Services))
+	for _, name := range profile.Services {
+		listed[name] = true
+	}
+
+	origin := EventOrigin{Actor: ActorProfile, Reason: fmt.Sprintf("start profile '%s'", profileName)}
+	failed := make(map[string]bool)
+	result.Success = true
+	for _, name := range order {
+		serviceResult := ServiceResult{Service: name, IsDependency: !listed[name]}
+
+		config, _ := a.processManager.GetProcessConfig(name)
+		if dep := firstFailed(config.DependsOn, failed); dep != "" {
+			serviceResult.Skipped = true
+			serviceResult.Message = fmt.Sprintf("Skipped because dependency '%s' failed to start", dep)
+		} else {
+			serviceResult.Message = a.startService(origin, name)
+			serviceResult.Success = isStartSuccess(serviceResult.Message)
+		}
+
+		if !serviceResult.Success {
+			failed[name] = true
+			result.Success = false
+		}
+		result.Results = append(result.Results, serviceResult)
+	}
+
+	if result.Success {
+		result.Message = fmt.Sprintf("Profile '%s' started successfully", profileName)
+	} else {
+		result.Message = fmt.Sprintf("Profile '%s' started with %d failed service(s)", profileName, len(failed))
+	}
+	return result
+}
+
+// StopProfile 按依赖的逆序停止服务组中的所有服务
+func (a *App) StopProfile(profileName string) ProfileResult {
+	result := ProfileResult{Profile: profileName, Results: []ServiceResult{}}
+
+	profile, err := a.getServiceProfile(profileName)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	order, err := a.processManager.ResolveStopOrder(profile.Services)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to resolve stop order for profile '%s': %v", profileName, err)
+		return result
+	}
+
+	origin := EventOrigin{Actor: ActorProfile, Reason: fmt.Sprintf("stop profile '%s'", profileName)}
+	result.Success = true
+	for _, name := range order {
+		serviceResult := ServiceResult{Service: name}
+		if !a.processManager.IsRunning(name) {
+			serviceResult.Success = true
+			serviceResult.Skipped = true
+			serviceResult.Message = fmt.Sprintf("Process '%s' is not running", name)
+		} else {
+			serviceResult.Message = a.stopProcessAs(origin, name)
+			serviceResult.Success = strings.Contains(serviceResult.Message, "stopped")
+		}
+
+		if !serviceResult.Success {
+			result.Success = false
+		}
+		result.Results = append(result.Results, serviceResult)
+	}
+
+	if result.Success {
+		result.Message = fmt.Sprintf("Profile '%s' stopped successfully", profileName)
+	} else {
+		result.Message = fmt.Sprintf("Profile '%s' could not be stopped completely", profileName)
+	}
+	return result
+}
+
+// startDefaultProfile 启动配置中的默认服务组
+func (a *App) startDefaultProfile() {
+	services := a.GetServicesConfig()
+	if services == nil || services.DefaultProfile == "" {
+		return
+	}
+	a.StartProfile(services.DefaultProfile)
+}
+
+// getServiceProfile 获取指定服务组
+func (a *App) getServiceProfile(profileName string) (ServiceProfile, error) {
+	if a.processManager == nil {
+		return ServiceProfile{}, fmt.Errorf("Process manager not initialized")
+	}
+	profile, exists := a.GetServiceProfiles()[profileName]
+	if !exists {
+		return ServiceProfile{}, fmt.Errorf("Profile '%s' not found", profileName)
+	}
+	return profile, nil
+}
+
+// startService 使用各服务自己的启动逻辑启动服务
+func (a *App) startService(origin EventOrigin, name string) string {
+	switch name {
+	case "workflowui":
+		return a.startWorkflowUI(origin, nil)
+	case "edu-tools":
+		return a.startEduTools(origin, nil)
+	case "fileserver":
+		port := "8081" // 默认端口
+		if a.configManager != nil {
+			if config := a.configManager.GetConfig(); config != nil && config.FileServer.Port != "" {
+				port = config.FileServer.Port
+			}
+		}
+		return a.startGinServer(origin, port)
+	case "gateway":
+		return a.startGateway(origin)
+	default:
+		return a.startProcessAs(origin, name)
+	}
+}
+
+// isStartSuccess 判断启动结果是否表示服务已在运行
+func isStartSuccess(result string) bool {
+	return strings.Contains(result, "started successfully") || strings.Contains(result, "already running")
+}
+
+// firstFailed 返回第一个启动失败的依赖
+func firstFailed(deps []string, failed map[string]bool) string {
+	for _, dep := range deps {
+		if failed[dep] {
+			return dep
+		}
+	}
+	return ""
+}
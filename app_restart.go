@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ===============================
+// 服务重启相关接口
+// ===============================
+
+// RestartService 按配置的重启方式重启服务，服务未运行时直接启动
+func (a *App) RestartService(name string) string {
+	origin := EventOrigin{Actor: ActorUI, Reason: "restart"}
+	if a.processManager == nil {
+		return "Process manager not initialized"
+	}
+	if !a.processManager.IsRunning(name) {
+		return a.startService(origin, name)
+	}
+	return a.restartService(origin, name, a.restartMode())
+}
+
+// RestartServiceBlueGreen 以蓝绿方式重启服务，新实例就绪后才切换网关路由，失败时保留旧实例
+func (a *App) RestartServiceBlueGreen(name string) string {
+	origin := EventOrigin{Actor: ActorUI, Reason: "blue-green restart"}
+	if a.processManager == nil {
+		return "Process manager not initialized"
+	}
+	if !a.processManager.IsRunning(name) {
+		return a.startService(origin, name)
+	}
+	return a.restartService(origin, name, RestartModeBlueGreen)
+}
+
+// restartService 以指定方式重启正在运行的服务
+// 蓝绿重启不可用时（服务不支持或网关未运行）退回先停止再启动
+func (a *App) restartService(origin EventOrigin, name string, mode string) string {
+	note := ""
+	if mode == RestartModeBlueGreen {
+		reason := a.blueGreenUnavailable(name)
+		if reason == "" {
+			return a.blueGreenRestart(origin, name)
+		}
+		note = fmt.Sprintf("Blue-green restart is not available for '%s' (%s); restarting in place\n", name, reason)
+	}
+
+	a.stopProcessAs(origin, name)
+	if !a.processManager.WaitForExit(name, 10*time.Second) {
+		return note + fmt.Sprintf("Process '%s' did not exit in time; please restart it manually", name)
+	}
+	return note + a.startService(origin, name)
+}
+
+// blueGreenUnavailable 返回服务无法蓝绿重启的原因，可以时返回空字符串
+func (a *App) blueGreenUnavailable(name string) string {
+	if name != "workflowui" && name != "edu-tools" {
+		return "only workflowui and edu-tools support blue-green restarts"
+	}
+	if !a.processManager.IsRunning("gateway") {
+		return "the gateway is not running"
+	}
+	return ""
+}
+
+// blueGreenRestart 在备用端口启动新实例，健康检查通过后切换网关路由，等待旧实例的请求完成后停止旧实例
+// 新实例未能就绪时停止新实例，网关继续使用旧实例
+func (a *App) blueGreenRestart(origin EventOrigin, name string) string {
+	services := a.effectiveServicesConfig()
+
+	port, err := sparePort()
+	if err != nil {
+		return fmt.Sprintf("Failed to restart '%s': no spare port available: %v", name, err)
+	}
+
+	// 先校验签名，生成启动参数时会执行组件
+	warning, err := a.verifyBeforeStart(name)
+	if err != nil {
+		return fmt.Sprintf("Refused to start process '%s': %v", name, err)
+	}
+
+	var args []string
+	var argsWarning string
+	switch name {
+	case "workflowui":
+		args, argsWarning, err = a.workflowUILaunchArgs(port)
+	case "edu-tools":
+		args, argsWarning, err = a.eduToolsLaunchArgs(port)
+	}
+	if err != nil {
+		return fmt.Sprintf("Failed to restart '%s': %v", name, err)
+	}
+	warning = joinWarnings(warning, argsWarning)
+	message := ""
+	if warning != "" {
+		message = warning + "\n"
+	}
+
+	standby, result := a.processManager.StartStandby(origin, name, args...)
+	if standby == nil {
+		return message + fmt.Sprintf("Failed to restart '%s': %s; the current instance keeps serving", name, result)
+	}
+
+	address := net.JoinHostPort(dialHost(a.bindAddress(name)), port)
+	readyTimeout := time.Duration(services.ReadyTimeout) * time.Second
+	if err := waitReady(address, services.HealthPaths[name], readyTimeout, standby.exitedChan()); err != nil {
+		a.processManager.StopInstance(EventOrigin{Actor: ActorSystem, Reason: "blue-green rollback"}, name, standby)
+		return message + fmt.Sprintf("Rolled back restart of '%s': %v; the current instance keeps serving", name, err)
+	}
+
+	// 切换路由后新请求进入新实例，旧实例处理完进行中的请求后停止
+	previousAddress := a.gateway.Target(name)
+	a.gateway.SetTarget(name, address)
+	previous := a.processManager.Promote(name, standby)
+	a.clearRestartRequired(name)
+
+	drained := a.gateway.Drain(previousAddress, time.Duration(services.DrainTimeout)*time.Second)
+	if previous != nil {
+		a.processManager.StopInstance(origin, name, previous)
+	}
+
+	message += fmt.Sprintf("Process '%s' restarted on port %s without downtime", name, port)
+	if !drained {
+		message += fmt.Sprintf("; %d request(s) to the old instance were interrupted after %ds",
+			a.gateway.ActiveRequests(previousAddress), services.DrainTimeout)
+	}
+	return message
+}
+
+// GetRestartRequired 获取因配置变化需要重启才能生效的服务及变化的配置段
+func (a *App) GetRestartRequired() map[string][]string {
+	a.restartMu.Lock()
+	defer a.restartMu.Unlock()
+	required := make(map[string][]string, len(a.restartRequired))
+	for service, sections := range a.restartRequired {
+		required[service] = append([]string(nil), sections...)
+	}
+	return required
+}
+
+// applyServiceConfigChange 服务相关配置变化后，未运行的服务立即更新网关路由，正在运行的服务标记为需要重启并通知前端
+// 正在运行的服务继续使用原端口，网关路由在服务重启后由 startProcessAs 切换
+func (a *App) applyServiceConfigChange(section string, services []string) {
+	if a.processManager == nil || len(services) == 0 {
+		return
+	}
+
+	changed := false
+	a.restartMu.Lock()
+	for _, service := range services {
+		if !a.processManager.IsRunning(service) {
+			// 已停止的服务下次按新配置启动，网关路由先指向新的地址
+			if a.gateway.Target(service) != "" {
+				a.gateway.SetTarget(service, net.JoinHostPort(dialHost(a.bindAddress(service)), a.configuredPort(service)))
+			}
+			continue
+		}
+		if a.restartRequired == nil {
+			a.restartRequired = make(map[string][]string)
+		}
+		if !containsString(a.restartRequired[service], section) {
+			a.restartRequired[service] = append(a.restartRequired[service], section)
+			changed = true
+		}
+	}
+	a.restartMu.Unlock()
+
+	if changed {
+		wailsruntime.EventsEmit(a.ctx, ServiceRestartRequiredEvent, a.GetRestartRequired())
+	}
+}
+
+// clearRestartRequired 服务以新配置启动后清除重启标记
+func (a *App) clearRestartRequired(name string) {
+	a.restartMu.Lock()
+	_, flagged := a.restartRequired[name]
+	delete(a.restartRequired, name)
+	a.restartMu.Unlock()
+
+	if flagged {
+		wailsruntime.EventsEmit(a.ctx, ServiceRestartRequiredEvent, a.GetRestartRequired())
+	}
+}
+
+// changedBindAddresses 监听地址发生变化的服务
+func changedBindAddresses(previous, next ServicesConfig) []string {
+	var services []string
+	for _, service := range configServices {
+		if previous.BindAddresses[service] != next.BindAddresses[service] {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// restartMode 获取配置的重启方式
+func (a *App) restartMode() string {
+	return a.effectiveServicesConfig().RestartMode
+}
+
+// effectiveServicesConfig 获取补全默认值后的服务编排配置
+func (a *App) effectiveServicesConfig() ServicesConfig {
+	defaults := GetDefaultConfig().Services
+	services := defaults
+	if current := a.GetServicesConfig(); current != nil {
+		services = *current
+	}
+	if services.RestartMode == "" {
+		services.RestartMode = defaults.RestartMode
+	}
+	if services.ReadyTimeout <= 0 {
+		services.ReadyTimeout = defaults.ReadyTimeout
+	}
+	if services.DrainTimeout <= 0 {
+		services.DrainTimeout = defaults.DrainTimeout
+	}
+
+	// 未配置健康检查路径的服务使用默认路径
+	healthPaths := make(map[string]string, len(defaults.HealthPaths))
+	for name, path := range defaults.HealthPaths {
+		healthPaths[name] = path
+	}
+	for name, path := range services.HealthPaths {
+		if path != "" {
+			healthPaths[name] = "/" + strings.TrimPrefix(path, "/")
+		}
+	}
+	services.HealthPaths = healthPaths
+
+	bindAddresses := make(map[string]string, len(defaults.BindAddresses))
+	for name, address := range defaults.BindAddresses {
+		bindAddresses[name] = address
+	}
+	for name, address := range services.BindAddresses {
+		if address != "" {
+			bindAddresses[name] = address
+		}
+	}
+	services.BindAddresses = bindAddresses
+	return services
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+)
+
+// 保存在密钥存储中的配置项
+const (
+	secretArkApiKey      = "eduexp.ark_api_key"
+	secretWorkflowApiKey = "workflow.apikey"
+)
+
+// ===============================
+// 密钥存储相关接口
+// ===============================
+
+// GetSecretsStatus 获取密钥存储状态
+func (a *App) GetSecretsStatus() SecretsStatus {
+	if a.secrets == nil {
+		return SecretsStatus{Locked: true}
+	}
+	return a.secrets.Status()
+}
+
+// UnlockSecrets 使用口令解锁密钥存储，解锁后迁移配置中残留的明文密钥
+func (a *App) UnlockSecrets(passphrase string) string {
+	if a.secrets == nil {
+		return "Secrets store not initialized"
+	}
+	if err := a.secrets.Unlock(passphrase); err != nil {
+		return fmt.Sprintf("Failed to unlock secrets: %v", err)
+	}
+	a.sealConfigSecrets()
+	return "Secrets unlocked successfully"
+}
+
+// LockSecrets 锁定密钥存储，锁定期间无法启动需要密钥的服务
+func (a *App) LockSecrets() string {
+	if a.secrets == nil {
+		return "Secrets store not initialized"
+	}
+	if err := a.secrets.Lock(); err != nil {
+		return fmt.Sprintf("Failed to lock secrets: %v", err)
+	}
+	return "Secrets locked successfully"
+}
+
+// SetSecretsPassphrase 设置保护密钥的口令，口令为空时改回本机密钥保护
+func (a *App) SetSecretsPassphrase(passphrase string) string {
+	if a.secrets == nil {
+		return "Secrets store not initialized"
+	}
+	if err := a.secrets.SetPassphrase(passphrase); err != nil {
+		return fmt.Sprintf("Failed to set secrets passphrase: %v", err)
+	}
+	if passphrase == "" {
+		return "Secrets are now protected by the machine key"
+	}
+	return "Secrets passphrase updated successfully"
+}
+
+// sealSecret 将明文配置值保存到当前配置档案的密钥并替换为引用，空值删除对应密钥，已是引用的值保持不变
+func (a *App) sealSecret(id string, value *string) error {
+	if IsSecretRef(*value) {
+		return nil
+	}
+	if a.secrets == nil {
+		return fmt.Errorf("secrets store not initialized")
+	}
+	if a.configManager != nil {
+		id = profileSecretID(a.configManager.ActiveProfile(), id)
+	}
+	if *value == "" {
+		return a.secrets.Delete(id)
+	}
+
+	ref, err := a.secrets.Put(id, *value)
+	if err != nil {
+		return err
+	}
+	*value = ref
+	return nil
+}
+
+// sealChangedSecrets 将修改过的明文敏感配置项加密保存，由配置管理器在保存配置前调用
+// 未修改的值保持不变，残留的明文密钥由 sealConfigSecrets 迁移
+func (a *App) sealChangedSecrets(previous, next *Config) error {
+	previousValues := configSecretValues(previous)
+	for id, value := range configSecretValues(next) {
+		if *value == *previousValues[id] {
+			continue
+		}
+		if err := a.sealSecret(id, value); err != nil {
+			return fmt.Errorf("failed to encrypt %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// resolveSecret 解密配置中的密钥引用，仅在启动服务时调用
+func (a *App) resolveSecret(value string) (string, error) {
+	if !IsSecretRef(value) {
+		return value, nil
+	}
+	if a.secrets == nil {
+		return "", fmt.Errorf("secrets store not initialized")
+	}
+	return a.secrets.Resolve(value)
+}
+
+// resolveConfigSecrets 返回配置中敏感配置项解密后的值，无法解密的配置项为空
+func (a *App) resolveConfigSecrets(config *Config) map[string]string {
+	values := make(map[string]string)
+	for id, value := range configSecretValues(config) {
+		values[id], _ = a.resolveSecret(*value)
+	}
+	return values
+}
+
+// configSecretValues 返回配置中保存在密钥存储的配置项
+func configSecretValues(config *Config) map[string]*string {
+	return map[string]*string{
+		secretArkApiKey:      &config.EduExp.ArkApiKey,
+		secretWorkflowApiKey: &config.Workflow.ApiKey,
+	}
+}
+
+// profileSecretID 配置档案中配置项对应的密钥ID，默认档案沿用引入配置档案之前的ID
+func profileSecretID(profile, id string) string {
+	if profile == DefaultConfigProfile {
+		return id
+	}
+	return "profiles/" + profile + "/" + id
+}
+
+// copyProfileSecrets 将配置引用的密钥复制为目标档案的密钥，并修改配置中的引用
+func (a *App) copyProfileSecrets(config *Config, profile string) error {
+	for id, value := range configSecretValues(config) {
+		if !IsSecretRef(*value) {
+			continue
+		}
+		plain, err := a.resolveSecret(*value)
+		if err != nil {
+			return err
+		}
+		ref, err := a.secrets.Put(profileSecretID(profile, id), plain)
+		if err != nil {
+			return err
+		}
+		*value = ref
+	}
+	return nil
+}
+
+// deleteProfileSecrets 删除配置档案的密钥
+func (a *App) deleteProfileSecrets(profile string) {
+	if a.secrets == nil {
+		return
+	}
+	for id := range configSecretValues(&Config{}) {
+		if err := a.secrets.Delete(profileSecretID(profile, id)); err != nil {
+			a.reportError("", fmt.Errorf("failed to delete secret '%s' of profile '%s': %v", id, profile, err))
+		}
+	}
+}
+
+// sealConfigSecrets 将配置文件中的明文 API Key 迁移到密钥存储
+func (a *App) sealConfigSecrets() {
+	if a.configManager == nil || a.secrets == nil || a.secrets.Status().Locked {
+		return
+	}
+	// 只处理配置文件中的值，环境变量和命令行参数覆盖的密钥不保存
+	config := a.configManager.FileConfig()
+
+	eduexp := config.EduExp
+	if eduexp.ArkApiKey != "" && !IsSecretRef(eduexp.ArkApiKey) {
+		if err := a.sealSecret(secretArkApiKey, &eduexp.ArkApiKey); err != nil {
+			a.reportError("", fmt.Errorf("failed to encrypt ark API key: %v", err))
+		} else if err := a.configManager.UpdateEduExpConfig(eduexp); err != nil {
+			a.reportError("", fmt.Errorf("failed to save encrypted ark API key: %v", err))
+		}
+	}
+
+	workflow := config.Workflow
+	if workflow.ApiKey != "" && !IsSecretRef(workflow.ApiKey) {
+		if err := a.sealSecret(secretWorkflowApiKey, &workflow.ApiKey); err != nil {
+			a.reportError("", fmt.Errorf("failed to encrypt workflow API key: %v", err))
+		} else if err := a.configManager.UpdateWorkflowConfig(workflow); err != nil {
+			a.reportError("", fmt.Errorf("failed to save encrypted workflow API key: %v", err))
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ===============================
+// 本地 HTTPS 相关接口
+// ===============================
+
+// GetTLSConfig 获取本地 HTTPS 配置
+func (a *App) GetTLSConfig() *TLSConfig {
+	if a.configManager == nil {
+		return nil
+	}
+	config := a.configManager.GetConfig()
+	if config == nil {
+		return nil
+	}
+	return &config.TLS
+}
+
+// UpdateTLSConfig 更新本地 HTTPS 配置，并重启正在运行的网关和文件服务器
+func (a *App) UpdateTLSConfig(tlsConfig TLSConfig) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	err := a.configManager.UpdateTLSConfig(tlsConfig)
+	if err != nil {
+		return fmt.Sprintf("Failed to update TLS config: %v", err)
+	}
+	origin := EventOrigin{Actor: ActorUI, Reason: "TLS config updated"}
+	a.recordConfigUpdate(origin, "tls")
+
+	message := "TLS configuration updated successfully"
+	for _, name := range []string{"gateway", "fileserver"} {
+		if restart := a.restartIfRunning(origin, name); restart != "" {
+			message += "\n" + restart
+		}
+	}
+	return message
+}
+
+// GetCAInfo 获取本地根证书信息，根证书不存在时生成
+func (a *App) GetCAInfo() (*CAInfo, error) {
+	return a.certAuthority.Info()
+}
+
+// RegenerateCACertificate 重新生成本地根证书，并重启正在运行的网关和文件服务器
+// 用于早期版本生成的没有名称限制的根证书，或配置的主机超出了根证书的名称限制；学生设备需要重新安装根证书
+func (a *App) RegenerateCACertificate() string {
+	if err := a.certAuthority.Regenerate(); err != nil {
+		return fmt.Sprintf("Failed to regenerate CA certificate: %v", err)
+	}
+	origin := EventOrigin{Actor: ActorUI, Reason: "CA certificate regenerated"}
+
+	message := "CA certificate regenerated successfully; reinstall it on student devices"
+	for _, name := range []string{"gateway", "fileserver"} {
+		if restart := a.restartIfRunning(origin, name); restart != "" {
+			message += "\n" + restart
+		}
+	}
+	return message
+}
+
+// GetCACertificate 获取本地根证书（PEM 编码），用于在学生设备上安装
+func (a *App) GetCACertificate() string {
+	data, err := a.certAuthority.CACertificatePEM()
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ExportCACertificate 将本地根证书导出到指定路径
+func (a *App) ExportCACertificate(path string) string {
+	if path == "" {
+		return "Failed to export CA certificate: export path is empty"
+	}
+
+	data, err := a.certAuthority.CACertificatePEM()
+	if err != nil {
+		return fmt.Sprintf("Failed to export CA certificate: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Sprintf("Failed to export CA certificate: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Sprintf("Failed to export CA certificate: %v", err)
+	}
+	return fmt.Sprintf("CA certificate exported to %s", path)
+}
+
+// tlsArgs 启用 HTTPS 时为服务签发证书并返回证书参数，未启用时返回空
+func (a *App) tlsArgs(service string) ([]string, error) {
+	tlsConfig := a.GetTLSConfig()
+	if tlsConfig == nil || !tlsConfig.Enabled {
+		return nil, nil
+	}
+
+	certFile, keyFile, err := a.certAuthority.IssueCertificate(service, a.certificateHosts())
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue TLS certificate: %v", err)
+	}
+	return []string{"--tls-cert", certFile, "--tls-key", keyFile}, nil
+}
+
+// serviceScheme 返回网关和文件服务器使用的协议
+func (a *App) serviceScheme() string {
+	if tlsConfig := a.GetTLSConfig(); tlsConfig != nil && tlsConfig.Enabled {
+		return "https"
+	}
+	return "http"
+}
+
+// certificateHosts 返回服务证书需要覆盖的主机名：本机名称、网关子域名、回环地址和局域网地址
+func (a *App) certificateHosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	for _, route := range gatewayRoutes {
+		hosts = append(hosts, route.Prefix+".localhost")
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hosts = append(hosts, hostname, hostname+".local")
+	}
+
+	hosts = append(hosts, lanAddresses(a.effectiveLANConfig().BindAddress)...)
+
+	var extra []string
+	if tlsConfig := a.GetTLSConfig(); tlsConfig != nil {
+		extra = tlsConfig.Hosts
+	}
+	return certificateHosts(hosts, extra)
+}
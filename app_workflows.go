@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ===============================
+// 工作流定义相关接口
+// ===============================
+
+// WorkflowResult 工作流修改结果
+type WorkflowResult struct {
+	Success  bool              // 是否成功
+	Key      string            // 工作流键
+	Revision int               // 修改后的修订号，前端下次修改时提交
+	Conflict bool              // 是否因修订号过期被拒绝，需要重新加载后再修改
+	Current  int               // 修订号过期时的当前修订号
+	Message  string            // 结果说明
+	Errors   []ValidationError // 校验错误
+}
+
+// AddWorkflow 添加工作流定义
+func (a *App) AddWorkflow(key string, workflow WorkflowDef) WorkflowResult {
+	if a.configManager == nil {
+		return WorkflowResult{Key: key, Message: "Configuration manager not initialized"}
+	}
+	revision, err := a.configManager.AddWorkflow(key, workflow)
+	return a.workflowResult(key, revision, err, "Failed to add workflow", fmt.Sprintf("Workflow '%s' added successfully", key))
+}
+
+// UpdateWorkflow 修改工作流定义，revision 为读取时的修订号，工作流已被修改时拒绝
+func (a *App) UpdateWorkflow(key string, workflow WorkflowDef, revision int) WorkflowResult {
+	if a.configManager == nil {
+		return WorkflowResult{Key: key, Message: "Configuration manager not initialized"}
+	}
+	revision, err := a.configManager.UpdateWorkflow(key, workflow, revision)
+	return a.workflowResult(key, revision, err, "Failed to update workflow", fmt.Sprintf("Workflow '%s' updated successfully", key))
+}
+
+// DeleteWorkflow 删除工作流定义，revision 为读取时的修订号，工作流已被修改时拒绝
+func (a *App) DeleteWorkflow(key string, revision int) WorkflowResult {
+	if a.configManager == nil {
+		return WorkflowResult{Key: key, Message: "Configuration manager not initialized"}
+	}
+	err := a.configManager.DeleteWorkflow(key, revision)
+	return a.workflowResult(key, 0, err, "Failed to delete workflow", fmt.Sprintf("Workflow '%s' deleted successfully", key))
+}
+
+// RenameWorkflowKey 修改工作流的键，revision 为读取时的修订号，工作流已被修改时拒绝
+func (a *App) RenameWorkflowKey(oldKey, newKey string, revision int) WorkflowResult {
+	if a.configManager == nil {
+		return WorkflowResult{Key: oldKey, Message: "Configuration manager not initialized"}
+	}
+	revision, err := a.configManager.RenameWorkflowKey(oldKey, newKey, revision)
+	if err != nil {
+		return a.workflowResult(oldKey, revision, err, "Failed to rename workflow", "")
+	}
+	return a.workflowResult(newKey, revision, nil, "", fmt.Sprintf("Workflow '%s' renamed to '%s'", oldKey, newKey))
+}
+
+// DuplicateWorkflow 复制工作流定义到新的键
+func (a *App) DuplicateWorkflow(key, newKey string) WorkflowResult {
+	if a.configManager == nil {
+		return WorkflowResult{Key: key, Message: "Configuration manager not initialized"}
+	}
+	revision, err := a.configManager.DuplicateWorkflow(key, newKey)
+	if err != nil {
+		return a.workflowResult(key, revision, err, "Failed to duplicate workflow", "")
+	}
+	return a.workflowResult(newKey, revision, nil, "", fmt.Sprintf("Workflow '%s' duplicated to '%s'", key, newKey))
+}
+
+// workflowResult 生成工作流修改结果，成功时记录配置变更
+func (a *App) workflowResult(key string, revision int, err error, failure, success string) WorkflowResult {
+	if err != nil {
+		result := WorkflowResult{Key: key, Message: fmt.Sprintf("%s: %v", failure, err)}
+		var conflict *WorkflowConflictError
+		var validation ValidationErrors
+		switch {
+		case errors.As(err, &conflict):
+			result.Conflict = true
+			result.Current = conflict.Current
+		case errors.As(err, &validation):
+			result.Errors = validation
+		}
+		return result
+	}
+
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI}, "workflow")
+	return WorkflowResult{Success: true, Key: key, Revision: revision, Message: success}
+}
+
+// ValidateWorkflowInput 按工作流的参数定义校验输入，发送到扣子之前调用
+func (a *App) ValidateWorkflowInput(workflowKey string, values map[string]interface{}) WorkflowInputResult {
+	if a.configManager == nil {
+		return WorkflowInputResult{Errors: []ValidationError{{Code: ValidationNotFound, Message: "configuration manager not initialized"}}}
+	}
+	workflow, exists := a.configManager.GetConfig().Workflow.Workflows[workflowKey]
+	if !exists {
+		return WorkflowInputResult{Errors: []ValidationError{{Code: ValidationNotFound, Message: fmt.Sprintf("workflow '%s' not found", workflowKey)}}}
+	}
+	return ValidateWorkflowInput(workflow, values)
+}
+
+// WorkflowRunResult 工作流运行结果
+type WorkflowRunResult struct {
+	Success  bool              // 是否成功
+	Output   string            // 工作流输出
+	DebugURL string            // 扣子的调试链接
+	Message  string            // 结果说明
+	Errors   []ValidationError // 输入校验错误，Field 为参数键
+}
+
+// RunWorkflow 校验输入后调用扣子运行工作流，输入不满足参数定义时不发送
+func (a *App) RunWorkflow(workflowKey string, values map[string]interface{}) WorkflowRunResult {
+	if a.configManager == nil {
+		return WorkflowRunResult{Message: "Configuration manager not initialized"}
+	}
+	config := a.configManager.GetConfig()
+	workflow, exists := config.Workflow.Workflows[workflowKey]
+	if !exists {
+		return WorkflowRunResult{Message: fmt.Sprintf("Workflow '%s' not found", workflowKey)}
+	}
+	input := ValidateWorkflowInput(workflow, values)
+	if !input.Valid {
+		return WorkflowRunResult{Message: fmt.Sprintf("Invalid workflow input: %v", ValidationErrors(input.Errors)), Errors: input.Errors}
+	}
+
+	apiKey, err := a.resolveSecret(config.Workflow.ApiKey)
+	if err != nil {
+		return WorkflowRunResult{Message: fmt.Sprintf("Failed to decrypt API key: %v", err)}
+	}
+	if apiKey == "" {
+		return WorkflowRunResult{Message: "Coze API key is not configured"}
+	}
+
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	response, err := runCozeWorkflow(ctx, apiKey, workflow, input.Values)
+	if err != nil {
+		result := WorkflowRunResult{Message: fmt.Sprintf("Failed to run workflow: %v", err)}
+		if response != nil {
+			result.DebugURL = response.DebugURL
+		}
+		return result
+	}
+	return WorkflowRunResult{Success: true, Output: response.Data, DebugURL: response.DebugURL, Message: fmt.Sprintf("Workflow '%s' completed", workflowKey)}
+}
+
+// ===============================
+// 工作流导入导出相关接口
+// ===============================
+
+// ExportWorkflows 将工作流定义导出为 JSON 文件，keys 为空时导出全部工作流
+func (a *App) ExportWorkflows(path string, keys []string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+
+	data, err := exportWorkflows(a.configManager.FileConfig().Workflow.Workflows, keys)
+	if err != nil {
+		return fmt.Sprintf("Failed to export workflows: %v", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Sprintf("Failed to export workflows: %v", err)
+	}
+	return fmt.Sprintf("Workflows exported to %s", path)
+}
+
+// PreviewWorkflowImport 预览导入工作流定义文件或扣子导出的工作流信息，列出键冲突和校验错误，不修改当前配置
+func (a *App) PreviewWorkflowImport(path, conflict string) (*WorkflowImportPreview, error) {
+	if a.configManager == nil {
+		return nil, fmt.Errorf("configuration manager not initialized")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow file: %v", err)
+	}
+	_, preview, err := previewWorkflowImport(a.configManager.FileConfig(), data, conflict)
+	return preview, err
+}
+
+// ImportWorkflows 导入工作流定义，conflict 为 skip（跳过）、overwrite（覆盖）或 rename（导入为新的键）
+func (a *App) ImportWorkflows(path, conflict string) string {
+	if a.configManager == nil {
+		return "Configuration manager not initialized"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("Failed to import workflows: %v", err)
+	}
+	imported, preview, err := previewWorkflowImport(a.configManager.FileConfig(), data, conflict)
+	if err != nil {
+		return fmt.Sprintf("Failed to import workflows: %v", err)
+	}
+	if len(preview.Errors) > 0 {
+		return fmt.Sprintf("Failed to import workflows: %v", ValidationErrors(preview.Errors))
+	}
+
+	items, err := a.configManager.ImportWorkflows(imported, conflict)
+	if err != nil {
+		return fmt.Sprintf("Failed to import workflows: %v", err)
+	}
+	a.recordConfigUpdate(EventOrigin{Actor: ActorUI, Reason: fmt.Sprintf("workflow import from %s", path)}, "workflow")
+
+	added, skipped := 0, 0
+	for _, item := range items {
+		if item.Action == WorkflowImportSkip {
+			skipped++
+		} else {
+			added++
+		}
+	}
+	return fmt.Sprintf("Workflows imported successfully (%d imported, %d skipped)", added, skipped)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// writeFileAtomic 原子地写入文件：先写入同目录下的临时文件并刷盘，再重命名覆盖目标文件
+// 写入过程中崩溃或磁盘写满时，目标文件保持原内容不变
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %v", err)
+	}
+	tempPath := file.Name()
+
+	// 失败时删除临时文件
+	committed := false
+	defer func() {
+		if !committed {
+			file.Close()
+			os.Remove(tempPath)
+		}
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write temp file: %v", err)
+	}
+	if err := file.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to set file mode: %v", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temp file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %v", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to replace file: %v", err)
+	}
+	committed = true
+
+	// 刷新目录，确保重命名本身已落盘
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("failed to sync directory: %v", err)
+	}
+	return nil
+}
+
+// fileLock 跨进程的文件锁，用于防止多个应用实例同时写入同一文件
+type fileLock struct {
+	file *os.File
+}
+
+// lockFile 获取指定锁文件的排他锁，锁被其他进程持有时等待直到超时
+func lockFile(path string, timeout time.Duration) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %v", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock file: %v", err)
+		}
+		if locked {
+			return &fileLock{file: file}, nil
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("file is locked by another process: %s", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Unlock 释放文件锁
+func (l *fileLock) Unlock() error {
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
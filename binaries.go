@@ -0,0 +1,299 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// managedComponents 由二进制管理器负责安装的组件
+var managedComponents = []string{"workflowui", "edu-tools"}
+
+// ReleaseManifest 发布清单
+type ReleaseManifest struct {
+	Components map[string]ManifestComponent `json:"components"` // 组件名称 -> 组件发布信息
+}
+
+// ManifestComponent 单个组件的发布信息
+type ManifestComponent struct {
+	Latest   string            `json:"latest"`   // 最新版本
+	Releases []ManifestRelease `json:"releases"` // 所有可用版本
+}
+
+// ManifestRelease 组件的一个发布版本
+type ManifestRelease struct {
+	Version   string                   `json:"version"`   // 版本号
+	Platforms map[string]ManifestAsset `json:"platforms"` // 平台（GOOS/GOARCH）-> 下载文件
+}
+
+// ManifestAsset 某个平台的下载文件
+type ManifestAsset struct {
+	URL    string `json:"url"`            // 下载地址，可以是相对于清单的路径
+	SHA256 string `json:"sha256"`         // 文件的 SHA-256（十六进制）
+	Size   int64  `json:"size,omitempty"` // 文件大小，可选
+}
+
+// InstallOptions 组件安装选项
+type InstallOptions struct {
+	Version      string // 安装的版本，为空时安装最新版本
+	Policy       string // 签名校验策略
+	KeepVersions int    // 保留的历史版本数量
+}
+
+// InstallResult 组件安装结果
+type InstallResult struct {
+	Component string // 组件名称
+	Version   string // 安装的版本
+	Path      string // 安装路径
+	SHA256    string // 文件哈希
+	Signed    bool   // 清单签名是否验证通过
+}
+
+// BinaryManager 服务二进制文件管理器
+type BinaryManager struct {
+	binDir     string       // 二进制文件目录
+	stagingDir string       // 下载暂存目录，与 binDir 位于同一文件系统以便原子替换
+	client     *http.Client // 下载使用的 HTTP 客户端
+	mu         sync.Mutex   // 同一时间只允许一个安装操作
+	cacheMu    sync.Mutex   // 保护版本检测缓存
+
+	// verifyExec 执行组件（检查版本、参数支持）前的签名校验，返回错误时不执行；为空时不校验
+	verifyExec func(path string) error
+	// isTrusted 判断组件的二进制文件（按 SHA-256）是否由用户确认信任，为空时不信任任何文件
+	isTrusted func(component, hash string) bool
+}
+
+// NewBinaryManager 创建二进制文件管理器
+func NewBinaryManager(binDir string) *BinaryManager {
+	return &BinaryManager{
+		binDir:     binDir,
+		stagingDir: filepath.Join(binDir, ".staging"),
+		client:     &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+// currentPlatform 返回清单中使用的平台标识
+func currentPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// executableName 根据操作系统返回可执行文件名
+func executableName(baseName string) string {
+	if runtime.GOOS == "windows" {
+		return baseName + ".exe"
+	}
+	return baseName
+}
+
+// isManagedComponent 判断组件是否由二进制管理器管理
+func isManagedComponent(name string) bool {
+	for _, component := range managedComponents {
+		if component == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecutablePath 返回组件的安装路径
+func (bm *BinaryManager) ExecutablePath(component string) string {
+	return filepath.Join(bm.binDir, executableName(component))
+}
+
+// FetchManifest 下载并解析发布清单
+func (bm *BinaryManager) FetchManifest(manifestURL string) (*ReleaseManifest, error) {
+	signed, err := bm.fetchSignedManifest(manifestURL)
+	if err != nil {
+		return nil, err
+	}
+	return signed.manifest, nil
+}
+
+// FindRelease 查找组件的指定版本，版本为空时返回最新版本
+func (m *ReleaseManifest) FindRelease(component, version string) (*ManifestRelease, error) {
+	info, exists := m.Components[component]
+	if !exists {
+		return nil, fmt.Errorf("component '%s' not found in release manifest", component)
+	}
+
+	if version == "" {
+		version = info.Latest
+	}
+	if version == "" {
+		return nil, fmt.Errorf("release manifest does not declare a latest version for '%s'", component)
+	}
+
+	for i := range info.Releases {
+		if info.Releases[i].Version == version {
+			return &info.Releases[i], nil
+		}
+	}
+	return nil, fmt.Errorf("version '%s' of component '%s' not found in release manifest", version, component)
+}
+
+// Install 从发布清单安装组件
+// 文件先下载到暂存目录并校验哈希，然后通过重命名原子地替换已安装的版本，被替换的版本归档到 versions 目录
+// 签名策略为 enforce 时拒绝安装未经受信任签名的清单中的组件
+func (bm *BinaryManager) Install(manifestURL, component string, opts InstallOptions) (*InstallResult, error) {
+	if !isManagedComponent(component) {
+		return nil, fmt.Errorf("component '%s' is not managed by the binary manager", component)
+	}
+
+	signed, err := bm.fetchSignedManifest(manifestURL)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Policy == SignaturePolicyEnforce && !signed.signed {
+		return nil, fmt.Errorf("release manifest signature is missing or not trusted by this build")
+	}
+
+	release, err := signed.manifest.FindRelease(component, opts.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, exists := release.Platforms[currentPlatform()]
+	if !exists {
+		return nil, fmt.Errorf("version '%s' of component '%s' has no build for %s", release.Version, component, currentPlatform())
+	}
+
+	assetURL, err := resolveURL(manifestURL, asset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download URL '%s': %v", asset.URL, err)
+	}
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	stagedFile, err := bm.download(assetURL, asset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s %s: %v", component, release.Version, err)
+	}
+	defer os.Remove(stagedFile)
+
+	if err := bm.archiveCurrent(component, opts.KeepVersions); err != nil {
+		return nil, fmt.Errorf("failed to archive current version of '%s': %v", component, err)
+	}
+
+	target := bm.ExecutablePath(component)
+	if err := os.Rename(stagedFile, target); err != nil {
+		return nil, fmt.Errorf("failed to replace '%s': %v", target, err)
+	}
+
+	result := &InstallResult{
+		Component: component,
+		Version:   release.Version,
+		Path:      target,
+		SHA256:    strings.ToLower(asset.SHA256),
+		Signed:    signed.signed,
+	}
+
+	// 保存清单原文和签名，运行前据此重新校验文件
+	// 元数据写入失败时仍可通过版本命令识别版本，但文件会被视为未签名
+	bm.writeMetadata(ComponentMetadata{
+		Component:   component,
+		Version:     result.Version,
+		SHA256:      result.SHA256,
+		InstalledAt: time.Now(),
+		Manifest:    base64.StdEncoding.EncodeToString(signed.raw),
+		Signature:   base64.StdEncoding.EncodeToString(signed.signature),
+	})
+	return result, nil
+}
+
+// download 下载文件到暂存目录，校验哈希并设置可执行权限，返回暂存文件路径
+func (bm *BinaryManager) download(assetURL string, asset ManifestAsset) (string, error) {
+	if asset.SHA256 == "" {
+		return "", fmt.Errorf("release manifest does not provide a SHA-256 hash")
+	}
+
+	if err := os.MkdirAll(bm.stagingDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %v", err)
+	}
+
+	file, err := os.CreateTemp(bm.stagingDir, "download-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging file: %v", err)
+	}
+	stagedFile := file.Name()
+
+	err = func() error {
+		defer file.Close()
+
+		resp, err := bm.client.Get(assetURL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected HTTP status: %s", resp.Status)
+		}
+
+		hasher := sha256.New()
+		body := io.Reader(resp.Body)
+		if asset.Size > 0 {
+			// 多读一个字节以便发现文件比声明的更大
+			body = io.LimitReader(resp.Body, asset.Size+1)
+		}
+		written, err := io.Copy(io.MultiWriter(file, hasher), body)
+		if err != nil {
+			return err
+		}
+		if asset.Size > 0 && written != asset.Size {
+			return fmt.Errorf("size mismatch: expected %d bytes, got %d", asset.Size, written)
+		}
+
+		actual := hex.EncodeToString(hasher.Sum(nil))
+		if !strings.EqualFold(actual, asset.SHA256) {
+			return fmt.Errorf("SHA-256 mismatch: expected %s, got %s", strings.ToLower(asset.SHA256), actual)
+		}
+
+		if err := file.Chmod(0755); err != nil {
+			return fmt.Errorf("failed to set executable permission: %v", err)
+		}
+		return file.Sync()
+	}()
+	if err != nil {
+		os.Remove(stagedFile)
+		return "", err
+	}
+	return stagedFile, nil
+}
+
+// fetch 下载小文件到内存
+func (bm *BinaryManager) fetch(rawURL string, maxSize int64) ([]byte, error) {
+	resp, err := bm.client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSize))
+}
+
+// resolveURL 将相对地址解析为相对于清单地址的绝对地址
+func resolveURL(base, ref string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchivedVersion 归档的组件历史版本
+type ArchivedVersion struct {
+	Version    string    // 版本号
+	Path       string    // 归档的二进制文件路径
+	SHA256     string    // 文件哈希
+	ArchivedAt time.Time // 归档时间
+}
+
+// versionsDir 返回组件历史版本的归档目录
+func (bm *BinaryManager) versionsDir(component string) string {
+	return filepath.Join(bm.binDir, "versions", component)
+}
+
+// ArchivedVersions 获取组件的历史版本，按归档时间倒序排列
+func (bm *BinaryManager) ArchivedVersions(component string) []ArchivedVersion {
+	entries, err := os.ReadDir(bm.versionsDir(component))
+	if err != nil {
+		return []ArchivedVersion{}
+	}
+
+	versions := make([]ArchivedVersion, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(bm.versionsDir(component), entry.Name(), executableName(component))
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		archived := ArchivedVersion{Version: entry.Name(), Path: path, ArchivedAt: info.ModTime()}
+		if metadata, err := readMetadataFile(path + ".json"); err == nil {
+			archived.Version = metadata.Version
+			archived.SHA256 = metadata.SHA256
+		}
+		versions = append(versions, archived)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ArchivedAt.After(versions[j].ArchivedAt)
+	})
+	return versions
+}
+
+// archiveCurrent 将当前安装的版本复制到归档目录，并只保留最近 keep 个历史版本
+// 调用方需持有 bm.mu
+func (bm *BinaryManager) archiveCurrent(component string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	current := bm.ExecutablePath(component)
+	hash, err := fileSHA256(current)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// 手动复制的二进制文件没有元数据，以哈希区分版本
+	metadata, err := bm.readMetadata(component)
+	if err != nil || !strings.EqualFold(metadata.SHA256, hash) {
+		metadata = &ComponentMetadata{
+			Component: component,
+			Version:   "unknown-" + hash[:12],
+			SHA256:    hash,
+		}
+		if info, err := os.Stat(current); err == nil {
+			metadata.InstalledAt = info.ModTime()
+		}
+	}
+
+	dir := filepath.Join(bm.versionsDir(component), archiveDirName(metadata.Version))
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	archived := filepath.Join(dir, executableName(component))
+	if err := copyFile(current, archived, 0755); err != nil {
+		return err
+	}
+	if err := writeMetadataFile(archived+".json", metadata); err != nil {
+		return err
+	}
+
+	bm.pruneVersions(component, keep)
+	return nil
+}
+
+// pruneVersions 删除超出保留数量的历史版本
+func (bm *BinaryManager) pruneVersions(component string, keep int) {
+	versions := bm.ArchivedVersions(component)
+	for i := keep; i < len(versions); i++ {
+		os.RemoveAll(filepath.Dir(versions[i].Path))
+	}
+}
+
+// Rollback 恢复到上一个版本，当前版本同时被归档以便再次切换
+// 优先选择低于当前版本的最高历史版本，当前版本未知时选择最近归档的其他版本
+func (bm *BinaryManager) Rollback(component string, keep int) (*InstallResult, error) {
+	if !isManagedComponent(component) {
+		return nil, fmt.Errorf("component '%s' is not managed by the binary manager", component)
+	}
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	previous := bm.findRollbackTarget(component)
+	if previous == nil {
+		return nil, fmt.Errorf("no previous version of '%s' is available", component)
+	}
+
+	metadata, err := readMetadataFile(previous.Path + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata of version %s: %v", previous.Version, err)
+	}
+
+	// 先复制到暂存目录，归档当前版本时的清理不会影响要恢复的文件
+	if err := os.MkdirAll(bm.stagingDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	stagedFile := filepath.Join(bm.stagingDir, "rollback-"+component)
+	if err := copyFile(previous.Path, stagedFile, 0755); err != nil {
+		return nil, fmt.Errorf("failed to stage version %s: %v", previous.Version, err)
+	}
+	defer os.Remove(stagedFile)
+
+	if err := bm.archiveCurrent(component, keep); err != nil {
+		return nil, fmt.Errorf("failed to archive current version of '%s': %v", component, err)
+	}
+
+	target := bm.ExecutablePath(component)
+	if err := os.Rename(stagedFile, target); err != nil {
+		return nil, fmt.Errorf("failed to replace '%s': %v", target, err)
+	}
+	bm.writeMetadata(*metadata)
+
+	return &InstallResult{
+		Component: component,
+		Version:   metadata.Version,
+		Path:      target,
+		SHA256:    metadata.SHA256,
+		Signed:    metadata.Signature != "",
+	}, nil
+}
+
+// findRollbackTarget 查找回滚的目标版本
+func (bm *BinaryManager) findRollbackTarget(component string) *ArchivedVersion {
+	currentHash, _ := fileSHA256(bm.ExecutablePath(component))
+	currentVersion := ""
+	if metadata, err := bm.readMetadata(component); err == nil && strings.EqualFold(metadata.SHA256, currentHash) {
+		currentVersion = metadata.Version
+	}
+
+	var candidates []ArchivedVersion
+	for _, archived := range bm.ArchivedVersions(component) {
+		if archived.SHA256 == "" || !strings.EqualFold(archived.SHA256, currentHash) {
+			candidates = append(candidates, archived)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if currentVersion != "" {
+		var best *ArchivedVersion
+		for i := range candidates {
+			candidate := &candidates[i]
+			if compareVersions(candidate.Version, currentVersion) >= 0 {
+				continue
+			}
+			if best == nil || compareVersions(candidate.Version, best.Version) > 0 {
+				best = candidate
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+	return &candidates[0]
+}
+
+// archiveDirName 将版本号转换为安全的目录名
+func archiveDirName(version string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(version)
+	if name == "" || name == "." {
+		return "_"
+	}
+	return name
+}
+
+// readMetadataFile 读取元数据文件
+func readMetadataFile(path string) (*ComponentMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var metadata ComponentMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %v", err)
+	}
+	return &metadata, nil
+}
+
+// writeMetadataFile 写入元数据文件
+func writeMetadataFile(path string, metadata *ComponentMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// copyFile 复制文件并同步到磁盘
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
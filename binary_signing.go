@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// embeddedReleaseKeys 内置的发布清单签名公钥（base64 编码），轮换密钥时在此追加新公钥
+var embeddedReleaseKeys = []string{
+	"OFED57BMX4NnB6KWNAgs0EoLv2dRKi+xMKIiWRKB8Ng=",
+}
+
+// releasePublicKeys 额外受信任的公钥（base64 编码，多个用逗号分隔），只能追加，不能替换内置公钥
+// 构建时通过 -ldflags "-X main.releasePublicKeys=<key1>,<key2>" 注入
+var releasePublicKeys = ""
+
+// 签名校验策略
+const (
+	SignaturePolicyOff     = "off"     // 不校验
+	SignaturePolicyWarn    = "warn"    // 校验失败时警告但允许运行
+	SignaturePolicyEnforce = "enforce" // 校验失败时拒绝安装和运行
+)
+
+// 二进制文件校验状态
+const (
+	VerificationSigned   = "signed"   // 由受信任的签名清单安装且未被修改
+	VerificationUnsigned = "unsigned" // 没有有效签名（手动复制或清单未签名）
+	VerificationModified = "modified" // 安装后文件被修改
+	VerificationMissing  = "missing"  // 文件不存在
+)
+
+// BinaryVerification 二进制文件校验结果
+type BinaryVerification struct {
+	Name    string // 组件名称
+	Path    string // 二进制文件路径
+	Status  string // 校验状态
+	Version string // 签名清单中记录的版本
+	Message string // 说明信息
+}
+
+// signedManifest 下载的发布清单及其签名
+type signedManifest struct {
+	manifest  *ReleaseManifest
+	raw       []byte // 清单原文，签名针对原文计算
+	signature []byte // ed25519 签名，清单没有签名时为空
+	signed    bool   // 签名是否由受信任的公钥验证通过
+}
+
+// trustedReleaseKeys 解析内置和构建时注入的公钥，忽略格式不正确的条目
+func trustedReleaseKeys() []ed25519.PublicKey {
+	var keys []ed25519.PublicKey
+	for _, encoded := range append(embeddedReleaseKeys, strings.Split(releasePublicKeys, ",")...) {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			continue
+		}
+		keys = append(keys, ed25519.PublicKey(key))
+	}
+	return keys
+}
+
+// verifyManifestSignature 使用受信任的公钥验证清单签名
+func verifyManifestSignature(raw, signature []byte) bool {
+	if len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	for _, key := range trustedReleaseKeys() {
+		if ed25519.Verify(key, raw, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchSignedManifest 下载发布清单及同目录下的 .sig 签名文件
+// 签名文件不存在时返回未签名的清单，是否允许由调用方按策略决定
+func (bm *BinaryManager) fetchSignedManifest(manifestURL string) (*signedManifest, error) {
+	if manifestURL == "" {
+		return nil, fmt.Errorf("release manifest URL is not configured")
+	}
+
+	raw, err := bm.fetch(manifestURL, 10*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download release manifest: %v", err)
+	}
+
+	var manifest ReleaseManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse release manifest: %v", err)
+	}
+
+	result := &signedManifest{manifest: &manifest, raw: raw}
+	if encoded, err := bm.fetch(manifestURL+".sig", 4096); err == nil {
+		if signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded))); err == nil {
+			result.signature = signature
+			result.signed = verifyManifestSignature(raw, signature)
+		}
+	}
+	return result, nil
+}
+
+// VerifyComponent 校验已安装的二进制文件
+// 重新验证安装时保存的清单签名，并确认文件哈希与签名清单中的记录一致
+func (bm *BinaryManager) VerifyComponent(name string) BinaryVerification {
+	return bm.verifyBinary(name, bm.ExecutablePath(name))
+}
+
+// VerifyBinary 校验 bin 目录中的任意二进制文件，非受管组件没有签名记录
+func (bm *BinaryManager) VerifyBinary(path string) BinaryVerification {
+	for _, name := range managedComponents {
+		if bm.ExecutablePath(name) == path {
+			return bm.VerifyComponent(name)
+		}
+	}
+	return BinaryVerification{
+		Name:    path,
+		Path:    path,
+		Status:  VerificationUnsigned,
+		Message: "Binary was not installed from a signed release manifest",
+	}
+}
+
+// InBinDir 判断路径是否位于受管的 bin 目录中
+func (bm *BinaryManager) InBinDir(path string) bool {
+	rel, err := filepath.Rel(bm.binDir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// verifyBinary 校验单个组件的二进制文件
+func (bm *BinaryManager) verifyBinary(name, path string) BinaryVerification {
+	result := BinaryVerification{Name: name, Path: path, Status: VerificationUnsigned}
+
+	hash, err := fileSHA256(path)
+	if os.IsNotExist(err) {
+		result.Status = VerificationMissing
+		result.Message = "Binary not found"
+		return result
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to read binary: %v", err)
+		return result
+	}
+
+	metadata, err := bm.readMetadata(name)
+	if err != nil {
+		result.Message = "Binary was not installed from a signed release manifest"
+		return result
+	}
+	result.Version = metadata.Version
+
+	if !strings.EqualFold(metadata.SHA256, hash) {
+		result.Status = VerificationModified
+		result.Message = "Binary has been modified after installation"
+		return result
+	}
+
+	raw, rawErr := base64.StdEncoding.DecodeString(metadata.Manifest)
+	signature, sigErr := base64.StdEncoding.DecodeString(metadata.Signature)
+	if rawErr != nil || sigErr != nil || !verifyManifestSignature(raw, signature) {
+		result.Message = "Release manifest signature is missing or not trusted by this build"
+		return result
+	}
+
+	var manifest ReleaseManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		result.Message = fmt.Sprintf("Failed to parse signed manifest: %v", err)
+		return result
+	}
+	release, err := manifest.FindRelease(name, metadata.Version)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	asset, exists := release.Platforms[currentPlatform()]
+	if !exists || !strings.EqualFold(asset.SHA256, hash) {
+		result.Status = VerificationModified
+		result.Message = "Binary has been modified after installation"
+		return result
+	}
+
+	result.Status = VerificationSigned
+	result.Message = "OK"
+	return result
+}
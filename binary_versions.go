@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minComponentVersions 本桌面版本支持的最低组件版本
+var minComponentVersions = map[string]string{
+	"workflowui": "0.1.0",
+	"edu-tools":  "0.1.0",
+}
+
+// 版本信息来源
+const (
+	VersionSourceMetadata = "metadata" // 安装时写入的元数据文件
+	VersionSourceCommand  = "command"  // 执行组件的版本命令
+	VersionSourceUnknown  = "unknown"  // 无法识别
+)
+
+// versionPattern 从版本命令的输出中提取版本号
+var versionPattern = regexp.MustCompile(`v?(\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.-]+)?)`)
+
+// flagPattern 从帮助命令的输出中提取命令行参数
+var flagPattern = regexp.MustCompile(`(?:^|[\s,\[(])(--?[A-Za-z0-9][A-Za-z0-9_-]*)`)
+
+// ComponentMetadata 安装时写在二进制文件旁边的元数据
+type ComponentMetadata struct {
+	Component   string    `json:"component"`    // 组件名称
+	Version     string    `json:"version"`      // 版本号
+	SHA256      string    `json:"sha256"`       // 二进制文件哈希
+	InstalledAt time.Time `json:"installed_at"` // 安装时间
+	Manifest    string    `json:"manifest"`     // 安装所用的发布清单原文（base64）
+	Signature   string    `json:"signature"`    // 发布清单签名（base64），未签名时为空
+}
+
+// InstalledComponent 已安装组件的信息
+type InstalledComponent struct {
+	Name       string // 组件名称
+	Installed  bool   // 是否已安装
+	Path       string // 二进制文件路径
+	Version    string // 识别到的版本，无法识别时为空
+	Source     string // 版本信息来源
+	SHA256     string // 二进制文件哈希
+	MinVersion string // 本桌面版本要求的最低版本
+	Compatible bool   // 是否满足最低版本要求，有最低版本要求但版本未知时视为不兼容
+	Trusted    bool   // 是否由用户确认信任，信任的文件可以不经组件管理器安装直接运行
+	Message    string // 说明信息
+}
+
+// versionCacheEntry 版本检测缓存条目，以文件哈希判断是否失效
+type versionCacheEntry struct {
+	SHA256     string    `json:"sha256"`
+	Version    string    `json:"version"`
+	Source     string    `json:"source"`
+	DetectedAt time.Time `json:"detected_at"`
+	Flags      []string  `json:"flags,omitempty"` // 帮助命令输出中的命令行参数，HelpProbed 为 false 时未检测
+	HelpProbed bool      `json:"help_probed,omitempty"`
+}
+
+// metadataPath 返回组件元数据文件路径
+func (bm *BinaryManager) metadataPath(component string) string {
+	return bm.ExecutablePath(component) + ".json"
+}
+
+// versionCachePath 返回版本检测缓存文件路径
+func (bm *BinaryManager) versionCachePath() string {
+	return filepath.Join(bm.binDir, ".versions.json")
+}
+
+// writeMetadata 写入组件元数据
+func (bm *BinaryManager) writeMetadata(metadata ComponentMetadata) error {
+	return writeMetadataFile(bm.metadataPath(metadata.Component), &metadata)
+}
+
+// readMetadata 读取组件元数据
+func (bm *BinaryManager) readMetadata(component string) (*ComponentMetadata, error) {
+	return readMetadataFile(bm.metadataPath(component))
+}
+
+// InstalledComponents 获取所有受管组件的安装信息
+func (bm *BinaryManager) InstalledComponents() []InstalledComponent {
+	components := make([]InstalledComponent, 0, len(managedComponents))
+	for _, name := range managedComponents {
+		components = append(components, bm.InspectComponent(name))
+	}
+	return components
+}
+
+// InspectComponent 获取单个组件的安装信息和兼容性
+func (bm *BinaryManager) InspectComponent(name string) InstalledComponent {
+	info := InstalledComponent{
+		Name:       name,
+		Path:       bm.ExecutablePath(name),
+		Source:     VersionSourceUnknown,
+		MinVersion: minComponentVersions[name],
+		Compatible: true,
+	}
+
+	if _, err := os.Stat(info.Path); err != nil {
+		info.Message = "Not installed"
+		return info
+	}
+	info.Installed = true
+
+	version, source, hash, err := bm.detectVersion(name)
+	info.SHA256 = hash
+	info.Version = version
+	info.Source = source
+	info.Trusted = bm.trusted(name, hash)
+
+	// 无法确认满足最低版本要求的组件不允许启动
+	if version == "" {
+		info.Message = "Version could not be determined"
+		if err != nil {
+			info.Message = fmt.Sprintf("Failed to detect version: %v", err)
+		}
+		if info.MinVersion != "" {
+			info.Compatible = false
+			info.Message += fmt.Sprintf("; version %s or newer is required", info.MinVersion)
+		}
+		return info
+	}
+	if info.MinVersion != "" && compareVersions(version, info.MinVersion) < 0 {
+		info.Compatible = false
+		info.Message = fmt.Sprintf("Version %s is older than the minimum supported version %s", version, info.MinVersion)
+		return info
+	}
+	info.Message = "OK"
+	return info
+}
+
+// CheckCompatibility 检查组件版本是否满足最低要求，有最低版本要求但版本未知时同样阻止启动
+func (bm *BinaryManager) CheckCompatibility(name string) error {
+	info := bm.InspectComponent(name)
+	if !info.Installed || info.Compatible {
+		return nil
+	}
+	if info.Version == "" {
+		return fmt.Errorf("%s: %s; please reinstall the component from the component manager, or trust this binary if you installed it yourself", name, info.Message)
+	}
+	return fmt.Errorf("%s version %s is not supported by this desktop build (requires %s or newer); please update the component",
+		name, info.Version, info.MinVersion)
+}
+
+// detectVersion 检测组件版本，结果按文件哈希缓存；版本命令执行失败时不缓存，下次重新检测
+// 版本取自安装时写入的元数据，只有用户确认信任的文件才执行版本命令，避免运行来历不明的文件
+func (bm *BinaryManager) detectVersion(component string) (version, source, hash string, err error) {
+	path := bm.ExecutablePath(component)
+	hash, err = fileSHA256(path)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	bm.cacheMu.Lock()
+	defer bm.cacheMu.Unlock()
+
+	// 取消信任后不再使用版本命令的结果
+	trusted := bm.trusted(component, hash)
+	cache := bm.loadVersionCache()
+	if entry, exists := cache[component]; exists && entry.SHA256 == hash && (entry.Source == VersionSourceMetadata || trusted) {
+		return entry.Version, entry.Source, hash, nil
+	}
+
+	// 文件被替换后元数据失效
+	if metadata, metaErr := bm.readMetadata(component); metaErr == nil && strings.EqualFold(metadata.SHA256, hash) {
+		version, source = metadata.Version, VersionSourceMetadata
+	} else if trusted {
+		version, err = runVersionCommand(path)
+		source = VersionSourceCommand
+		if version == "" {
+			source = VersionSourceUnknown
+		}
+	} else {
+		return "", VersionSourceUnknown, hash, errNotInstalledByManager
+	}
+
+	// 版本命令执行失败（超时、无法运行）可能是暂时的，不缓存
+	if err != nil {
+		return version, source, hash, err
+	}
+	// 输出中没有版本号也缓存，避免每次启动都重复执行版本命令
+	cache[component] = versionCacheEntry{SHA256: hash, Version: version, Source: source, DetectedAt: time.Now()}
+	bm.saveVersionCache(cache)
+	return version, source, hash, err
+}
+
+// loadVersionCache 读取版本检测缓存，读取失败时返回空缓存
+func (bm *BinaryManager) loadVersionCache() map[string]versionCacheEntry {
+	cache := make(map[string]versionCacheEntry)
+	data, err := os.ReadFile(bm.versionCachePath())
+	if err == nil {
+		json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+// saveVersionCache 保存版本检测缓存
+func (bm *BinaryManager) saveVersionCache(cache map[string]versionCacheEntry) {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	os.WriteFile(bm.versionCachePath(), data, 0644)
+}
+
+// errNotInstalledByManager 二进制文件不是由组件管理器安装的（没有元数据或元数据与文件不符）且未被信任
+var errNotInstalledByManager = fmt.Errorf("binary was not installed by the component manager and is not trusted")
+
+// trusted 判断组件的二进制文件是否由用户确认信任
+func (bm *BinaryManager) trusted(component, hash string) bool {
+	return hash != "" && bm.isTrusted != nil && bm.isTrusted(component, hash)
+}
+
+// checkExec 执行组件（检查参数支持）前校验二进制文件：用户确认信任的文件直接允许，
+// 其他文件需由组件管理器安装（元数据与文件哈希一致）并通过签名策略校验
+func (bm *BinaryManager) checkExec(component string) error {
+	path := bm.ExecutablePath(component)
+	hash, err := fileSHA256(path)
+	if err != nil {
+		return err
+	}
+	if bm.trusted(component, hash) {
+		return nil
+	}
+	if metadata, err := bm.readMetadata(component); err != nil || !strings.EqualFold(metadata.SHA256, hash) {
+		return fmt.Errorf("refused to run %s: %v", component, errNotInstalledByManager)
+	}
+	if bm.verifyExec == nil {
+		return nil
+	}
+	if err := bm.verifyExec(path); err != nil {
+		return fmt.Errorf("refused to run unverified binary: %v", err)
+	}
+	return nil
+}
+
+// forgetVersion 删除组件的版本检测缓存，信任设置变化后重新检测
+func (bm *BinaryManager) forgetVersion(component string) {
+	bm.cacheMu.Lock()
+	defer bm.cacheMu.Unlock()
+
+	cache := bm.loadVersionCache()
+	if _, exists := cache[component]; exists {
+		delete(cache, component)
+		bm.saveVersionCache(cache)
+	}
+}
+
+// runVersionCommand 执行组件的 --version 命令并解析版本号
+func runVersionCommand(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("version command timed out")
+	}
+	if err != nil {
+		return "", fmt.Errorf("version command failed: %v", err)
+	}
+
+	match := versionPattern.FindStringSubmatch(string(output))
+	if match == nil {
+		return "", nil
+	}
+	return match[1], nil
+}
+
+// SupportsFlag 根据组件 --help 的输出判断是否支持指定的命令行参数
+// 输出中的参数与版本一起按文件哈希缓存，同一个二进制文件只执行一次帮助命令
+// 帮助命令退出码非零时仍检查其输出，无法执行或未通过签名校验时视为不支持
+func (bm *BinaryManager) SupportsFlag(component, flag string) bool {
+	flags, err := bm.helpFlags(component)
+	return err == nil && containsString(flags, flag)
+}
+
+// helpFlags 返回组件帮助命令输出中的命令行参数，结果缓存在版本检测缓存中
+func (bm *BinaryManager) helpFlags(component string) ([]string, error) {
+	// 先检测版本，确保缓存中有当前文件哈希的条目
+	if _, _, _, err := bm.detectVersion(component); err != nil {
+		return nil, err
+	}
+	path := bm.ExecutablePath(component)
+	hash, err := fileSHA256(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bm.cacheMu.Lock()
+	defer bm.cacheMu.Unlock()
+
+	cache := bm.loadVersionCache()
+	entry, exists := cache[component]
+	if exists && entry.SHA256 == hash && entry.HelpProbed {
+		return entry.Flags, nil
+	}
+	if err := bm.checkExec(component); err != nil {
+		return nil, err
+	}
+	flags, err := runHelpCommand(path)
+	if err != nil {
+		return nil, err
+	}
+	// 版本检测后文件被替换时不缓存
+	if exists && entry.SHA256 == hash {
+		entry.Flags, entry.HelpProbed = flags, true
+		cache[component] = entry
+		bm.saveVersionCache(cache)
+	}
+	return flags, nil
+}
+
+// runHelpCommand 执行组件的 --help 命令并提取其中的命令行参数
+func runHelpCommand(path string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, _ := exec.CommandContext(ctx, path, "--help").CombinedOutput()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("help command timed out")
+	}
+
+	flags := []string{}
+	for _, match := range flagPattern.FindAllStringSubmatch(string(output), -1) {
+		if !containsString(flags, match[1]) {
+			flags = append(flags, match[1])
+		}
+	}
+	return flags, nil
+}
+
+// fileSHA256 计算文件的 SHA-256
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// compareVersions 比较两个版本号，返回 -1、0 或 1
+// 按数字逐段比较，带预发布后缀（如 1.2.0-beta）的版本低于对应的正式版本
+func compareVersions(a, b string) int {
+	a, preA, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	b, preB, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	default:
+		return 1
+	}
+}
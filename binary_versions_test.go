@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installTestComponent 安装输出指定帮助信息的脚本作为 edu-tools，每次执行时将第一个参数记录到 calls 文件
+// withMetadata 为 true 时同时写入组件管理器安装时的元数据
+func installTestComponent(t *testing.T, bm *BinaryManager, help string, withMetadata bool) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the component binary")
+	}
+
+	calls := filepath.Join(bm.binDir, "calls")
+	script := "#!/bin/sh\necho \"$1\" >> " + calls + "\necho 'edu-tools 1.0.0'\necho '" + help + "'\n"
+	path := bm.ExecutablePath("edu-tools")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := fileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(bm.metadataPath("edu-tools"))
+	if withMetadata {
+		if err := bm.writeMetadata(ComponentMetadata{Component: "edu-tools", Version: "1.0.0", SHA256: hash}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return hash
+}
+
+// componentCalls 返回组件脚本被执行时的参数
+func componentCalls(bm *BinaryManager) []string {
+	data, _ := os.ReadFile(filepath.Join(bm.binDir, "calls"))
+	return strings.Fields(string(data))
+}
+
+func TestSupportsFlagRunsHelpOncePerBinary(t *testing.T) {
+	bm := NewBinaryManager(t.TempDir())
+
+	installTestComponent(t, bm, "Usage: edu-tools [--port PORT] [--host=HOST]\n  -v, --verbose", true)
+	for i := 0; i < 3; i++ {
+		for _, flag := range []string{"--port", "--host", "-v", "--verbose"} {
+			if !bm.SupportsFlag("edu-tools", flag) {
+				t.Fatalf("%s should be supported", flag)
+			}
+		}
+		if bm.SupportsFlag("edu-tools", "--hos") {
+			t.Fatalf("a prefix of a flag should not match")
+		}
+	}
+	if calls := componentCalls(bm); len(calls) != 1 || calls[0] != "--help" {
+		t.Fatalf("component calls = %v, want a single --help", calls)
+	}
+
+	// 替换二进制文件后重新检测
+	installTestComponent(t, bm, "Usage: edu-tools [--port PORT]", true)
+	if bm.SupportsFlag("edu-tools", "--host") {
+		t.Fatalf("--host should not be supported by the new binary")
+	}
+	if calls := componentCalls(bm); len(calls) != 2 {
+		t.Fatalf("component calls = %v, want two --help", calls)
+	}
+}
+
+func TestUnmanagedBinaryRunsOnlyWhenTrusted(t *testing.T) {
+	bm := NewBinaryManager(t.TempDir())
+	trusted := map[string]string{}
+	bm.isTrusted = func(component, hash string) bool {
+		return trusted[component] == hash
+	}
+
+	// 手动复制到 bin 目录、没有元数据的文件不执行
+	hash := installTestComponent(t, bm, "Usage: edu-tools [--host HOST]", false)
+	info := bm.InspectComponent("edu-tools")
+	if info.Version != "" || info.Compatible || info.Trusted {
+		t.Fatalf("unmanaged binary was inspected as %+v", info)
+	}
+	if err := bm.CheckCompatibility("edu-tools"); err == nil || !strings.Contains(err.Error(), "trust") {
+		t.Fatalf("unexpected compatibility result: %v", err)
+	}
+	if bm.SupportsFlag("edu-tools", "--host") {
+		t.Fatalf("flags of an unmanaged binary should not be probed")
+	}
+	if calls := componentCalls(bm); len(calls) != 0 {
+		t.Fatalf("unmanaged binary was executed with %v", calls)
+	}
+
+	// 信任后执行版本和帮助命令
+	trusted["edu-tools"] = hash
+	info = bm.InspectComponent("edu-tools")
+	if info.Version != "1.0.0" || info.Source != VersionSourceCommand || !info.Compatible || !info.Trusted {
+		t.Fatalf("trusted binary was inspected as %+v", info)
+	}
+	if !bm.SupportsFlag("edu-tools", "--host") {
+		t.Fatalf("--host should be supported by the trusted binary")
+	}
+
+	// 取消信任后不再使用版本命令的结果
+	delete(trusted, "edu-tools")
+	if info := bm.InspectComponent("edu-tools"); info.Version != "" {
+		t.Fatalf("untrusted binary still reports version %s", info.Version)
+	}
+
+	// 签名校验失败的已安装文件同样不执行
+	installTestComponent(t, bm, "Usage: edu-tools [--host HOST]", true)
+	bm.verifyExec = func(path string) error {
+		return os.ErrPermission
+	}
+	before := len(componentCalls(bm))
+	if bm.SupportsFlag("edu-tools", "--host") {
+		t.Fatalf("flags of an unverified binary should not be probed")
+	}
+	if calls := componentCalls(bm); len(calls) != before {
+		t.Fatalf("unverified binary was executed with %v", calls[before:])
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// 服务重启方式
+const (
+	RestartModeStopStart = "stop-start" // 先停止再启动，重启期间服务不可用
+	RestartModeBlueGreen = "blue-green" // 在备用端口启动新实例，就绪后切换网关路由再停止旧实例
+)
+
+// StartStandby 启动进程的备用实例，当前实例不受影响
+// 备用实例在 Promote 之前不会出现在进程名称下，但会在应用退出时一并停止
+func (pm *ProcessManager) StartStandby(origin EventOrigin, processName string, extraArgs ...string) (*Process, string) {
+	pm.mu.RLock()
+	config, exists := pm.configs[processName]
+	pm.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Sprintf("Process '%s' not found", processName)
+	}
+
+	standby := &Process{Config: config}
+	standby.mu.Lock()
+	result := pm.startRun(origin, processName, standby, config, extraArgs)
+	started := standby.running
+	standby.mu.Unlock()
+
+	if !started {
+		return nil, result
+	}
+
+	pm.mu.Lock()
+	pm.detached[standby] = processName
+	pm.mu.Unlock()
+	return standby, result
+}
+
+// Promote 将备用实例切换为进程名称下的当前实例，返回被替换的旧实例
+func (pm *ProcessManager) Promote(processName string, standby *Process) *Process {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	previous := pm.processes[processName]
+	pm.processes[processName] = standby
+	delete(pm.detached, standby)
+	if previous != nil {
+		pm.detached[previous] = processName
+	}
+	return previous
+}
+
+// StopInstance 停止未注册在进程名称下的实例（备用实例或被替换的旧实例）
+// 先请求进程正常退出，超时后强制结束
+func (pm *ProcessManager) StopInstance(origin EventOrigin, processName string, instance *Process) {
+	instance.mu.Lock()
+	running, runID := instance.running, instance.runID
+	instance.mu.Unlock()
+
+	if running {
+		instance.stopGracefully()
+		pm.record(JournalEvent{
+			Type:    EventStop,
+			Service: processName,
+			RunID:   runID,
+			Actor:   origin.Actor,
+			Reason:  origin.Reason,
+			Details: "gracefully",
+		})
+	}
+
+	pm.mu.Lock()
+	delete(pm.detached, instance)
+	pm.mu.Unlock()
+}
+
+// exitedChan 返回实例当前运行的退出通知通道
+func (p *Process) exitedChan() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exited
+}
+
+// sparePort 获取一个当前空闲的本地端口
+func sparePort() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer listener.Close()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	return port, err
+}
+
+// waitReady 轮询健康检查地址直到服务就绪
+// 服务返回非 5xx 响应即视为就绪；实例提前退出或超时返回错误
+func waitReady(address, path string, timeout time.Duration, exited <-chan struct{}) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.Now().Add(timeout)
+	healthURL := "http://" + address + path
+
+	var lastErr error
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(healthURL)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil
+			}
+			err = fmt.Errorf("health check returned %s", resp.Status)
+		}
+		lastErr = err
+
+		select {
+		case <-exited:
+			return fmt.Errorf("new instance exited before becoming ready")
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	if lastErr != nil {
+		return fmt.Errorf("new instance did not become ready within %s: %v", timeout, lastErr)
+	}
+	return fmt.Errorf("new instance did not become ready within %s", timeout)
+}
@@ -1,24 +1,35 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
 // Config 应用配置结构
 type Config struct {
-	Global   GlobalConfig   `toml:"global"`
-	EduExp   EduExpConfig   `toml:"eduexp"`
-	Workflow WorkflowConfig `toml:"workflow"`
-	License  LicenseConfig  `toml:"license"`
+	SchemaVersion int              `toml:"schema_version"` // 配置文件结构版本
+	Global        GlobalConfig     `toml:"global"`
+	EduExp        EduExpConfig     `toml:"eduexp"`
+	Workflow      WorkflowConfig   `toml:"workflow"`
+	License       LicenseConfig    `toml:"license"`
+	Services      ServicesConfig   `toml:"services"`
+	FileServer    FileServerConfig `toml:"fileserver"`
+	Binaries      BinariesConfig   `toml:"binaries"`
+	Gateway       GatewayConfig    `toml:"gateway"`
+	LAN           LANConfig        `toml:"lan"`
+	TLS           TLSConfig        `toml:"tls"`
 }
 
 // GlobalConfig 全局配置
 type GlobalConfig struct {
-	Theme string `toml:"theme"` // 主题: light/dark
+	Theme        string `toml:"theme"`         // 主题: light/dark
+	HistoryLimit int    `toml:"history_limit"` // 保留的配置历史版本数量
 }
 
 // EduExpConfig EduExp模块配置
@@ -43,16 +54,30 @@ type WorkflowDef struct {
 	WorkflowID string              `toml:"workflow_id"` // 工作流ID
 	AppID      string              `toml:"app_id"`      // 应用ID
 	Parameters []WorkflowParameter `toml:"parameters"`  // 参数列表
+	Revision   int                 `toml:"-"`           // 修订号，不写入配置文件；内容变化时分配只增不减的新值，用于拒绝基于旧版本的修改
 }
 
 // WorkflowParameter 工作流参数
 type WorkflowParameter struct {
-	Key          string   `toml:"key"`           // 参数键
-	Label        string   `toml:"label"`         // 参数标签
-	Type         string   `toml:"type"`          // 参数类型
-	Required     bool     `toml:"required"`      // 是否必需
-	Options      []string `toml:"options"`       // 选项列表（用于select类型）
-	DefaultValue string   `toml:"default_value"` // 默认值
+	Key          string              `toml:"key"`                    // 参数键
+	Label        string              `toml:"label"`                  // 参数标签
+	Type         string              `toml:"type"`                   // 参数类型
+	Required     bool                `toml:"required"`               // 是否必需
+	Options      []string            `toml:"options"`                // 选项列表（用于select、multiselect类型）
+	DefaultValue string              `toml:"default_value"`          // 默认值，multiselect类型用逗号分隔多个选项
+	HelpText     string              `toml:"help_text,omitempty"`    // 帮助说明
+	Min          *float64            `toml:"min,omitempty"`          // 最小值（用于number类型）
+	Max          *float64            `toml:"max,omitempty"`          // 最大值（用于number类型）
+	MinLength    int                 `toml:"min_length,omitzero"`    // 最小长度，文本为字符数，multiselect为选择数量
+	MaxLength    int                 `toml:"max_length,omitzero"`    // 最大长度，0 表示不限制
+	Pattern      string              `toml:"pattern,omitempty"`      // 文本需匹配的正则表达式
+	VisibleWhen  []WorkflowCondition `toml:"visible_when,omitempty"` // 显示条件，全部满足时才显示和校验该参数
+}
+
+// WorkflowCondition 工作流参数的显示条件：参数 Key 的值为 Values 之一
+type WorkflowCondition struct {
+	Key    string   `toml:"key"`    // 依赖的参数键
+	Values []string `toml:"values"` // 满足条件的取值，multiselect参数选中其中任一项即满足
 }
 
 // LicenseConfig 许可配置
@@ -60,51 +85,164 @@ type LicenseConfig struct {
 	// TODO: 添加许可相关配置项
 	LicenseKey   string   `toml:"license_key"`   // 许可证密钥
 	ExpiryDate   string   `toml:"expiry_date"`   // 过期日期
-	UserLimit    int      `toml:"user_limit"`    // 用户数量限制
+	UserLimit    int      `toml:"user_limit"`    // 用户数量限制，填写许可证密钥后同时限制局域网模式的在线设备数，0 表示不限制
 	FeatureFlags []string `toml:"feature_flags"` // 功能标志
 }
 
+// ServicesConfig 服务编排配置
+type ServicesConfig struct {
+	DefaultProfile string                    `toml:"default_profile"` // 应用启动时自动启动的服务组，为空则不自动启动
+	Profiles       map[string]ServiceProfile `toml:"profiles"`        // 服务组定义
+	RestartMode    string                    `toml:"restart_mode"`    // 重启方式: stop-start/blue-green
+	ReadyTimeout   int                       `toml:"ready_timeout"`   // 蓝绿重启时等待新实例就绪的秒数
+	DrainTimeout   int                       `toml:"drain_timeout"`   // 蓝绿重启时等待旧实例请求完成的秒数
+	HealthPaths    map[string]string         `toml:"health_paths"`    // 各服务的健康检查路径
+	BindAddresses  map[string]string         `toml:"bind_addresses"`  // 各服务的监听地址，为空时使用服务自身的默认值
+}
+
+// ServiceProfile 服务组定义
+type ServiceProfile struct {
+	Name     string   `toml:"name"`     // 服务组显示名称
+	Services []string `toml:"services"` // 包含的服务（进程名称）
+}
+
+// FileServerConfig 文件服务器配置
+type FileServerConfig struct {
+	Port      string `toml:"port"`       // 文件服务器端口
+	Root      string `toml:"root"`       // 共享的根目录，为空时使用应用数据目录下的 data/files
+	Browse    bool   `toml:"browse"`     // 是否允许列出目录内容
+	Gzip      bool   `toml:"gzip"`       // 是否对文本类响应启用 gzip 压缩
+	AccessLog bool   `toml:"access_log"` // 是否将访问日志写入服务输出
+}
+
+// GatewayConfig 本地网关配置
+type GatewayConfig struct {
+	Enabled bool   `toml:"enabled"` // 应用启动时是否启动网关
+	Port    string `toml:"port"`    // 网关端口
+	Routing string `toml:"routing"` // 生成服务地址时使用的路由方式: path/subdomain
+}
+
+// LANConfig 局域网共享配置
+type LANConfig struct {
+	Enabled     bool   `toml:"enabled"`      // 是否允许局域网内的设备通过网关访问服务
+	BindAddress string `toml:"bind_address"` // 局域网模式下网关的监听地址
+	AuthMode    string `toml:"auth_mode"`    // 认证方式: token/password
+	Token       string `toml:"token"`        // 访问令牌
+	Password    string `toml:"password"`     // 访问密码
+	SessionTTL  int    `toml:"session_ttl"`  // 会话空闲超时（分钟）
+	MaxClients  int    `toml:"max_clients"`  // 同时在线的局域网设备数上限，0 时使用默认值
+}
+
+// TLSConfig 本地 HTTPS 配置
+type TLSConfig struct {
+	Enabled bool     `toml:"enabled"` // 网关和文件服务器是否使用 HTTPS
+	Hosts   []string `toml:"hosts"`   // 证书额外包含的主机名或 IP
+}
+
+// BinariesConfig 服务二进制文件管理配置
+type BinariesConfig struct {
+	ManifestURL     string            `toml:"manifest_url"`     // 发布清单地址，可指向本地 HTTP 服务
+	SignaturePolicy string            `toml:"signature_policy"` // 签名校验策略: off/warn/enforce
+	KeepVersions    int               `toml:"keep_versions"`    // 每个组件保留的历史版本数量
+	AutoUpdate      bool              `toml:"auto_update"`      // 应用启动时自动更新组件
+	Pinned          map[string]string `toml:"pinned"`           // 固定版本的组件，自动更新时跳过
+	Trusted         map[string]string `toml:"trusted"`          // 用户确认信任的组件二进制文件 SHA-256，允许运行自行安装的文件
+}
+
+// configLockTimeout 等待其他应用实例释放配置文件锁的时间
+const configLockTimeout = 5 * time.Second
+
 // ConfigManager 配置管理器
+// 当前配置是只读快照：写入时复制一份修改后整体替换，读取时返回副本，调用方修改返回值不会影响当前配置
 type ConfigManager struct {
-	configDir  string  // 配置目录
-	configFile string  // 配置文件路径
-	config     *Config // 当前配置
+	configDir      string                             // 配置目录
+	configFile     string                             // 配置文件路径
+	mu             sync.RWMutex                       // 保护 config 指针和 profile
+	writeMu        sync.Mutex                         // 串行化配置写入
+	config         *Config                            // 当前配置（配置文件层，不含环境变量和命令行参数的覆盖）
+	profile        string                             // 当前配置档案名称
+	problems       ValidationErrors                   // 加载配置文件时发现的校验错误（手动编辑导致）
+	overrides      []configOverride                   // 环境变量和命令行参数的覆盖，创建后不再修改
+	overrideErr    ValidationErrors                   // 无法应用的覆盖
+	recovery       *ConfigRecovery                    // 启动时配置文件损坏的恢复结果
+	subMu          sync.Mutex                         // 保护 subscribers 和 external
+	subscribers    map[int]*configSubscriber          // 配置变更订阅者
+	nextSubID      int                                // 下一个订阅者ID
+	external       []func(ExternalConfigChange)       // 配置文件被外部修改时的处理函数
+	disk           configFileState                    // 最近一次读取或写入时配置文件的状态，由 writeMu 保护
+	reportedBad    [32]byte                           // 已报告过的无法解析的外部修改，避免重复报告
+	sealSecrets    func(previous, next *Config) error // 保存前将修改过的敏感配置项加密保存，配置中只保留引用
+	secrets        *SecretStore                       // 密钥存储，历史版本同时保存引用的密钥密文，为 nil 时不保存
+	onError        func(error)                        // 不影响配置保存的后台操作（如保存历史版本）失败时的处理函数，为 nil 时忽略
+	queueMu        sync.Mutex                         // 保护 queue 和 delivering
+	queue          []configEvent                      // 等待发送的变更通知
+	delivering     bool                               // 是否有协程正在发送通知
+	revisionMu     sync.Mutex                         // 保护 revision 和 revisionLoaded
+	revision       int                                // 最近分配的工作流修订号
+	revisionLoaded bool                               // 是否已从文件读取修订号计数器
 }
 
 // NewConfigManager 创建配置管理器
-func NewConfigManager() (*ConfigManager, error) {
-	// 获取用户配置目录
-	userConfigDir, err := os.UserConfigDir()
+// 生效的配置依次由默认值、配置文件、环境变量和命令行参数叠加而成，后者优先
+func NewConfigManager(options ConfigOptions) (*ConfigManager, error) {
+	manager, err := newConfigManager(options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user config directory: %v", err)
+		return nil, err
+	}
+	if err := manager.load(); err != nil {
+		return nil, err
 	}
+	return manager, nil
+}
 
-	// 创建应用配置目录
-	appConfigDir := filepath.Join(userConfigDir, "eduexp-desktop")
+// newConfigManager 创建尚未加载配置的配置管理器，用于在加载前设置 sealSecrets，避免明文密钥写入配置文件和历史版本
+func newConfigManager(options ConfigOptions) (*ConfigManager, error) {
+	configFile := options.File
+	if configFile == "" {
+		// 获取用户配置目录
+		userConfigDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user config directory: %v", err)
+		}
+		configFile = filepath.Join(userConfigDir, "eduexp-desktop", "config.toml")
+	} else if abs, err := filepath.Abs(configFile); err == nil {
+		configFile = abs
+	}
+
+	// 创建应用配置目录，--config 指定的配置文件所在目录同时保存密钥、证书和配置历史
+	appConfigDir := filepath.Dir(configFile)
 	if err := os.MkdirAll(appConfigDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create config directory: %v", err)
 	}
 
-	configFile := filepath.Join(appConfigDir, "config.toml")
-
 	manager := &ConfigManager{
 		configDir:  appConfigDir,
 		configFile: configFile,
 	}
+	manager.loadOverrides(options.Overrides, options.Missing)
+	return manager, nil
+}
 
-	// 加载配置
-	if err := manager.LoadConfig(); err != nil {
-		return nil, err
+// load 处理中断的档案切换后加载当前配置档案和配置文件，并清除配置副本中残留的明文密钥
+func (cm *ConfigManager) load() error {
+	if err := cm.repairProfileSwitch(); err != nil {
+		return err
 	}
-
-	return manager, nil
+	cm.loadActiveProfile()
+	if err := cm.LoadConfig(); err != nil {
+		return err
+	}
+	cm.scrubConfigCopies()
+	return nil
 }
 
 // GetDefaultConfig 获取默认配置
 func GetDefaultConfig() *Config {
 	return &Config{
+		SchemaVersion: CurrentSchemaVersion,
 		Global: GlobalConfig{
-			Theme: "light",
+			Theme:        "light",
+			HistoryLimit: defaultConfigHistoryLimit,
 		},
 		EduExp: EduExpConfig{
 			ArkApiKey:        "",
@@ -124,73 +262,394 @@ func GetDefaultConfig() *Config {
 			UserLimit:    1,
 			FeatureFlags: []string{},
 		},
+		Services: ServicesConfig{
+			DefaultProfile: "",
+			RestartMode:    RestartModeStopStart,
+			ReadyTimeout:   30,
+			DrainTimeout:   30,
+			HealthPaths: map[string]string{
+				"workflowui": "/",
+				"edu-tools":  "/",
+			},
+			BindAddresses: map[string]string{
+				"gateway":    "127.0.0.1",
+				"fileserver": "127.0.0.1",
+				"workflowui": "127.0.0.1",
+				"edu-tools":  "127.0.0.1",
+			},
+			Profiles: map[string]ServiceProfile{
+				"workflow": {
+					Name:     "仅工作流",
+					Services: []string{"workflowui"},
+				},
+				"workflow-files": {
+					Name:     "工作流 + 文件服务",
+					Services: []string{"workflowui", "fileserver"},
+				},
+				"all": {
+					Name:     "全部服务",
+					Services: []string{"workflowui", "edu-tools", "fileserver"},
+				},
+			},
+		},
+		FileServer: FileServerConfig{
+			Port:      "8081",
+			Root:      "",
+			Browse:    true,
+			Gzip:      true,
+			AccessLog: true,
+		},
+		Binaries: BinariesConfig{
+			ManifestURL:     "",
+			SignaturePolicy: SignaturePolicyWarn,
+			KeepVersions:    3,
+			AutoUpdate:      false,
+			Pinned:          map[string]string{},
+			Trusted:         map[string]string{},
+		},
+		Gateway: GatewayConfig{
+			Enabled: true,
+			Port:    "8000",
+			Routing: GatewayRoutingPath,
+		},
+		LAN: LANConfig{
+			Enabled:     false,
+			BindAddress: "0.0.0.0",
+			AuthMode:    LANAuthToken,
+			Token:       "",
+			Password:    "",
+			SessionTTL:  240,
+			MaxClients:  defaultLANMaxClients,
+		},
+		TLS: TLSConfig{
+			Enabled: false,
+			Hosts:   []string{},
+		},
 	}
 }
 
 // LoadConfig 加载配置
+// 旧版本的配置文件会按迁移列表升级，升级前的文件备份为 config.toml.v<版本>.bak，备份中的明文密钥替换为引用
 func (cm *ConfigManager) LoadConfig() error {
+	previous := cm.snapshot()
+	config, err := cm.readConfig()
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		cm.enqueueNotify(configEvent{previous: previous, next: config})
+		cm.deliverNotifications()
+	}
+	return nil
+}
+
+// readConfig 读取并迁移配置文件，替换当前配置
+func (cm *ConfigManager) readConfig() (*Config, error) {
 	// 如果配置文件不存在，创建默认配置
 	if _, err := os.Stat(cm.configFile); os.IsNotExist(err) {
-		cm.config = GetDefaultConfig()
-		return cm.SaveConfig()
+		return cm.replaceConfig(GetDefaultConfig(), nil)
 	}
 
 	// 读取配置文件
+	data, err := os.ReadFile(cm.configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var config *Config
+	var from int
+	raw, err := decodeRawConfig(data)
+	if err == nil {
+		config, from, err = buildConfig(raw)
+	}
+	if err != nil {
+		// 解析失败时备份原文件，保留仍能解析的配置段，其余配置段使用默认值
+		recovered, err := cm.recoverConfig(data, err)
+		if err != nil {
+			return nil, err
+		}
+		return cm.replaceConfig(recovered, nil)
+	}
+
+	if from >= CurrentSchemaVersion {
+		return cm.replaceConfig(config, data)
+	}
+
+	// 保存迁移结果前备份原文件
+	backupFile := fmt.Sprintf("%s.v%d.bak", cm.configFile, from)
+	if err := cm.writeConfigCopy(backupFile, data, config); err != nil {
+		return nil, fmt.Errorf("failed to back up config before migration: %v", err)
+	}
+	return cm.replaceConfig(config, nil)
+}
+
+// decodeRawConfig 将配置文件解析为原始键值
+func decodeRawConfig(data []byte) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// buildConfig 迁移原始键值并解析为配置结构，返回迁移前的版本
+func buildConfig(raw map[string]interface{}) (*Config, int, error) {
+	from, err := migrateConfig(raw)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to migrate config: %v", err)
+	}
+
+	// 迁移后的键值重新编码后解析为配置结构
+	var migrated bytes.Buffer
+	if err := toml.NewEncoder(&migrated).Encode(raw); err != nil {
+		return nil, 0, fmt.Errorf("failed to encode migrated config: %v", err)
+	}
 	var config Config
-	if _, err := toml.DecodeFile(cm.configFile, &config); err != nil {
-		// 如果解析失败，使用默认配置并备份原文件
-		cm.backupCorruptedConfig()
-		cm.config = GetDefaultConfig()
-		return cm.SaveConfig()
+	meta, err := toml.Decode(migrated.String(), &config)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode migrated config: %v", err)
 	}
+	applyConfigDefaults(&config, meta)
+	return &config, from, nil
+}
 
-	cm.config = &config
-	return nil
+// replaceConfig 替换当前配置
+// data 为读取到的配置文件内容，为 nil 时表示需要将配置写入文件
+func (cm *ConfigManager) replaceConfig(config *Config, data []byte) (*Config, error) {
+	cm.writeMu.Lock()
+	defer cm.writeMu.Unlock()
+
+	// 加载、恢复或导入的配置中的明文密钥先加密保存，再写入配置文件和历史版本
+	if cm.sealPlaintextSecrets(config) {
+		data = nil
+	}
+	if data == nil {
+		if err := cm.writeConfig(config); err != nil {
+			return nil, err
+		}
+	} else {
+		cm.recordDiskState(data)
+		cm.recordHistory(data)
+	}
+	cm.assignWorkflowRevisions(config, cm.snapshot())
+	cm.mu.Lock()
+	cm.config = config
+	cm.problems = cm.validate(config)
+	cm.mu.Unlock()
+	return config, nil
 }
 
-// SaveConfig 保存配置
+// SaveConfig 保存当前配置，环境变量和命令行参数的覆盖不会写入配置文件
 func (cm *ConfigManager) SaveConfig() error {
-	file, err := os.Create(cm.configFile)
+	cm.writeMu.Lock()
+	defer cm.writeMu.Unlock()
+	return cm.writeConfig(cm.snapshot())
+}
+
+// writeConfig 将配置写入文件，调用方需持有 cm.writeMu
+// 先写入临时文件再替换原文件，并通过锁文件防止多个应用实例同时写入
+func (cm *ConfigManager) writeConfig(config *Config) error {
+	buf, err := encodeConfig(config)
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockFile(cm.configFile+".lock", configLockTimeout)
 	if err != nil {
-		return fmt.Errorf("failed to create config file: %v", err)
+		return fmt.Errorf("failed to lock config file: %v", err)
 	}
-	defer file.Close()
+	defer lock.Unlock()
 
-	encoder := toml.NewEncoder(file)
-	if err := encoder.Encode(cm.config); err != nil {
-		return fmt.Errorf("failed to encode config: %v", err)
+	return cm.writeConfigLocked(buf)
+}
+
+// encodeConfig 将配置编码为 TOML
+func encodeConfig(config *Config) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %v", err)
 	}
+	return buf.Bytes(), nil
+}
 
+// writeConfigLocked 写入已编码的配置，记录文件状态和历史版本，调用方需持有 cm.writeMu 和配置文件锁
+func (cm *ConfigManager) writeConfigLocked(data []byte) error {
+	if err := writeFileAtomic(cm.configFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	cm.recordDiskState(data)
+	cm.recordHistory(data)
 	return nil
 }
 
-// GetConfig 获取当前配置
+// GetConfig 获取当前生效配置的副本，包含环境变量和命令行参数的覆盖
 func (cm *ConfigManager) GetConfig() *Config {
+	config := cm.snapshot()
+	if config == nil {
+		return nil
+	}
+	return cm.applyOverrides(config.Clone())
+}
+
+// FileConfig 获取配置文件层的副本，不含覆盖，用于导出、复制档案等需要保存配置的场景
+func (cm *ConfigManager) FileConfig() *Config {
+	config := cm.snapshot()
+	if config == nil {
+		return nil
+	}
+	return config.Clone()
+}
+
+// Problems 获取当前配置中的校验错误
+func (cm *ConfigManager) Problems() ValidationErrors {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return append(ValidationErrors{}, cm.problems...)
+}
+
+// validate 校验应用覆盖后的生效配置，并附加无法应用的覆盖
+func (cm *ConfigManager) validate(config *Config) ValidationErrors {
+	errs := append(ValidationErrors{}, cm.overrideErr...)
+	return append(errs, ValidateConfig(cm.applyOverrides(config.Clone()))...)
+}
+
+// snapshot 获取当前配置快照，返回值只读
+func (cm *ConfigManager) snapshot() *Config {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	return cm.config
 }
 
+// update 在当前配置的副本上应用修改，保存成功后替换当前配置并通知订阅者
+// 配置文件在上次读取后被外部修改时，先与外部修改合并再保存
+func (cm *ConfigManager) update(apply func(config *Config)) error {
+	return cm.updateChecked(func(config *Config) error {
+		apply(config)
+		return nil
+	})
+}
+
+// updateChecked 与 update 相同，apply 返回错误时放弃修改，用于需要在写锁内检查前置条件的修改
+func (cm *ConfigManager) updateChecked(apply func(config *Config) error) error {
+	_, err := cm.commitUpdate(apply)
+	return err
+}
+
+// commitUpdate 与 updateChecked 相同，返回保存后的配置（配置文件层）
+func (cm *ConfigManager) commitUpdate(apply func(config *Config) error) (*Config, error) {
+	cm.writeMu.Lock()
+	previous := cm.snapshot()
+	next := previous.Clone()
+	if err := apply(next); err != nil {
+		cm.writeMu.Unlock()
+		return nil, err
+	}
+	cm.keepOverriddenFileValues(previous, next)
+	cm.assignWorkflowRevisions(next, previous)
+
+	// 在去掉环境变量和命令行参数覆盖的值之后加密，避免覆盖的值被写入密钥存储
+	if cm.sealSecrets != nil {
+		if err := cm.sealSecrets(previous, next); err != nil {
+			cm.writeMu.Unlock()
+			return nil, err
+		}
+	}
+
+	// 只拒绝本次修改的配置段中的错误，避免其他配置段已有的问题阻止所有修改
+	if errs := ValidateConfig(next).inSections(changedSections(previous, next)); len(errs) > 0 {
+		cm.writeMu.Unlock()
+		return nil, errs
+	}
+
+	ours := next
+	next, external, err := cm.commit(previous, next)
+	if err != nil {
+		cm.writeMu.Unlock()
+		return nil, err
+	}
+	// 与外部修改合并后，外部修改的工作流分配新的修订号
+	cm.assignWorkflowRevisions(next, previous, ours)
+	cm.mu.Lock()
+	cm.config = next
+	cm.problems = cm.validate(next)
+	cm.mu.Unlock()
+	cm.enqueueNotify(configEvent{previous: previous, next: next, external: external})
+	cm.writeMu.Unlock()
+
+	cm.deliverNotifications()
+	return next, nil
+}
+
 // UpdateGlobalConfig 更新全局配置
 func (cm *ConfigManager) UpdateGlobalConfig(global GlobalConfig) error {
-	cm.config.Global = global
-	return cm.SaveConfig()
+	return cm.update(func(config *Config) {
+		config.Global = global
+	})
 }
 
 // UpdateEduExpConfig 更新EduExp配置
 func (cm *ConfigManager) UpdateEduExpConfig(eduexp EduExpConfig) error {
-	cm.config.EduExp = eduexp
-	return cm.SaveConfig()
+	return cm.update(func(config *Config) {
+		config.EduExp = eduexp
+	})
 }
 
-// UpdateWorkflowConfig 更新工作流配置
+// UpdateWorkflowConfig 更新工作流配置中的 API Key 和端口
+// 提交的工作流定义被忽略，避免覆盖其他窗口的修改；工作流定义通过 AddWorkflow、UpdateWorkflow 等按修订号修改
 func (cm *ConfigManager) UpdateWorkflowConfig(workflow WorkflowConfig) error {
-	cm.config.Workflow = workflow
-	return cm.SaveConfig()
+	return cm.update(func(config *Config) {
+		workflow.Workflows = config.Workflow.Workflows
+		config.Workflow = workflow
+	})
 }
 
 // UpdateLicenseConfig 更新许可配置
 func (cm *ConfigManager) UpdateLicenseConfig(license LicenseConfig) error {
-	cm.config.License = license
-	return cm.SaveConfig()
+	return cm.update(func(config *Config) {
+		config.License = license
+	})
+}
+
+// UpdateServicesConfig 更新服务编排配置
+func (cm *ConfigManager) UpdateServicesConfig(services ServicesConfig) error {
+	return cm.update(func(config *Config) {
+		config.Services = services
+	})
+}
+
+// UpdateFileServerConfig 更新文件服务器配置
+func (cm *ConfigManager) UpdateFileServerConfig(fileServer FileServerConfig) error {
+	return cm.update(func(config *Config) {
+		config.FileServer = fileServer
+	})
+}
+
+// UpdateBinariesConfig 更新二进制文件管理配置
+func (cm *ConfigManager) UpdateBinariesConfig(binaries BinariesConfig) error {
+	return cm.update(func(config *Config) {
+		config.Binaries = binaries
+	})
+}
+
+// UpdateGatewayConfig 更新网关配置
+func (cm *ConfigManager) UpdateGatewayConfig(gateway GatewayConfig) error {
+	return cm.update(func(config *Config) {
+		config.Gateway = gateway
+	})
+}
+
+// UpdateLANConfig 更新局域网共享配置
+func (cm *ConfigManager) UpdateLANConfig(lan LANConfig) error {
+	return cm.update(func(config *Config) {
+		config.LAN = lan
+	})
+}
+
+// UpdateTLSConfig 更新本地 HTTPS 配置
+func (cm *ConfigManager) UpdateTLSConfig(tlsConfig TLSConfig) error {
+	return cm.update(func(config *Config) {
+		config.TLS = tlsConfig
+	})
 }
 
 // GetConfigDir 获取配置目录
@@ -203,14 +662,16 @@ func (cm *ConfigManager) GetConfigFile() string {
 	return cm.configFile
 }
 
-// backupCorruptedConfig 备份损坏的配置文件
-func (cm *ConfigManager) backupCorruptedConfig() {
-	backupFile := cm.configFile + ".backup"
-	os.Rename(cm.configFile, backupFile)
-}
-
 // ResetToDefault 重置为默认配置
 func (cm *ConfigManager) ResetToDefault() error {
-	cm.config = GetDefaultConfig()
-	return cm.SaveConfig()
+	return cm.update(func(config *Config) {
+		*config = *GetDefaultConfig()
+	})
+}
+
+// ReplaceConfig 使用新配置替换当前配置，如导入的配置
+func (cm *ConfigManager) ReplaceConfig(replacement *Config) error {
+	return cm.update(func(config *Config) {
+		*config = *replacement.Clone()
+	})
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// 配置项变更类型
+const (
+	ConfigChangeAdded    = "added"    // 新增
+	ConfigChangeRemoved  = "removed"  // 删除
+	ConfigChangeModified = "modified" // 修改
+)
+
+// maskedSecret 差异中敏感配置项的显示值
+const maskedSecret = "******"
+
+// configSecretFields 敏感配置项，导出时脱敏或加密，差异中不显示原值
+var configSecretFields = []string{
+	"eduexp.ark_api_key",
+	"workflow.apikey",
+	"lan.token",
+	"lan.password",
+}
+
+// ConfigFieldChange 两个配置之间的配置项差异
+type ConfigFieldChange struct {
+	Path   string // 配置项路径，映射类配置项细化到键，如 workflow.workflows.essay
+	Action string // 变更类型: added/removed/modified
+	Old    string // 原值（JSON），新增时为空
+	New    string // 新值（JSON），删除时为空
+}
+
+// diffConfigs 比较两个配置，返回配置项级别的差异
+func diffConfigs(before, after *Config) []ConfigFieldChange {
+	changes := []ConfigFieldChange{}
+	bv := reflect.ValueOf(before).Elem()
+	av := reflect.ValueOf(after).Elem()
+
+	for i := 0; i < av.NumField(); i++ {
+		field := av.Type().Field(i)
+		name := configSectionName(field)
+		if field.Type.Kind() != reflect.Struct {
+			changes = appendFieldChange(changes, name, bv.Field(i), av.Field(i))
+			continue
+		}
+		for j := 0; j < field.Type.NumField(); j++ {
+			path := name + "." + configSectionName(field.Type.Field(j))
+			b, a := bv.Field(i).Field(j), av.Field(i).Field(j)
+			if b.Kind() == reflect.Map && b.Type().Key().Kind() == reflect.String {
+				changes = appendMapChanges(changes, path, b, a)
+			} else {
+				changes = appendFieldChange(changes, path, b, a)
+			}
+		}
+	}
+	return changes
+}
+
+// appendFieldChange 比较单个配置项
+func appendFieldChange(changes []ConfigFieldChange, path string, before, after reflect.Value) []ConfigFieldChange {
+	if reflect.DeepEqual(before.Interface(), after.Interface()) {
+		return changes
+	}
+	return append(changes, ConfigFieldChange{
+		Path:   path,
+		Action: ConfigChangeModified,
+		Old:    diffValue(path, before),
+		New:    diffValue(path, after),
+	})
+}
+
+// appendMapChanges 按键比较映射类配置项（工作流、服务组等）
+func appendMapChanges(changes []ConfigFieldChange, path string, before, after reflect.Value) []ConfigFieldChange {
+	keys := make(map[string]bool)
+	for _, key := range before.MapKeys() {
+		keys[key.String()] = true
+	}
+	for _, key := range after.MapKeys() {
+		keys[key.String()] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		keyPath := path + "." + key
+		b := before.MapIndex(reflect.ValueOf(key))
+		a := after.MapIndex(reflect.ValueOf(key))
+		switch {
+		case !b.IsValid():
+			changes = append(changes, ConfigFieldChange{Path: keyPath, Action: ConfigChangeAdded, New: diffValue(keyPath, a)})
+		case !a.IsValid():
+			changes = append(changes, ConfigFieldChange{Path: keyPath, Action: ConfigChangeRemoved, Old: diffValue(keyPath, b)})
+		default:
+			changes = appendFieldChange(changes, keyPath, b, a)
+		}
+	}
+	return changes
+}
+
+// diffValue 将配置值格式化为 JSON，敏感配置项只显示是否已设置
+func diffValue(path string, value reflect.Value) string {
+	if containsString(configSecretFields, path) {
+		if value.String() == "" {
+			return `""`
+		}
+		return maskedSecret
+	}
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
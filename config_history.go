@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// defaultConfigHistoryLimit 默认保留的配置历史版本数量
+const defaultConfigHistoryLimit = 50
+
+// ConfigVersionCurrent 表示当前配置的版本ID，用于与历史版本比较
+const ConfigVersionCurrent = "current"
+
+// configVersionLayout 历史版本文件名中的时间格式，按文件名排序即按时间排序
+const configVersionLayout = "20060102-150405.000"
+
+// ConfigVersion 配置历史版本，每次保存配置文件时记录
+type ConfigVersion struct {
+	ID       string    // 版本ID
+	SavedAt  time.Time // 保存时间
+	Sections []string  // 与上一个版本相比变化的配置段，最早的版本为空
+	Current  bool      // 是否与当前配置文件内容相同
+}
+
+// historyDir 当前配置档案的历史目录
+func (cm *ConfigManager) historyDir() string {
+	return cm.profileHistoryDir(cm.ActiveProfile())
+}
+
+// profileHistoryDir 配置档案的历史目录，默认档案沿用引入配置档案之前的目录
+func (cm *ConfigManager) profileHistoryDir(profile string) string {
+	if profile == DefaultConfigProfile {
+		return filepath.Join(cm.configDir, "history")
+	}
+	return filepath.Join(cm.profilesDir(), profile, "history")
+}
+
+// recordHistory 将写入的配置文件内容及其引用的密钥密文保存为历史版本，并删除超出保留数量的旧版本
+// 调用方需持有 cm.writeMu；历史记录失败不影响配置保存
+func (cm *ConfigManager) recordHistory(data []byte) {
+	var limits struct {
+		Global GlobalConfig `toml:"global"`
+	}
+	if _, err := toml.Decode(string(data), &limits); err != nil || limits.Global.HistoryLimit <= 0 {
+		return
+	}
+
+	ids, err := cm.historyIDs()
+	if err != nil {
+		cm.reportError(fmt.Errorf("failed to read config history: %v", err))
+		return
+	}
+	// 内容和引用的密钥都与最近的版本相同时不重复保存，只修改密钥时配置中的引用不变
+	secrets := cm.referencedSecrets(data)
+	if len(ids) > 0 {
+		latestID := ids[len(ids)-1]
+		if latest, err := os.ReadFile(cm.historyFile(latestID)); err == nil && bytes.Equal(latest, data) {
+			if saved, err := cm.loadHistorySecrets(latestID); err == nil && maps.Equal(saved, secrets) {
+				return
+			}
+		}
+	}
+
+	if err := os.MkdirAll(cm.historyDir(), 0700); err != nil {
+		cm.reportError(fmt.Errorf("failed to create config history directory: %v", err))
+		return
+	}
+	savedAt := time.Now()
+	id := savedAt.Format(configVersionLayout)
+	for len(ids) > 0 && id <= ids[len(ids)-1] {
+		savedAt = savedAt.Add(time.Millisecond)
+		id = savedAt.Format(configVersionLayout)
+	}
+	if len(secrets) > 0 {
+		encoded, err := json.Marshal(secrets)
+		if err == nil {
+			err = writeFileAtomic(cm.historySecretsFile(id), encoded, 0600)
+		}
+		if err != nil {
+			cm.reportError(fmt.Errorf("failed to save config history: %v", err))
+			return
+		}
+	}
+	if err := writeFileAtomic(cm.historyFile(id), data, 0600); err != nil {
+		os.Remove(cm.historySecretsFile(id))
+		cm.reportError(fmt.Errorf("failed to save config history: %v", err))
+		return
+	}
+
+	ids = append(ids, id)
+	for len(ids) > limits.Global.HistoryLimit {
+		if err := os.Remove(cm.historyFile(ids[0])); err != nil {
+			cm.reportError(fmt.Errorf("failed to remove old config version %s: %v", ids[0], err))
+		}
+		os.Remove(cm.historySecretsFile(ids[0]))
+		ids = ids[1:]
+	}
+}
+
+// reportError 报告不影响配置保存的后台操作失败
+func (cm *ConfigManager) reportError(err error) {
+	if cm.onError != nil {
+		cm.onError(err)
+	}
+}
+
+// historyFile 历史版本文件
+func (cm *ConfigManager) historyFile(id string) string {
+	return filepath.Join(cm.historyDir(), id+".toml")
+}
+
+// historySecretsFile 历史版本引用的密钥密文
+func (cm *ConfigManager) historySecretsFile(id string) string {
+	return filepath.Join(cm.historyDir(), id+".secrets.json")
+}
+
+// referencedSecrets 返回配置文件内容引用的密钥的密文
+func (cm *ConfigManager) referencedSecrets(data []byte) map[string]string {
+	if cm.secrets == nil {
+		return nil
+	}
+	config, err := parseConfigData(data)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, value := range configSecretValues(config) {
+		if IsSecretRef(*value) {
+			ids = append(ids, strings.TrimPrefix(*value, SecretRefPrefix))
+		}
+	}
+	return cm.secrets.Sealed(ids)
+}
+
+// loadHistorySecrets 读取历史版本保存的密钥密文，没有保存时返回 nil
+func (cm *ConfigManager) loadHistorySecrets(id string) (map[string]string, error) {
+	data, err := os.ReadFile(cm.historySecretsFile(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets of config version '%s': %v", id, err)
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets of config version '%s': %v", id, err)
+	}
+	return secrets, nil
+}
+
+// changedSecretSections 返回密钥密文发生变化的配置段
+func changedSecretSections(before, after map[string]string) []string {
+	var sections []string
+	for _, secrets := range []map[string]string{before, after} {
+		for id := range secrets {
+			if before[id] != after[id] {
+				sections = mergeSections(sections, []string{secretSection(id)})
+			}
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// mergeSections 合并配置段列表，去掉重复项
+func mergeSections(lists ...[]string) []string {
+	var merged []string
+	for _, list := range lists {
+		for _, section := range list {
+			if !containsString(merged, section) {
+				merged = append(merged, section)
+			}
+		}
+	}
+	return merged
+}
+
+// secretSection 密钥ID所属的配置段，如 profiles/<名称>/workflow.apikey 属于 workflow
+func secretSection(id string) string {
+	id = id[strings.LastIndex(id, "/")+1:]
+	section, _, _ := strings.Cut(id, ".")
+	return section
+}
+
+// historyIDs 按时间从旧到新列出历史版本ID
+func (cm *ConfigManager) historyIDs() ([]string, error) {
+	entries, err := os.ReadDir(cm.historyDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), ".toml")
+		if entry.IsDir() || id == entry.Name() {
+			continue
+		}
+		if _, err := time.ParseInLocation(configVersionLayout, id, time.Local); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// ListHistory 列出配置历史版本，最新的版本在前
+func (cm *ConfigManager) ListHistory() ([]ConfigVersion, error) {
+	ids, err := cm.historyIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config history: %v", err)
+	}
+	current, _ := os.ReadFile(cm.configFile)
+	currentSecrets := cm.referencedSecrets(current)
+
+	versions := make([]ConfigVersion, 0, len(ids))
+	var previous *Config
+	var previousSecrets map[string]string
+	for _, id := range ids {
+		data, err := os.ReadFile(cm.historyFile(id))
+		if err != nil {
+			continue
+		}
+		secrets, _ := cm.loadHistorySecrets(id)
+		version := ConfigVersion{ID: id, Current: bytes.Equal(data, current) && maps.Equal(secrets, currentSecrets)}
+		version.SavedAt, _ = time.ParseInLocation(configVersionLayout, id, time.Local)
+
+		config, err := parseConfigData(data)
+		if err == nil {
+			if previous != nil {
+				version.Sections = mergeSections(changedSections(previous, config), changedSecretSections(previousSecrets, secrets))
+			}
+			previous = config
+			previousSecrets = secrets
+		}
+		versions = append(versions, version)
+	}
+
+	// 最新的版本在前
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
+
+// LoadVersion 读取历史版本的配置，ConfigVersionCurrent 返回当前配置文件中的配置
+func (cm *ConfigManager) LoadVersion(id string) (*Config, error) {
+	if id == ConfigVersionCurrent {
+		return cm.FileConfig(), nil
+	}
+	if _, err := time.ParseInLocation(configVersionLayout, id, time.Local); err != nil {
+		return nil, fmt.Errorf("invalid config version '%s'", id)
+	}
+
+	data, err := os.ReadFile(cm.historyFile(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("config version '%s' not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config version '%s': %v", id, err)
+	}
+	return parseConfigData(data)
+}
+
+// DiffVersions 比较两个配置版本
+func (cm *ConfigManager) DiffVersions(from, to string) ([]ConfigFieldChange, error) {
+	before, err := cm.LoadVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := cm.LoadVersion(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffConfigs(before, after), nil
+}
+
+// RestoreVersion 将配置和当时的密钥恢复为历史版本，恢复操作本身也会保存为新的历史版本，可以撤销
+func (cm *ConfigManager) RestoreVersion(id string) error {
+	config, err := cm.LoadVersion(id)
+	if err != nil {
+		return err
+	}
+	if id == ConfigVersionCurrent || cm.secrets == nil {
+		return cm.ReplaceConfig(config)
+	}
+
+	saved, err := cm.loadHistorySecrets(id)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(saved))
+	for secretID := range saved {
+		ids = append(ids, secretID)
+	}
+	current := cm.secrets.Sealed(ids)
+	if err := cm.secrets.RestoreSealed(saved); err != nil {
+		return err
+	}
+	if err := cm.ReplaceConfig(config); err != nil {
+		// 配置未恢复时撤销密钥的修改
+		cm.secrets.RestoreSealed(current)
+		return err
+	}
+	return nil
+}
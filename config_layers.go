@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// 配置项的来源，按优先级从低到高排列
+const (
+	ConfigSourceDefault = "default" // 配置文件中未设置，使用默认值
+	ConfigSourceFile    = "file"    // 配置文件
+	ConfigSourceEnv     = "env"     // 环境变量，如 EDUEXP_EDUEXP_EDU_TOOLS_PORT
+	ConfigSourceFlag    = "flag"    // 命令行参数 --set section.key=value
+)
+
+// configEnvPrefix 覆盖配置项的环境变量前缀
+const configEnvPrefix = "EDUEXP_"
+
+// ConfigOptions 命令行中的配置选项
+type ConfigOptions struct {
+	File      string   // --config 指定的配置文件，为空时使用用户配置目录下的 config.toml
+	Overrides []string // --set 覆盖的配置项，格式为 section.key=value
+	Missing   []string // 缺少值的参数，如末尾的 --set
+}
+
+// ConfigValueSource 配置项当前生效的值及其来源
+type ConfigValueSource struct {
+	Path   string // 配置项路径，如 eduexp.edu_tools_port
+	Value  string // 生效的值（JSON），敏感配置项不显示原值
+	Source string // 来源: default/file/env/flag
+	Origin string // 来源为 env/flag 时的环境变量名或命令行参数
+}
+
+// configOverride 环境变量或命令行参数对配置项的覆盖
+// 覆盖只作用于 GetConfig 返回的生效配置，不会写入配置文件
+type configOverride struct {
+	path   string // 配置项路径
+	value  string // 覆盖值
+	source string // 来源: env/flag
+	origin string // 环境变量名或命令行参数
+}
+
+// ParseConfigFlags 解析命令行中的 --config 和 --set 参数，忽略其他参数
+func ParseConfigFlags(args []string) ConfigOptions {
+	var options ConfigOptions
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || (name != "config" && name != "set") {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				options.Missing = append(options.Missing, "--"+name)
+				continue
+			}
+			i++
+			value = args[i]
+		}
+
+		if name == "config" {
+			options.File = value
+		} else {
+			options.Overrides = append(options.Overrides, value)
+		}
+	}
+	return options
+}
+
+// loadOverrides 按环境变量、命令行参数的顺序收集配置覆盖，后者优先
+// 无法应用的覆盖和缺少值的参数记录为校验错误，不影响其他配置项
+func (cm *ConfigManager) loadOverrides(flags, missing []string) {
+	for _, flag := range missing {
+		cm.overrideErr = append(cm.overrideErr, ValidationError{
+			Field:   flag,
+			Code:    ValidationRequired,
+			Message: fmt.Sprintf("missing value for %s", flag),
+		})
+	}
+
+	probe := GetDefaultConfig()
+	add := func(override configOverride) {
+		field, err := configFieldByPath(probe, override.path)
+		if err == nil {
+			err = setConfigField(field, override.value)
+		}
+		if err != nil {
+			cm.overrideErr = append(cm.overrideErr, ValidationError{
+				Field:   override.path,
+				Code:    ValidationInvalidValue,
+				Message: fmt.Sprintf("invalid override %s: %v", override.origin, err),
+			})
+			return
+		}
+		cm.overrides = append(cm.overrides, override)
+	}
+
+	for _, path := range configFieldPaths() {
+		name := configEnvName(path)
+		if value, exists := os.LookupEnv(name); exists {
+			add(configOverride{path: path, value: value, source: ConfigSourceEnv, origin: name})
+		}
+	}
+	for _, flag := range flags {
+		path, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			cm.overrideErr = append(cm.overrideErr, ValidationError{
+				Field:   flag,
+				Code:    ValidationInvalidFormat,
+				Message: fmt.Sprintf("invalid override --set %s: expected section.key=value", flag),
+			})
+			continue
+		}
+		add(configOverride{path: strings.TrimSpace(path), value: value, source: ConfigSourceFlag, origin: "--set " + flag})
+	}
+}
+
+// applyConfigDefaults 配置文件中未设置的配置项使用默认值
+// 只处理字符串、数值和布尔类型：空的映射和列表在保存时会被省略，不能视为未设置
+func applyConfigDefaults(config *Config, meta toml.MetaData) {
+	defaults := GetDefaultConfig()
+	for _, path := range configFieldPaths() {
+		field, _ := configFieldByPath(config, path)
+		switch field.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int:
+		default:
+			continue
+		}
+		if section, key := splitConfigPath(path); !meta.IsDefined(section, key) {
+			defaultField, _ := configFieldByPath(defaults, path)
+			field.Set(defaultField)
+		}
+	}
+}
+
+// applyOverrides 将覆盖应用到配置上并返回该配置，调用方需传入副本
+func (cm *ConfigManager) applyOverrides(config *Config) *Config {
+	for _, override := range cm.overrides {
+		if field, err := configFieldByPath(config, override.path); err == nil {
+			setConfigField(field, override.value)
+		}
+	}
+	return config
+}
+
+// keepOverriddenFileValues 被覆盖的配置项仍等于覆盖值时恢复配置文件中的原值，避免覆盖值被写入配置文件
+// 调用方通常先通过 GetConfig 获取生效配置再整段更新，未修改的被覆盖配置项因此等于覆盖值
+func (cm *ConfigManager) keepOverriddenFileValues(previous, next *Config) {
+	if len(cm.overrides) == 0 {
+		return
+	}
+	effective := cm.applyOverrides(previous.Clone())
+	for _, override := range cm.overrides {
+		nextField, err := configFieldByPath(next, override.path)
+		if err != nil {
+			continue
+		}
+		effectiveField, _ := configFieldByPath(effective, override.path)
+		previousField, _ := configFieldByPath(previous, override.path)
+		if reflect.DeepEqual(nextField.Interface(), effectiveField.Interface()) {
+			nextField.Set(previousField)
+		}
+	}
+}
+
+// Sources 返回每个配置项当前生效的值及其来源
+func (cm *ConfigManager) Sources() []ConfigValueSource {
+	effective := cm.GetConfig()
+	if effective == nil {
+		return []ConfigValueSource{}
+	}
+
+	// 配置文件中定义的配置项
+	var meta toml.MetaData
+	if data, err := os.ReadFile(cm.configFile); err == nil {
+		var raw map[string]interface{}
+		meta, _ = toml.Decode(string(data), &raw)
+	}
+
+	overrides := make(map[string]configOverride)
+	for _, override := range cm.overrides {
+		overrides[override.path] = override
+	}
+
+	paths := configFieldPaths()
+	sources := make([]ConfigValueSource, 0, len(paths))
+	for _, path := range paths {
+		field, _ := configFieldByPath(effective, path)
+		source := ConfigValueSource{Path: path, Value: diffValue(path, field), Source: ConfigSourceDefault}
+		if override, exists := overrides[path]; exists {
+			source.Source = override.source
+			source.Origin = override.origin
+		} else if section, key := splitConfigPath(path); meta.IsDefined(section, key) {
+			source.Source = ConfigSourceFile
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// configFieldPaths 返回所有配置项的路径
+func configFieldPaths() []string {
+	var paths []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			paths = append(paths, configSectionName(section)+"."+configSectionName(section.Type.Field(j)))
+		}
+	}
+	return paths
+}
+
+// configEnvName 配置项对应的环境变量名，如 eduexp.edu_tools_port -> EDUEXP_EDUEXP_EDU_TOOLS_PORT
+func configEnvName(path string) string {
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(path)
+	return configEnvPrefix + strings.ToUpper(name)
+}
+
+// configFieldByPath 按路径获取配置项
+func configFieldByPath(config *Config, path string) (reflect.Value, error) {
+	sectionName, key := splitConfigPath(path)
+	v := reflect.ValueOf(config).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		section := v.Type().Field(i)
+		if section.Type.Kind() != reflect.Struct || configSectionName(section) != sectionName {
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			if configSectionName(section.Type.Field(j)) == key {
+				return v.Field(i).Field(j), nil
+			}
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown config key '%s'", path)
+}
+
+// setConfigField 将字符串形式的值解析后写入配置项，字符串列表使用逗号分隔
+func setConfigField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a boolean", value)
+		}
+		field.SetBool(parsed)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("'%s' is not an integer", value)
+		}
+		field.SetInt(int64(parsed))
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("this setting cannot be overridden")
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("this setting cannot be overridden")
+	}
+	return nil
+}
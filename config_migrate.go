@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+)
+
+// CurrentSchemaVersion 当前配置文件结构版本，新增迁移时同步递增
+const CurrentSchemaVersion = 5
+
+// configMigration 配置迁移，将配置文件从 Version-1 升级到 Version
+// 迁移直接修改解析后的原始键值，因此可以处理键的重命名和缺失键的默认值；
+// 引入版本号之前的配置文件均视为版本 0，迁移需要能重复执行
+type configMigration struct {
+	Version     int                                    // 迁移完成后的版本
+	Description string                                 // 迁移说明
+	Migrate     func(raw map[string]interface{}) error // 迁移函数
+}
+
+// configMigrations 按版本排列的迁移列表
+var configMigrations = []configMigration{
+	{
+		Version:     1,
+		Description: "添加默认服务组",
+		Migrate:     migrateServiceProfiles,
+	},
+	{
+		Version:     2,
+		Description: "内置文件服务器替代 caddy",
+		Migrate:     migrateBuiltinFileServer,
+	},
+	{
+		Version:     3,
+		Description: "默认启用本地网关",
+		Migrate:     migrateGatewayEnabled,
+	},
+	{
+		Version:     4,
+		Description: "保留配置历史版本",
+		Migrate:     migrateHistoryLimit,
+	},
+	{
+		Version:     5,
+		Description: "WorkflowUI 和 EduTools 默认只监听本机",
+		Migrate:     migrateServiceBindAddresses,
+	},
+}
+
+// migrateConfig 依次执行配置文件版本之后的迁移，返回迁移前的版本
+// 配置文件版本高于当前版本时（由更新的应用写入）不做修改
+func migrateConfig(raw map[string]interface{}) (int, error) {
+	from, err := rawSchemaVersion(raw)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, migration := range configMigrations {
+		if migration.Version <= from {
+			continue
+		}
+		if err := migration.Migrate(raw); err != nil {
+			return from, fmt.Errorf("migration to version %d (%s) failed: %v", migration.Version, migration.Description, err)
+		}
+		raw["schema_version"] = int64(migration.Version)
+	}
+	return from, nil
+}
+
+// rawSchemaVersion 读取配置文件的结构版本，没有版本号时为 0
+func rawSchemaVersion(raw map[string]interface{}) (int, error) {
+	value, exists := raw["schema_version"]
+	if !exists {
+		return 0, nil
+	}
+	version, ok := value.(int64)
+	if !ok || version < 0 {
+		return 0, fmt.Errorf("invalid schema_version: %v", value)
+	}
+	return int(version), nil
+}
+
+// migrateServiceProfiles 引入服务组之前的配置文件没有服务组定义，补全默认服务组
+func migrateServiceProfiles(raw map[string]interface{}) error {
+	services, err := rawSection(raw, "services")
+	if err != nil {
+		return err
+	}
+	if _, exists := services["profiles"]; exists {
+		return nil
+	}
+
+	services["profiles"] = map[string]interface{}{
+		"workflow": map[string]interface{}{
+			"name":     "仅工作流",
+			"services": []interface{}{"workflowui"},
+		},
+		"workflow-files": map[string]interface{}{
+			"name":     "工作流 + 文件服务",
+			"services": []interface{}{"workflowui", "fileserver"},
+		},
+		"all": map[string]interface{}{
+			"name":     "全部服务",
+			"services": []interface{}{"workflowui", "edu-tools", "fileserver"},
+		},
+	}
+	return nil
+}
+
+// migrateBuiltinFileServer 将服务组中的 caddy-fileserver 替换为 fileserver，
+// 并为旧配置补全内置文件服务器新增的开关（默认开启）
+func migrateBuiltinFileServer(raw map[string]interface{}) error {
+	fileServer, err := rawSection(raw, "fileserver")
+	if err != nil {
+		return err
+	}
+	for _, key := range []string{"browse", "gzip", "access_log"} {
+		if _, exists := fileServer[key]; !exists {
+			fileServer[key] = true
+		}
+	}
+
+	services, err := rawSection(raw, "services")
+	if err != nil {
+		return err
+	}
+	profiles, _ := services["profiles"].(map[string]interface{})
+	for _, value := range profiles {
+		profile, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		names, _ := profile["services"].([]interface{})
+		for i, name := range names {
+			if name == "caddy-fileserver" {
+				names[i] = "fileserver"
+			}
+		}
+	}
+	return nil
+}
+
+// migrateGatewayEnabled 引入网关之前的配置文件没有网关开关，补全为默认启用
+func migrateGatewayEnabled(raw map[string]interface{}) error {
+	gateway, err := rawSection(raw, "gateway")
+	if err != nil {
+		return err
+	}
+	if _, exists := gateway["enabled"]; !exists {
+		gateway["enabled"] = true
+	}
+	return nil
+}
+
+// migrateHistoryLimit 引入配置历史之前的配置文件没有保留数量，补全为默认值
+func migrateHistoryLimit(raw map[string]interface{}) error {
+	global, err := rawSection(raw, "global")
+	if err != nil {
+		return err
+	}
+	if _, exists := global["history_limit"]; !exists {
+		global["history_limit"] = int64(defaultConfigHistoryLimit)
+	}
+	return nil
+}
+
+// migrateServiceBindAddresses 旧配置只限制了网关和文件服务器的监听地址，
+// WorkflowUI 和 EduTools 使用各自的默认地址，开启局域网模式后可以绕过网关认证直接访问，补全为只监听本机
+func migrateServiceBindAddresses(raw map[string]interface{}) error {
+	services, err := rawSection(raw, "services")
+	if err != nil {
+		return err
+	}
+	bindAddresses, exists := services["bind_addresses"].(map[string]interface{})
+	if !exists {
+		if _, invalid := services["bind_addresses"]; invalid {
+			return fmt.Errorf("'services.bind_addresses' is not a table")
+		}
+		bindAddresses = make(map[string]interface{})
+		services["bind_addresses"] = bindAddresses
+	}
+	for _, name := range []string{"workflowui", "edu-tools"} {
+		if _, exists := bindAddresses[name]; !exists {
+			bindAddresses[name] = "127.0.0.1"
+		}
+	}
+	return nil
+}
+
+// rawSection 获取原始配置中的表，不存在时创建
+func rawSection(raw map[string]interface{}, name string) (map[string]interface{}, error) {
+	value, exists := raw[name]
+	if !exists {
+		section := make(map[string]interface{})
+		raw[name] = section
+		return section, nil
+	}
+	section, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a table", name)
+	}
+	return section, nil
+}
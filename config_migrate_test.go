@@ -0,0 +1,475 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// loadSampleConfig 将 testdata 中的配置文件复制到临时目录并加载
+func loadSampleConfig(t *testing.T, sample string) *ConfigManager {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "config", sample))
+	if err != nil {
+		t.Fatalf("failed to read sample %s: %v", sample, err)
+	}
+
+	dir := t.TempDir()
+	cm := &ConfigManager{configDir: dir, configFile: filepath.Join(dir, "config.toml")}
+	if err := os.WriteFile(cm.configFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.LoadConfig(); err != nil {
+		t.Fatalf("failed to load %s: %v", sample, err)
+	}
+	return cm
+}
+
+// reloadConfig 重新从磁盘加载配置，用于确认迁移结果已保存
+func reloadConfig(t *testing.T, cm *ConfigManager) *Config {
+	t.Helper()
+
+	reloaded := &ConfigManager{configDir: cm.configDir, configFile: cm.configFile}
+	if err := reloaded.LoadConfig(); err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	return reloaded.config
+}
+
+// assertBackup 检查迁移前的原文件已按版本备份
+func assertBackup(t *testing.T, cm *ConfigManager, sample string, version int) {
+	t.Helper()
+
+	original, err := os.ReadFile(filepath.Join("testdata", "config", sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(fmt.Sprintf("%s.v%d.bak", cm.configFile, version))
+	if err != nil {
+		t.Fatalf("pre-migration backup missing: %v", err)
+	}
+	if string(backup) != string(original) {
+		t.Fatalf("backup does not match the original file")
+	}
+}
+
+func TestConfigMigrationsAreOrdered(t *testing.T) {
+	for i, migration := range configMigrations {
+		if migration.Version != i+1 {
+			t.Fatalf("migration %d has version %d, want %d", i, migration.Version, i+1)
+		}
+		if migration.Migrate == nil || migration.Description == "" {
+			t.Fatalf("migration %d is incomplete", migration.Version)
+		}
+	}
+	if last := configMigrations[len(configMigrations)-1].Version; last != CurrentSchemaVersion {
+		t.Fatalf("last migration is version %d, CurrentSchemaVersion is %d", last, CurrentSchemaVersion)
+	}
+	if GetDefaultConfig().SchemaVersion != CurrentSchemaVersion {
+		t.Fatalf("default config does not use the current schema version")
+	}
+}
+
+func TestMigrateBaselineConfig(t *testing.T) {
+	cm := loadSampleConfig(t, "v0-baseline.toml")
+	assertBackup(t, cm, "v0-baseline.toml", 0)
+
+	for _, config := range []*Config{cm.config, reloadConfig(t, cm)} {
+		if config.SchemaVersion != CurrentSchemaVersion {
+			t.Fatalf("schema version = %d, want %d", config.SchemaVersion, CurrentSchemaVersion)
+		}
+
+		// 原有配置保持不变
+		if config.Global.Theme != "dark" || config.EduExp.ArkApiKey != "ark-key" || config.EduExp.EduToolsPort != "8090" {
+			t.Fatalf("existing settings changed: %+v %+v", config.Global, config.EduExp)
+		}
+		if config.Workflow.ApiKey != "coze-key" || config.License.UserLimit != 5 {
+			t.Fatalf("existing settings changed: %+v %+v", config.Workflow, config.License)
+		}
+		essay, exists := config.Workflow.Workflows["essay"]
+		if !exists || essay.WorkflowID != "7400000000000000001" || len(essay.Parameters) != 1 || !essay.Parameters[0].Required {
+			t.Fatalf("workflow definition changed: %+v", config.Workflow.Workflows)
+		}
+
+		// 之后新增的配置使用默认值
+		if !reflect.DeepEqual(config.Services.Profiles, GetDefaultConfig().Services.Profiles) {
+			t.Fatalf("default profiles not added: %+v", config.Services.Profiles)
+		}
+		if config.Global.HistoryLimit != defaultConfigHistoryLimit {
+			t.Fatalf("history_limit = %d, want %d", config.Global.HistoryLimit, defaultConfigHistoryLimit)
+		}
+		if !config.FileServer.Browse || !config.FileServer.Gzip || !config.FileServer.AccessLog {
+			t.Fatalf("file server switches not defaulted: %+v", config.FileServer)
+		}
+		if !config.Gateway.Enabled {
+			t.Fatalf("gateway not enabled by default")
+		}
+		if config.Services.BindAddresses["workflowui"] != "127.0.0.1" || config.Services.BindAddresses["edu-tools"] != "127.0.0.1" {
+			t.Fatalf("services not bound to localhost: %+v", config.Services.BindAddresses)
+		}
+	}
+}
+
+func TestMigrateCaddyConfig(t *testing.T) {
+	cm := loadSampleConfig(t, "v0-caddy.toml")
+	assertBackup(t, cm, "v0-caddy.toml", 0)
+
+	for _, config := range []*Config{cm.config, reloadConfig(t, cm)} {
+		if config.SchemaVersion != CurrentSchemaVersion {
+			t.Fatalf("schema version = %d, want %d", config.SchemaVersion, CurrentSchemaVersion)
+		}
+
+		// 自定义服务组保留，caddy-fileserver 改名为 fileserver，且不添加默认服务组
+		if len(config.Services.Profiles) != 1 {
+			t.Fatalf("profiles = %+v, want only the custom profile", config.Services.Profiles)
+		}
+		classroom := config.Services.Profiles["classroom"]
+		if !reflect.DeepEqual(classroom.Services, []string{"workflowui", "fileserver"}) {
+			t.Fatalf("classroom services = %v", classroom.Services)
+		}
+		if config.Services.DefaultProfile != "classroom" {
+			t.Fatalf("default profile = %q", config.Services.DefaultProfile)
+		}
+
+		if config.FileServer.Port != "9000" || config.FileServer.Root != "/srv/share" {
+			t.Fatalf("file server settings changed: %+v", config.FileServer)
+		}
+		if !config.FileServer.Browse || !config.FileServer.Gzip || !config.FileServer.AccessLog {
+			t.Fatalf("file server switches not defaulted: %+v", config.FileServer)
+		}
+
+		if config.Binaries.SignaturePolicy != "enforce" || config.Binaries.KeepVersions != 2 ||
+			!config.Binaries.AutoUpdate || config.Binaries.Pinned["workflowui"] != "1.2.0" {
+			t.Fatalf("binaries settings changed: %+v", config.Binaries)
+		}
+		if !config.Gateway.Enabled {
+			t.Fatalf("gateway not enabled by default")
+		}
+		if config.Services.BindAddresses["workflowui"] != "127.0.0.1" || config.Services.BindAddresses["edu-tools"] != "127.0.0.1" {
+			t.Fatalf("services not bound to localhost: %+v", config.Services.BindAddresses)
+		}
+	}
+}
+
+func TestMigrateGatewayConfig(t *testing.T) {
+	cm := loadSampleConfig(t, "v0-gateway.toml")
+	assertBackup(t, cm, "v0-gateway.toml", 0)
+
+	for _, config := range []*Config{cm.config, reloadConfig(t, cm)} {
+		if config.SchemaVersion != CurrentSchemaVersion {
+			t.Fatalf("schema version = %d, want %d", config.SchemaVersion, CurrentSchemaVersion)
+		}
+
+		// 用户明确设置的值不会被迁移覆盖
+		if config.Gateway.Enabled || config.Gateway.Port != "8100" || config.Gateway.Routing != GatewayRoutingSubdomain {
+			t.Fatalf("gateway settings changed: %+v", config.Gateway)
+		}
+		if config.FileServer.Browse || !config.FileServer.Gzip || config.FileServer.AccessLog {
+			t.Fatalf("file server switches changed: %+v", config.FileServer)
+		}
+		if config.Services.RestartMode != RestartModeBlueGreen || config.Services.ReadyTimeout != 10 || config.Services.DrainTimeout != 5 {
+			t.Fatalf("services settings changed: %+v", config.Services)
+		}
+		if len(config.Services.Profiles) != 1 {
+			t.Fatalf("profiles = %+v, want only the existing profile", config.Services.Profiles)
+		}
+	}
+}
+
+func TestMigrateIntermediateConfigs(t *testing.T) {
+	tests := []struct {
+		sample  string
+		version int
+		check   func(t *testing.T, config *Config)
+	}{
+		{
+			// 版本 1 已有服务组，只将 caddy-fileserver 替换为 fileserver 并补全之后的配置
+			sample:  "v1-profiles.toml",
+			version: 1,
+			check: func(t *testing.T, config *Config) {
+				if len(config.Services.Profiles) != 2 || config.Services.DefaultProfile != "workflow-files" {
+					t.Fatalf("profiles changed: %+v", config.Services)
+				}
+				files := config.Services.Profiles["workflow-files"]
+				if !reflect.DeepEqual(files.Services, []string{"workflowui", "fileserver"}) {
+					t.Fatalf("workflow-files services = %v", files.Services)
+				}
+				if !config.FileServer.Browse || !config.FileServer.Gzip || !config.FileServer.AccessLog {
+					t.Fatalf("file server switches not defaulted: %+v", config.FileServer)
+				}
+				if config.FileServer.Root != "/srv/share" || config.EduExp.EduToolsPort != "8090" {
+					t.Fatalf("existing settings changed: %+v %+v", config.FileServer, config.EduExp)
+				}
+			},
+		},
+		{
+			// 版本 2 的文件服务器开关保持原值
+			sample:  "v2-fileserver.toml",
+			version: 2,
+			check: func(t *testing.T, config *Config) {
+				if config.FileServer.Browse || config.FileServer.Gzip || !config.FileServer.AccessLog {
+					t.Fatalf("file server switches changed: %+v", config.FileServer)
+				}
+				if len(config.Services.Profiles) != 1 {
+					t.Fatalf("profiles = %+v, want only the existing profile", config.Services.Profiles)
+				}
+				if !config.Gateway.Enabled {
+					t.Fatalf("gateway not enabled by default")
+				}
+			},
+		},
+		{
+			// 版本 3 中关闭的网关保持关闭
+			sample:  "v3-gateway.toml",
+			version: 3,
+			check: func(t *testing.T, config *Config) {
+				if config.Gateway.Enabled || config.Gateway.Port != "8100" {
+					t.Fatalf("gateway settings changed: %+v", config.Gateway)
+				}
+				if config.Global.HistoryLimit != defaultConfigHistoryLimit {
+					t.Fatalf("history_limit = %d, want %d", config.Global.HistoryLimit, defaultConfigHistoryLimit)
+				}
+			},
+		},
+		{
+			// 版本 4 没有 services.bind_addresses，删除了全部服务组，迁移不能重新添加默认服务组
+			sample:  "v4-history.toml",
+			version: 4,
+			check: func(t *testing.T, config *Config) {
+				if config.Global.HistoryLimit != 3 {
+					t.Fatalf("history_limit = %d, want 3", config.Global.HistoryLimit)
+				}
+				if len(config.Services.Profiles) != 0 {
+					t.Fatalf("default profiles added again: %+v", config.Services.Profiles)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sample, func(t *testing.T) {
+			cm := loadSampleConfig(t, tt.sample)
+			assertBackup(t, cm, tt.sample, tt.version)
+
+			for _, config := range []*Config{cm.config, reloadConfig(t, cm)} {
+				if config.SchemaVersion != CurrentSchemaVersion {
+					t.Fatalf("schema version = %d, want %d", config.SchemaVersion, CurrentSchemaVersion)
+				}
+				if config.Services.BindAddresses["workflowui"] != "127.0.0.1" || config.Services.BindAddresses["edu-tools"] != "127.0.0.1" {
+					t.Fatalf("services not bound to localhost: %+v", config.Services.BindAddresses)
+				}
+				tt.check(t, config)
+			}
+		})
+	}
+}
+
+func TestLoadCurrentConfigSkipsMigration(t *testing.T) {
+	dir := t.TempDir()
+	cm := &ConfigManager{configDir: dir, configFile: filepath.Join(dir, "config.toml")}
+	if err := cm.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 关闭网关后重新加载，迁移不能再次补全默认值
+	gateway := cm.GetConfig().Gateway
+	gateway.Enabled = false
+	if err := cm.UpdateGatewayConfig(gateway); err != nil {
+		t.Fatal(err)
+	}
+	config := reloadConfig(t, cm)
+	if config.SchemaVersion != CurrentSchemaVersion || config.Gateway.Enabled {
+		t.Fatalf("current config was migrated again: version %d, gateway %+v", config.SchemaVersion, config.Gateway)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "*.bak"))
+	if len(backups) != 0 {
+		t.Fatalf("unexpected backups: %v", backups)
+	}
+}
+
+func TestLoadNewerConfigIsNotModified(t *testing.T) {
+	dir := t.TempDir()
+	cm := &ConfigManager{configDir: dir, configFile: filepath.Join(dir, "config.toml")}
+	content := []byte("schema_version = 99\n\n[global]\n  theme = \"dark\"\n")
+	if err := os.WriteFile(cm.configFile, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if cm.config.SchemaVersion != 99 || cm.config.Global.Theme != "dark" {
+		t.Fatalf("unexpected config: %+v", cm.config)
+	}
+	data, _ := os.ReadFile(cm.configFile)
+	if string(data) != string(content) {
+		t.Fatalf("newer config file was rewritten")
+	}
+}
+
+func TestMigrateConfigRejectsInvalidVersion(t *testing.T) {
+	raw := map[string]interface{}{"schema_version": "two"}
+	if _, err := migrateConfig(raw); err == nil {
+		t.Fatalf("expected an error for a non-integer schema_version")
+	}
+}
+
+func TestConfigMigrationSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		migrate func(raw map[string]interface{}) error
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "profiles added",
+			migrate: migrateServiceProfiles,
+			raw:     `[global]`,
+			want: `[global]
+[services.profiles.workflow]
+name = "仅工作流"
+services = ["workflowui"]
+[services.profiles.workflow-files]
+name = "工作流 + 文件服务"
+services = ["workflowui", "fileserver"]
+[services.profiles.all]
+name = "全部服务"
+services = ["workflowui", "edu-tools", "fileserver"]`,
+		},
+		{
+			name:    "existing profiles kept",
+			migrate: migrateServiceProfiles,
+			raw:     "[services.profiles.files]\nservices = [\"fileserver\"]",
+			want:    "[services.profiles.files]\nservices = [\"fileserver\"]",
+		},
+		{
+			name:    "services not a table",
+			migrate: migrateServiceProfiles,
+			raw:     `services = "all"`,
+			wantErr: true,
+		},
+		{
+			name:    "caddy renamed and switches added",
+			migrate: migrateBuiltinFileServer,
+			raw:     "[fileserver]\nbrowse = false\n[services.profiles.p]\nservices = [\"workflowui\", \"caddy-fileserver\"]",
+			want:    "[fileserver]\nbrowse = false\ngzip = true\naccess_log = true\n[services.profiles.p]\nservices = [\"workflowui\", \"fileserver\"]",
+		},
+		{
+			name:    "fileserver not a table",
+			migrate: migrateBuiltinFileServer,
+			raw:     `fileserver = 1`,
+			wantErr: true,
+		},
+		{
+			name:    "gateway enabled",
+			migrate: migrateGatewayEnabled,
+			raw:     "[gateway]\nport = \"8100\"",
+			want:    "[gateway]\nport = \"8100\"\nenabled = true",
+		},
+		{
+			name:    "disabled gateway kept",
+			migrate: migrateGatewayEnabled,
+			raw:     "[gateway]\nenabled = false",
+			want:    "[gateway]\nenabled = false",
+		},
+		{
+			name:    "history limit added",
+			migrate: migrateHistoryLimit,
+			raw:     `[global]`,
+			want:    fmt.Sprintf("[global]\nhistory_limit = %d", defaultConfigHistoryLimit),
+		},
+		{
+			name:    "history limit kept",
+			migrate: migrateHistoryLimit,
+			raw:     "[global]\nhistory_limit = 0",
+			want:    "[global]\nhistory_limit = 0",
+		},
+		{
+			name:    "bind addresses added",
+			migrate: migrateServiceBindAddresses,
+			raw:     `[services]`,
+			want:    "[services.bind_addresses]\nworkflowui = \"127.0.0.1\"\nedu-tools = \"127.0.0.1\"",
+		},
+		{
+			name:    "bind addresses kept",
+			migrate: migrateServiceBindAddresses,
+			raw:     "[services.bind_addresses]\nworkflowui = \"0.0.0.0\"",
+			want:    "[services.bind_addresses]\nworkflowui = \"0.0.0.0\"\nedu-tools = \"127.0.0.1\"",
+		},
+		{
+			name:    "bind addresses not a table",
+			migrate: migrateServiceBindAddresses,
+			raw:     "[services]\nbind_addresses = \"0.0.0.0\"",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := decodeRawConfig([]byte(tt.raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tt.migrate(raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := decodeRawConfig([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(raw, want) {
+				t.Fatalf("migrated = %v, want %v", raw, want)
+			}
+
+			// 迁移可以重复执行
+			if err := tt.migrate(raw); err != nil || !reflect.DeepEqual(raw, want) {
+				t.Fatalf("second run changed the config: %v (%v)", raw, err)
+			}
+		})
+	}
+}
+
+func TestMigrateConfigRunsOnlyLaterSteps(t *testing.T) {
+	// 每个版本的文件都缺少所有迁移补全的键，只有版本之后的迁移会补全
+	added := map[int]func(raw map[string]interface{}) bool{
+		1: func(raw map[string]interface{}) bool { return rawHas(raw, "services", "profiles") },
+		2: func(raw map[string]interface{}) bool { return rawHas(raw, "fileserver", "browse") },
+		3: func(raw map[string]interface{}) bool { return rawHas(raw, "gateway", "enabled") },
+		4: func(raw map[string]interface{}) bool { return rawHas(raw, "global", "history_limit") },
+		5: func(raw map[string]interface{}) bool { return rawHas(raw, "services", "bind_addresses") },
+	}
+	for version := 0; version <= CurrentSchemaVersion; version++ {
+		raw := map[string]interface{}{"schema_version": int64(version)}
+		from, err := migrateConfig(raw)
+		if err != nil || from != version {
+			t.Fatalf("version %d: from = %d, err = %v", version, from, err)
+		}
+		if raw["schema_version"] != int64(CurrentSchemaVersion) {
+			t.Fatalf("version %d: schema_version = %v", version, raw["schema_version"])
+		}
+		for step, has := range added {
+			if has(raw) != (step > version) {
+				t.Fatalf("version %d: migration %d applied = %v", version, step, has(raw))
+			}
+		}
+	}
+}
+
+// rawHas 检查原始配置的表中是否存在键
+func rawHas(raw map[string]interface{}, section, key string) bool {
+	table, _ := raw[section].(map[string]interface{})
+	_, exists := table[key]
+	return exists
+}
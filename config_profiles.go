@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultConfigProfile 默认配置档案名称，引入配置档案之前的配置属于该档案
+const DefaultConfigProfile = "default"
+
+// ConfigProfile 配置档案信息
+// 当前档案保存在 config.toml 中，其他档案保存在配置目录的 profiles/<名称>.toml 中
+type ConfigProfile struct {
+	Name      string    // 档案名称
+	Active    bool      // 是否为当前档案
+	UpdatedAt time.Time // 最近修改时间
+}
+
+// profilesDir 配置档案目录
+func (cm *ConfigManager) profilesDir() string {
+	return filepath.Join(cm.configDir, "profiles")
+}
+
+// profileFile 未激活的配置档案文件
+func (cm *ConfigManager) profileFile(name string) string {
+	return filepath.Join(cm.profilesDir(), name+".toml")
+}
+
+// activeProfileFile 记录当前档案名称的文件
+func (cm *ConfigManager) activeProfileFile() string {
+	return filepath.Join(cm.profilesDir(), "active")
+}
+
+// loadActiveProfile 读取当前档案名称，没有记录时为默认档案
+func (cm *ConfigManager) loadActiveProfile() {
+	name := DefaultConfigProfile
+	if data, err := os.ReadFile(cm.activeProfileFile()); err == nil && validateProfileName(strings.TrimSpace(string(data))) == nil {
+		name = strings.TrimSpace(string(data))
+	}
+	cm.mu.Lock()
+	cm.profile = name
+	cm.mu.Unlock()
+}
+
+// ActiveProfile 获取当前配置档案名称
+func (cm *ConfigManager) ActiveProfile() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if cm.profile == "" {
+		return DefaultConfigProfile
+	}
+	return cm.profile
+}
+
+// setActiveProfile 记录当前档案名称
+func (cm *ConfigManager) setActiveProfile(name string) error {
+	if err := os.MkdirAll(cm.profilesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %v", err)
+	}
+	if err := writeFileAtomic(cm.activeProfileFile(), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record active profile: %v", err)
+	}
+	cm.mu.Lock()
+	cm.profile = name
+	cm.mu.Unlock()
+	return nil
+}
+
+// ListProfiles 列出所有配置档案，按名称排序
+func (cm *ConfigManager) ListProfiles() ([]ConfigProfile, error) {
+	active := cm.ActiveProfile()
+	profiles := []ConfigProfile{{Name: active, Active: true}}
+	if info, err := os.Stat(cm.configFile); err == nil {
+		profiles[0].UpdatedAt = info.ModTime()
+	}
+
+	entries, err := os.ReadDir(cm.profilesDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read profiles directory: %v", err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".toml")
+		if entry.IsDir() || name == entry.Name() || name == active || validateProfileName(name) != nil {
+			continue
+		}
+		profile := ConfigProfile{Name: name}
+		if info, err := entry.Info(); err == nil {
+			profile.UpdatedAt = info.ModTime()
+		}
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles, nil
+}
+
+// ProfileExists 判断配置档案是否存在
+func (cm *ConfigManager) ProfileExists(name string) bool {
+	if name == cm.ActiveProfile() {
+		return true
+	}
+	_, err := os.Stat(cm.profileFile(name))
+	return err == nil
+}
+
+// LoadProfile 读取配置档案，当前档案返回当前配置文件中的配置
+func (cm *ConfigManager) LoadProfile(name string) (*Config, error) {
+	if name == cm.ActiveProfile() {
+		return cm.FileConfig(), nil
+	}
+	data, err := os.ReadFile(cm.profileFile(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("profile '%s' not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile '%s': %v", name, err)
+	}
+	return parseConfigData(data)
+}
+
+// SaveProfile 保存配置档案，当前档案直接替换当前配置，其他档案在激活时校验
+func (cm *ConfigManager) SaveProfile(name string, config *Config) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if name == cm.ActiveProfile() {
+		return cm.ReplaceConfig(config)
+	}
+	return cm.writeProfile(name, config)
+}
+
+// writeProfile 写入未激活的配置档案文件
+func (cm *ConfigManager) writeProfile(name string, config *Config) error {
+	data, err := encodeConfig(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cm.profilesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %v", err)
+	}
+	if err := writeFileAtomic(cm.profileFile(name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile '%s': %v", name, err)
+	}
+	return nil
+}
+
+// RenameProfile 重命名配置档案
+func (cm *ConfigManager) RenameProfile(oldName, newName string) error {
+	if err := validateProfileName(newName); err != nil {
+		return err
+	}
+	if !cm.ProfileExists(oldName) {
+		return fmt.Errorf("profile '%s' not found", oldName)
+	}
+	if cm.ProfileExists(newName) {
+		return fmt.Errorf("profile '%s' already exists", newName)
+	}
+
+	// 配置历史随档案移动
+	if err := cm.moveProfileHistory(oldName, newName); err != nil {
+		return err
+	}
+	if oldName == cm.ActiveProfile() {
+		return cm.setActiveProfile(newName)
+	}
+	if err := os.Rename(cm.profileFile(oldName), cm.profileFile(newName)); err != nil {
+		return fmt.Errorf("failed to rename profile '%s': %v", oldName, err)
+	}
+	return nil
+}
+
+// moveProfileHistory 将配置档案的历史目录移动到新名称下
+func (cm *ConfigManager) moveProfileHistory(oldName, newName string) error {
+	from, to := cm.profileHistoryDir(oldName), cm.profileHistoryDir(newName)
+	if _, err := os.Stat(from); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0700); err != nil {
+		return fmt.Errorf("failed to move history of profile '%s': %v", oldName, err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to move history of profile '%s': %v", oldName, err)
+	}
+	if oldName != DefaultConfigProfile {
+		os.Remove(filepath.Dir(from))
+	}
+	return nil
+}
+
+// DeleteProfile 删除配置档案，不能删除当前档案
+func (cm *ConfigManager) DeleteProfile(name string) error {
+	if name == cm.ActiveProfile() {
+		return fmt.Errorf("cannot delete the active profile '%s'", name)
+	}
+	if err := os.Remove(cm.profileFile(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("profile '%s' not found", name)
+		}
+		return fmt.Errorf("failed to delete profile '%s': %v", name, err)
+	}
+	// 删除档案的配置历史
+	os.RemoveAll(cm.profileHistoryDir(name))
+	if name != DefaultConfigProfile {
+		os.Remove(filepath.Dir(cm.profileHistoryDir(name)))
+	}
+	return nil
+}
+
+// profileSwitchFile 记录进行中的档案切换（原档案和目标档案名称），切换完成后删除
+func (cm *ConfigManager) profileSwitchFile() string {
+	return filepath.Join(cm.profilesDir(), "switching")
+}
+
+// ActivateProfile 切换到指定配置档案：当前配置保存为原档案的文件，目标档案的配置替换当前配置
+// 整个切换在写锁内完成，先记录切换再依次写入原档案、配置文件和当前档案名称，最后删除目标档案文件；
+// 中途退出时由 repairProfileSwitch 在下次加载时撤销
+func (cm *ConfigManager) ActivateProfile(name string) error {
+	if name == cm.ActiveProfile() {
+		return nil
+	}
+	// 先读取配置文件的外部修改，避免保存时被合并到目标档案中
+	cm.checkExternalChange()
+
+	var active string
+	switched := false
+	_, err := cm.commitUpdate(func(config *Config) error {
+		active = cm.ActiveProfile()
+		if name == active {
+			return nil
+		}
+		target, err := cm.LoadProfile(name)
+		if err != nil {
+			return err
+		}
+		if err := cm.recordProfileSwitch(active, name); err != nil {
+			return err
+		}
+		if err := cm.writeProfile(active, config); err != nil {
+			os.Remove(cm.profileSwitchFile())
+			return err
+		}
+
+		// 保存前切换当前档案，目标配置的密钥和历史版本归属目标档案
+		cm.mu.Lock()
+		cm.profile = name
+		cm.mu.Unlock()
+		*config = *target
+		switched = true
+		return nil
+	})
+	if err != nil {
+		if switched {
+			cm.mu.Lock()
+			cm.profile = active
+			cm.mu.Unlock()
+			os.Remove(cm.profileFile(active))
+			os.Remove(cm.profileSwitchFile())
+		}
+		return fmt.Errorf("failed to activate profile '%s': %v", name, err)
+	}
+	if !switched {
+		return nil
+	}
+
+	// 记录失败时保留切换记录，下次加载时恢复原档案
+	if err := cm.setActiveProfile(name); err != nil {
+		return err
+	}
+	// 当前档案保存在 config.toml 中，删除旧文件避免出现两个版本，切换至此完成
+	if err := os.Remove(cm.profileFile(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to finish activating profile '%s': %v", name, err)
+	}
+	os.Remove(cm.profileSwitchFile())
+	return nil
+}
+
+// recordProfileSwitch 记录即将进行的档案切换
+func (cm *ConfigManager) recordProfileSwitch(from, to string) error {
+	if err := os.MkdirAll(cm.profilesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %v", err)
+	}
+	if err := writeFileAtomic(cm.profileSwitchFile(), []byte(from+"\n"+to+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record profile switch: %v", err)
+	}
+	return nil
+}
+
+// repairProfileSwitch 处理上次中断的档案切换
+// 目标档案文件仍存在时切换未完成，从原档案文件恢复配置文件和当前档案；否则切换已完成，只补全当前档案名称
+func (cm *ConfigManager) repairProfileSwitch() error {
+	data, err := os.ReadFile(cm.profileSwitchFile())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read profile switch record: %v", err)
+	}
+	from, to, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	if validateProfileName(from) != nil || validateProfileName(to) != nil {
+		return os.Remove(cm.profileSwitchFile())
+	}
+
+	if _, err := os.Stat(cm.profileFile(to)); err == nil {
+		if saved, err := os.ReadFile(cm.profileFile(from)); err == nil {
+			if err := writeFileAtomic(cm.configFile, saved, 0644); err != nil {
+				return fmt.Errorf("failed to restore profile '%s': %v", from, err)
+			}
+			os.Remove(cm.profileFile(from))
+		}
+		if err := cm.setActiveProfile(from); err != nil {
+			return err
+		}
+	} else if err := cm.setActiveProfile(to); err != nil {
+		return err
+	}
+	return os.Remove(cm.profileSwitchFile())
+}
+
+// validateProfileName 校验配置档案名称，名称用作文件名
+func validateProfileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	if name != strings.TrimSpace(name) || len(name) > 64 || strings.HasPrefix(name, ".") ||
+		strings.ContainsAny(name, `/\:*?"<>|`) {
+		return fmt.Errorf("invalid profile name '%s'", name)
+	}
+	return nil
+}
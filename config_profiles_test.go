@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// newProfileTestManager 创建包含 exam 档案的配置管理器，当前档案的主题为 light，exam 档案为 dark
+func newProfileTestManager(t *testing.T) *ConfigManager {
+	t.Helper()
+
+	cm := newTestConfigManager(t)
+	exam := GetDefaultConfig()
+	exam.Global.Theme = "dark"
+	if err := cm.SaveProfile("exam", exam); err != nil {
+		t.Fatalf("failed to save profile: %v", err)
+	}
+	return cm
+}
+
+// reopenConfigManager 重新加载配置目录，模拟应用重启
+func reopenConfigManager(t *testing.T, cm *ConfigManager) *ConfigManager {
+	t.Helper()
+
+	reopened, err := NewConfigManager(ConfigOptions{File: cm.configFile})
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	return reopened
+}
+
+// assertActiveProfile 检查当前档案、当前配置的主题和残留的档案文件
+func assertActiveProfile(t *testing.T, cm *ConfigManager, profile, theme, inactive string) {
+	t.Helper()
+
+	if got := cm.ActiveProfile(); got != profile {
+		t.Fatalf("active profile = %s, want %s", got, profile)
+	}
+	if got := cm.FileConfig().Global.Theme; got != theme {
+		t.Fatalf("theme = %s, want %s", got, theme)
+	}
+	if _, err := os.Stat(cm.profileFile(profile)); err == nil {
+		t.Fatalf("the active profile '%s' still has a profile file", profile)
+	}
+	if _, err := os.Stat(cm.profileFile(inactive)); err != nil {
+		t.Fatalf("the inactive profile '%s' has no profile file: %v", inactive, err)
+	}
+	if _, err := os.Stat(cm.profileSwitchFile()); err == nil {
+		t.Fatalf("the profile switch record was not removed")
+	}
+}
+
+func TestActivateProfile(t *testing.T) {
+	cm := newProfileTestManager(t)
+	if err := cm.ActivateProfile("exam"); err != nil {
+		t.Fatalf("failed to activate profile: %v", err)
+	}
+	assertActiveProfile(t, cm, "exam", "dark", DefaultConfigProfile)
+	assertActiveProfile(t, reopenConfigManager(t, cm), "exam", "dark", DefaultConfigProfile)
+
+	// 目标配置无法保存时保持原档案
+	broken := GetDefaultConfig()
+	broken.FileServer.Port = "not-a-port"
+	if err := cm.SaveProfile("broken", broken); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.ActivateProfile("broken"); err == nil {
+		t.Fatalf("activating an invalid profile should fail")
+	}
+	assertActiveProfile(t, cm, "exam", "dark", "broken")
+	assertActiveProfile(t, reopenConfigManager(t, cm), "exam", "dark", DefaultConfigProfile)
+}
+
+func TestInterruptedProfileSwitchIsRepaired(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       int // 中断前完成的步骤数
+		wantProfile string
+		wantTheme   string
+		wantOther   string
+	}{
+		{"after recording the switch", 1, DefaultConfigProfile, "light", "exam"},
+		{"after saving the active profile", 2, DefaultConfigProfile, "light", "exam"},
+		{"after writing the config file", 3, DefaultConfigProfile, "light", "exam"},
+		{"after recording the active profile", 4, DefaultConfigProfile, "light", "exam"},
+		{"after removing the target profile", 5, "exam", "dark", DefaultConfigProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newProfileTestManager(t)
+			target, err := cm.LoadProfile("exam")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := encodeConfig(target)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 按 ActivateProfile 的顺序执行切换的各个步骤
+			steps := []func() error{
+				func() error { return cm.recordProfileSwitch(DefaultConfigProfile, "exam") },
+				func() error { return cm.writeProfile(DefaultConfigProfile, cm.FileConfig()) },
+				func() error { return writeFileAtomic(cm.configFile, data, 0644) },
+				func() error { return cm.setActiveProfile("exam") },
+				func() error { return os.Remove(cm.profileFile("exam")) },
+			}
+			for _, step := range steps[:tt.steps] {
+				if err := step(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			assertActiveProfile(t, reopenConfigManager(t, cm), tt.wantProfile, tt.wantTheme, tt.wantOther)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigRecovery 配置文件损坏时的恢复结果
+type ConfigRecovery struct {
+	RecoveredAt time.Time           // 恢复时间
+	BackupFile  string              // 损坏文件的备份
+	Error       string              // 整个文件的解析错误
+	Recovered   []string            // 完整恢复的配置段
+	Lost        []ConfigSectionLoss // 无法解析、已重置为默认值的配置段
+}
+
+// ConfigSectionLoss 无法恢复的配置段
+type ConfigSectionLoss struct {
+	Section string // 配置段名称，schema_version 等顶层配置项为空
+	Error   string // 解析错误
+}
+
+// configTableHeader 匹配 TOML 表头，捕获顶层表名
+var configTableHeader = regexp.MustCompile(`^\s*\[\[?\s*("[^"]*"|[A-Za-z0-9_-]+)`)
+
+// recoverConfig 备份损坏的配置文件，逐个解析其中的配置段：能解析的配置段保留，无法解析的重置为默认值
+func (cm *ConfigManager) recoverConfig(data []byte, cause error) (*Config, error) {
+	// 备份文件名带随机后缀，同一秒内多次恢复（如多个实例同时启动）不会覆盖之前的备份
+	now := time.Now()
+	recovery := &ConfigRecovery{
+		RecoveredAt: now,
+		BackupFile:  fmt.Sprintf("%s.corrupt-%s-%s", cm.configFile, now.Format("20060102-150405"), randomHex(4)),
+		Error:       cause.Error(),
+	}
+	config, recovered, lost := salvageConfig(data)
+	if err := cm.writeConfigCopy(recovery.BackupFile, data, config); err != nil {
+		return nil, fmt.Errorf("failed to back up corrupted config: %v", err)
+	}
+	recovery.Recovered = recovered
+	recovery.Lost = lost
+
+	cm.mu.Lock()
+	cm.recovery = recovery
+	cm.mu.Unlock()
+	return config, nil
+}
+
+// Recovery 获取本次启动时配置文件的恢复结果，配置文件未损坏时为 nil
+func (cm *ConfigManager) Recovery() *ConfigRecovery {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if cm.recovery == nil {
+		return nil
+	}
+	recovery := *cm.recovery
+	recovery.Recovered = cloneStrings(recovery.Recovered)
+	recovery.Lost = append([]ConfigSectionLoss{}, recovery.Lost...)
+	return &recovery
+}
+
+// salvageConfig 按顶层表拆分配置文件并分别解析，返回恢复的配置、完整恢复的配置段和丢失的配置段
+func salvageConfig(data []byte) (*Config, []string, []ConfigSectionLoss) {
+	preamble, sections, order := splitConfigSections(string(data))
+	raw := make(map[string]interface{})
+	recovered := []string{}
+	lost := []ConfigSectionLoss{}
+
+	// 顶层配置项（schema_version），无法解析时按版本 0 迁移，迁移可以重复执行
+	if _, err := toml.Decode(preamble, &raw); err != nil {
+		raw = make(map[string]interface{})
+		lost = append(lost, ConfigSectionLoss{Error: err.Error()})
+	} else if _, err := rawSchemaVersion(raw); err != nil {
+		delete(raw, "schema_version")
+		lost = append(lost, ConfigSectionLoss{Error: err.Error()})
+	}
+
+	known := configSectionNames()
+	for _, name := range order {
+		var section map[string]interface{}
+		_, err := toml.Decode(sections[name], &section)
+		if err == nil {
+			// 同时检查能否解析为配置结构，发现类型不匹配（如端口写成了数字）
+			var probe Config
+			_, err = toml.Decode(sections[name], &probe)
+		}
+		if err != nil {
+			lost = append(lost, ConfigSectionLoss{Section: name, Error: err.Error()})
+			continue
+		}
+		if containsString(known, name) {
+			raw[name] = section[name]
+			recovered = append(recovered, name)
+		}
+	}
+
+	config, _, err := buildConfig(raw)
+	if err != nil {
+		lost = append(lost, ConfigSectionLoss{Error: err.Error()})
+		return GetDefaultConfig(), []string{}, lost
+	}
+
+	// 丢失的配置段整体使用默认值
+	defaults := reflect.ValueOf(GetDefaultConfig()).Elem()
+	v := reflect.ValueOf(config).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() == reflect.Struct && !containsString(recovered, configSectionName(field)) {
+			v.Field(i).Set(defaults.Field(i))
+		}
+	}
+	return config, recovered, lost
+}
+
+// splitConfigSections 将配置文件按顶层表拆分，同一顶层表的子表（如 [workflow.workflows.essay]）归入该表
+// 返回第一个表头之前的内容、各顶层表的内容和出现顺序；其他表的行替换为空行，使解析错误中的行号与原文件一致
+func splitConfigSections(content string) (string, map[string]string, []string) {
+	lines := strings.SplitAfter(content, "\n")
+	owners := make([]string, len(lines))
+	var order []string
+
+	owner := ""
+	for i, line := range lines {
+		if match := configTableHeader.FindStringSubmatch(line); match != nil {
+			owner = strings.Trim(match[1], `"`)
+			if !containsString(order, owner) {
+				order = append(order, owner)
+			}
+		}
+		owners[i] = owner
+	}
+
+	extract := func(name string) string {
+		var builder strings.Builder
+		for i, line := range lines {
+			if owners[i] == name {
+				builder.WriteString(line)
+			} else {
+				builder.WriteString("\n")
+			}
+		}
+		return builder.String()
+	}
+
+	sections := make(map[string]string, len(order))
+	for _, name := range order {
+		sections[name] = extract(name)
+	}
+	return extract(""), sections, order
+}
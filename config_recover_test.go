@@ -0,0 +1,191 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitConfigSections(t *testing.T) {
+	content := strings.Join([]string{
+		"schema_version = 5",
+		"[global]",
+		"theme = \"dark\"",
+		"[workflow]",
+		"apikey = \"k\"",
+		"[workflow.workflows.essay]",
+		"name = \"作文\"",
+		"[\"fileserver\"]",
+		"port = \"9000\"",
+		"[[workflow.workflows.essay.parameters]]",
+		"key = \"topic\"",
+		"[global]",
+		"history_limit = 5",
+	}, "\n") + "\n"
+
+	preamble, sections, order := splitConfigSections(content)
+
+	if want := []string{"global", "workflow", "fileserver"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	want := map[string][]int{ // 配置段 -> 所属的行号（从 1 开始）
+		"":           {1},
+		"global":     {2, 3, 12, 13},
+		"workflow":   {4, 5, 6, 7, 10, 11},
+		"fileserver": {8, 9},
+	}
+	lines := strings.Split(content, "\n")
+	for name, owned := range want {
+		got := preamble
+		if name != "" {
+			got = sections[name]
+		}
+		// 其他配置段的行替换为空行，行号与原文件一致
+		gotLines := strings.Split(got, "\n")
+		if len(gotLines) < len(lines)-1 {
+			t.Fatalf("section %q has %d lines, want %d", name, len(gotLines), len(lines)-1)
+		}
+		for i, line := range lines[:len(lines)-1] {
+			expected := ""
+			if containsInt(owned, i+1) {
+				expected = line
+			}
+			if gotLines[i] != expected {
+				t.Fatalf("section %q line %d = %q, want %q", name, i+1, gotLines[i], expected)
+			}
+		}
+	}
+}
+
+// containsInt 判断切片中是否包含指定的整数
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSalvageConfig(t *testing.T) {
+	defaults := GetDefaultConfig()
+	tests := []struct {
+		name          string
+		content       string
+		wantRecovered []string
+		wantLost      []string // 丢失的配置段，顶层配置项为空字符串
+		wantLine      string   // 丢失原因中应包含的原文件行号
+		check         func(t *testing.T, config *Config)
+	}{
+		{
+			name:          "broken section",
+			content:       "schema_version = 5\n[global]\ntheme = \"dark\"\n[fileserver]\nport = \n",
+			wantRecovered: []string{"global"},
+			wantLost:      []string{"fileserver"},
+			wantLine:      "line 5",
+			check: func(t *testing.T, config *Config) {
+				if config.Global.Theme != "dark" || !reflect.DeepEqual(config.FileServer, defaults.FileServer) {
+					t.Fatalf("unexpected config: global %+v, fileserver %+v", config.Global, config.FileServer)
+				}
+			},
+		},
+		{
+			name:          "section with a wrong type",
+			content:       "schema_version = 5\n[global]\ntheme = \"dark\"\n[fileserver]\nport = 9000\n",
+			wantRecovered: []string{"global"},
+			wantLost:      []string{"fileserver"},
+		},
+		{
+			name: "broken sub-table",
+			content: "schema_version = 5\n[fileserver]\nport = \"9000\"\n[workflow]\napikey = \"k\"\n" +
+				"[workflow.workflows.essay]\nname = \n",
+			wantRecovered: []string{"fileserver"},
+			wantLost:      []string{"workflow"},
+			wantLine:      "line 7",
+			check: func(t *testing.T, config *Config) {
+				if config.FileServer.Port != "9000" || config.Workflow.ApiKey != "" || len(config.Workflow.Workflows) != 0 {
+					t.Fatalf("unexpected config: fileserver %+v, workflow %+v", config.FileServer, config.Workflow)
+				}
+			},
+		},
+		{
+			name:          "broken preamble",
+			content:       "schema_version = \n[global]\ntheme = \"dark\"\n",
+			wantRecovered: []string{"global"},
+			wantLost:      []string{""},
+			wantLine:      "line 1",
+			check: func(t *testing.T, config *Config) {
+				if config.Global.Theme != "dark" || config.SchemaVersion != CurrentSchemaVersion {
+					t.Fatalf("unexpected config: schema %d, global %+v", config.SchemaVersion, config.Global)
+				}
+			},
+		},
+		{
+			name:          "invalid schema version",
+			content:       "schema_version = \"five\"\n[global]\ntheme = \"dark\"\n",
+			wantRecovered: []string{"global"},
+			wantLost:      []string{""},
+		},
+		{
+			name:          "duplicate table",
+			content:       "schema_version = 5\n[global]\ntheme = \"dark\"\n[fileserver]\nport = \"9000\"\n[global]\nhistory_limit = 5\n",
+			wantRecovered: []string{"fileserver"},
+			wantLost:      []string{"global"},
+			check: func(t *testing.T, config *Config) {
+				if config.FileServer.Port != "9000" || !reflect.DeepEqual(config.Global, defaults.Global) {
+					t.Fatalf("unexpected config: fileserver %+v, global %+v", config.FileServer, config.Global)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, recovered, lost := salvageConfig([]byte(tt.content))
+
+			if !reflect.DeepEqual(recovered, tt.wantRecovered) {
+				t.Fatalf("recovered = %v, want %v", recovered, tt.wantRecovered)
+			}
+			var lostSections []string
+			for _, loss := range lost {
+				lostSections = append(lostSections, loss.Section)
+			}
+			if !reflect.DeepEqual(lostSections, tt.wantLost) {
+				t.Fatalf("lost = %+v, want %v", lost, tt.wantLost)
+			}
+			if tt.wantLine != "" && !strings.Contains(lost[0].Error, tt.wantLine) {
+				t.Fatalf("error %q does not point to %s of the original file", lost[0].Error, tt.wantLine)
+			}
+			if tt.check != nil {
+				tt.check(t, config)
+			}
+		})
+	}
+}
+
+func TestRecoverConfigKeepsEveryBackup(t *testing.T) {
+	cm := newTestConfigManager(t)
+	broken := []byte("schema_version = 5\n[global]\ntheme = \n")
+
+	// 同一秒内的多次恢复各自保留备份
+	for i := 0; i < 3; i++ {
+		if _, err := cm.recoverConfig(broken, os.ErrInvalid); err != nil {
+			t.Fatalf("failed to recover config: %v", err)
+		}
+	}
+	backups, _ := filepath.Glob(cm.configFile + ".corrupt-*")
+	if len(backups) != 3 {
+		t.Fatalf("backups = %v, want 3", backups)
+	}
+	for _, backup := range backups {
+		info, err := os.Stat(backup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm()&0077 != 0 {
+			t.Fatalf("%s is readable by other users (%v)", backup, info.Mode().Perm())
+		}
+	}
+}
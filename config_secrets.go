@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// sealPlaintextSecrets 将配置中的明文敏感配置项加密保存并替换为引用，返回是否有修改，调用方需持有 cm.writeMu
+// 密钥存储锁定时保留明文，解锁后由 sealConfigSecrets 迁移
+func (cm *ConfigManager) sealPlaintextSecrets(config *Config) bool {
+	if cm.sealSecrets == nil || config == nil {
+		return false
+	}
+	before := config.Clone()
+	// 与空配置比较，所有非空的明文值都视为修改
+	cm.sealSecrets(&Config{}, config)
+
+	previous := configSecretValues(before)
+	for id, value := range configSecretValues(config) {
+		if *value != *previous[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// writeConfigCopy 保存配置文件的副本（迁移前备份、损坏文件备份、冲突副本），仅当前用户可读
+// config 为副本内容解析后的配置，其中的明文密钥在副本中替换为引用，无法解析时为 nil
+func (cm *ConfigManager) writeConfigCopy(path string, data []byte, config *Config) error {
+	return writeFileAtomic(path, cm.scrubSecrets(data, config), 0600)
+}
+
+// scrubSecrets 将配置文件内容中的明文密钥替换为当前配置档案的密钥引用
+// 未设置 sealSecrets 时配置文件本身保留明文，副本保持原样
+func (cm *ConfigManager) scrubSecrets(data []byte, config *Config) []byte {
+	if cm.sealSecrets == nil || config == nil {
+		return data
+	}
+	profile := cm.ActiveProfile()
+	for id, value := range configSecretValues(config) {
+		if *value == "" || IsSecretRef(*value) {
+			continue
+		}
+		ref := []byte(strconv.Quote(SecretRefPrefix + profileSecretID(profile, id)))
+		for _, quoted := range []string{strconv.Quote(*value), "'" + *value + "'"} {
+			data = bytes.ReplaceAll(data, []byte(quoted), ref)
+		}
+	}
+	return data
+}
+
+// scrubConfigCopies 清除已有的配置历史版本和配置副本中的明文密钥，并改为仅当前用户可读
+// 用于处理加密保存密钥之前写入的文件
+func (cm *ConfigManager) scrubConfigCopies() {
+	var paths []string
+	for _, pattern := range []string{
+		filepath.Join(cm.profileHistoryDir(DefaultConfigProfile), "*.toml"),
+		filepath.Join(cm.profilesDir(), "*", "history", "*.toml"),
+		cm.configFile + ".v*.bak",
+		cm.configFile + ".corrupt-*",
+		cm.configFile + ".conflict-*",
+	} {
+		matches, _ := filepath.Glob(pattern)
+		paths = append(paths, matches...)
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		config, err := parseConfigData(data)
+		if err != nil {
+			config, _, _ = salvageConfig(data)
+		}
+		scrubbed := cm.scrubSecrets(data, config)
+		if bytes.Equal(scrubbed, data) && info.Mode().Perm()&0077 == 0 {
+			continue
+		}
+		writeFileAtomic(path, scrubbed, 0600)
+	}
+	os.Chmod(cm.profileHistoryDir(DefaultConfigProfile), 0700)
+}
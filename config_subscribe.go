@@ -0,0 +1,215 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ConfigSectionAll 订阅所有配置段的变更
+const ConfigSectionAll = ""
+
+// ConfigChange 配置变更通知
+type ConfigChange struct {
+	Section  string  // 变更的配置段（配置文件中的表名，如 gateway、lan）
+	Config   *Config // 变更后的生效配置副本
+	Previous *Config // 变更前的生效配置副本
+}
+
+// configEvent 等待发送的配置变更通知
+type configEvent struct {
+	previous *Config               // 变更前的配置，与 next 同时为空表示只有外部修改的通知
+	next     *Config               // 变更后的配置
+	external *ExternalConfigChange // 外部修改的处理结果
+}
+
+// configSubscriber 配置变更订阅者
+type configSubscriber struct {
+	section string             // 订阅的配置段，为空表示全部
+	handler func(ConfigChange) // 变更处理函数
+}
+
+// Subscribe 订阅配置段的变更，section 为 ConfigSectionAll 时订阅全部配置段，返回取消订阅函数
+// 处理函数在配置保存成功后按保存顺序逐个调用，不会并发执行；可以在其中再次更新配置，新的通知在当前通知之后发送
+func (cm *ConfigManager) Subscribe(section string, handler func(ConfigChange)) func() {
+	cm.subMu.Lock()
+	defer cm.subMu.Unlock()
+
+	if cm.subscribers == nil {
+		cm.subscribers = make(map[int]*configSubscriber)
+	}
+	id := cm.nextSubID
+	cm.nextSubID++
+	cm.subscribers[id] = &configSubscriber{section: section, handler: handler}
+
+	return func() {
+		cm.subMu.Lock()
+		defer cm.subMu.Unlock()
+		delete(cm.subscribers, id)
+	}
+}
+
+// enqueueNotify 记录等待发送的通知，调用方需持有 cm.writeMu，使通知顺序与保存顺序一致
+func (cm *ConfigManager) enqueueNotify(event configEvent) {
+	cm.queueMu.Lock()
+	defer cm.queueMu.Unlock()
+	cm.queue = append(cm.queue, event)
+}
+
+// deliverNotifications 依次发送等待发送的通知
+// 其他协程正在发送时直接返回，由该协程继续发送，保证处理函数不会并发执行
+func (cm *ConfigManager) deliverNotifications() {
+	cm.queueMu.Lock()
+	if cm.delivering {
+		cm.queueMu.Unlock()
+		return
+	}
+	cm.delivering = true
+	for len(cm.queue) > 0 {
+		event := cm.queue[0]
+		cm.queue = cm.queue[1:]
+		cm.queueMu.Unlock()
+
+		if event.external != nil {
+			cm.notifyExternal(*event.external)
+		}
+		if event.next != nil {
+			cm.notify(event.previous, event.next)
+		}
+		cm.queueMu.Lock()
+	}
+	cm.delivering = false
+	cm.queueMu.Unlock()
+}
+
+// notify 比较新旧配置，向订阅了变更配置段的订阅者发送通知
+func (cm *ConfigManager) notify(previous, next *Config) {
+	sections := changedSections(previous, next)
+	if len(sections) == 0 {
+		return
+	}
+
+	cm.subMu.Lock()
+	subscribers := make([]*configSubscriber, 0, len(cm.subscribers))
+	for _, subscriber := range cm.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	cm.subMu.Unlock()
+
+	for _, section := range sections {
+		for _, subscriber := range subscribers {
+			if subscriber.section == ConfigSectionAll || subscriber.section == section {
+				subscriber.handler(ConfigChange{
+					Section:  section,
+					Config:   cm.applyOverrides(next.Clone()),
+					Previous: cm.applyOverrides(previous.Clone()),
+				})
+			}
+		}
+	}
+}
+
+// changedSections 返回新旧配置中发生变化的配置段名称
+func changedSections(previous, next *Config) []string {
+	var sections []string
+	before := reflect.ValueOf(previous).Elem()
+	after := reflect.ValueOf(next).Elem()
+	for i := 0; i < after.NumField(); i++ {
+		field := after.Type().Field(i)
+		if field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if !reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			sections = append(sections, configSectionName(field))
+		}
+	}
+	return sections
+}
+
+// configSectionName 返回配置段在配置文件中的表名
+func configSectionName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("toml"), ",")[0]
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// Clone 深拷贝配置
+func (c *Config) Clone() *Config {
+	clone := *c
+
+	clone.Workflow.Workflows = make(map[string]WorkflowDef, len(c.Workflow.Workflows))
+	for key, workflow := range c.Workflow.Workflows {
+		workflow.Parameters = cloneWorkflowParameters(workflow.Parameters)
+		clone.Workflow.Workflows[key] = workflow
+	}
+	if c.Workflow.Workflows == nil {
+		clone.Workflow.Workflows = nil
+	}
+
+	clone.License.FeatureFlags = cloneStrings(c.License.FeatureFlags)
+
+	clone.Services.Profiles = make(map[string]ServiceProfile, len(c.Services.Profiles))
+	for key, profile := range c.Services.Profiles {
+		profile.Services = cloneStrings(profile.Services)
+		clone.Services.Profiles[key] = profile
+	}
+	if c.Services.Profiles == nil {
+		clone.Services.Profiles = nil
+	}
+	clone.Services.HealthPaths = cloneStringMap(c.Services.HealthPaths)
+	clone.Services.BindAddresses = cloneStringMap(c.Services.BindAddresses)
+
+	clone.Binaries.Pinned = cloneStringMap(c.Binaries.Pinned)
+	clone.TLS.Hosts = cloneStrings(c.TLS.Hosts)
+	return &clone
+}
+
+// cloneStrings 复制字符串切片，保留 nil
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+// cloneWorkflowParameters 深拷贝工作流参数列表，保留 nil
+func cloneWorkflowParameters(parameters []WorkflowParameter) []WorkflowParameter {
+	if parameters == nil {
+		return nil
+	}
+	clone := make([]WorkflowParameter, len(parameters))
+	for i, parameter := range parameters {
+		parameter.Options = cloneStrings(parameter.Options)
+		if parameter.Min != nil {
+			min := *parameter.Min
+			parameter.Min = &min
+		}
+		if parameter.Max != nil {
+			max := *parameter.Max
+			parameter.Max = &max
+		}
+		if parameter.VisibleWhen != nil {
+			conditions := make([]WorkflowCondition, len(parameter.VisibleWhen))
+			for j, condition := range parameter.VisibleWhen {
+				condition.Values = cloneStrings(condition.Values)
+				conditions[j] = condition
+			}
+			parameter.VisibleWhen = conditions
+		}
+		clone[i] = parameter
+	}
+	return clone
+}
+
+// cloneStringMap 复制字符串映射，保留 nil
+func cloneStringMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	clone := make(map[string]string, len(values))
+	for key, value := range values {
+		clone[key] = value
+	}
+	return clone
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSubscribersAreCalledInOrder(t *testing.T) {
+	cm, err := NewConfigManager(ConfigOptions{File: filepath.Join(t.TempDir(), "config.toml")})
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+
+	var inFlight int32
+	var overlapped bool
+	last := cm.GetConfig().FileServer.Port
+	var broken []string
+	cm.Subscribe("fileserver", func(change ConfigChange) {
+		if atomic.AddInt32(&inFlight, 1) > 1 {
+			overlapped = true
+		}
+		defer atomic.AddInt32(&inFlight, -1)
+
+		// 每次通知的变更前配置应为上一次通知的变更后配置
+		if change.Previous.FileServer.Port != last {
+			broken = append(broken, fmt.Sprintf("%s -> %s after %s", change.Previous.FileServer.Port, change.Config.FileServer.Port, last))
+		}
+		last = change.Config.FileServer.Port
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(port int) {
+			defer wg.Done()
+			fileServer := cm.FileConfig().FileServer
+			fileServer.Port = fmt.Sprint(port)
+			if err := cm.UpdateFileServerConfig(fileServer); err != nil {
+				t.Errorf("failed to update config: %v", err)
+			}
+		}(9100 + i)
+	}
+	wg.Wait()
+
+	if overlapped {
+		t.Fatalf("subscribers were called concurrently")
+	}
+	if len(broken) > 0 {
+		t.Fatalf("notifications out of order: %v", broken)
+	}
+	if last != cm.GetConfig().FileServer.Port {
+		t.Fatalf("last notification %s does not match the saved port %s", last, cm.GetConfig().FileServer.Port)
+	}
+}
+
+func TestSubscriberCanUpdateConfig(t *testing.T) {
+	cm, err := NewConfigManager(ConfigOptions{File: filepath.Join(t.TempDir(), "config.toml")})
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+
+	var sections []string
+	cm.Subscribe(ConfigSectionAll, func(change ConfigChange) {
+		sections = append(sections, change.Section)
+	})
+	// 网关端口变化后同步修改文件服务器端口，新的通知排在当前通知之后
+	cm.Subscribe("gateway", func(change ConfigChange) {
+		fileServer := change.Config.FileServer
+		fileServer.Port = "9200"
+		if err := cm.UpdateFileServerConfig(fileServer); err != nil {
+			t.Errorf("failed to update config from subscriber: %v", err)
+		}
+	})
+
+	gateway := cm.FileConfig().Gateway
+	gateway.Port = "9300"
+	if err := cm.UpdateGatewayConfig(gateway); err != nil {
+		t.Fatal(err)
+	}
+	if got := cm.GetConfig().FileServer.Port; got != "9200" {
+		t.Fatalf("file server port = %s, want 9200", got)
+	}
+	if fmt.Sprint(sections) != "[gateway fileserver]" {
+		t.Fatalf("sections = %v, want [gateway fileserver]", sections)
+	}
+}
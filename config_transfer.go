@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// 配置导出文件格式
+const (
+	ConfigFormatTOML = "toml"
+	ConfigFormatJSON = "json"
+)
+
+// 导出时敏感配置项的处理方式
+const (
+	SecretExportRedact  = "redact"  // 脱敏，导入时保留目标机器上的当前值
+	SecretExportEncrypt = "encrypt" // 使用口令加密，导入时需要输入相同的口令
+)
+
+// 配置导入模式
+const (
+	ConfigImportMerge   = "merge"   // 导入文件中的配置项覆盖当前值，未包含的配置项保持不变
+	ConfigImportReplace = "replace" // 导入文件包含的配置段整体替换，未包含的配置段保持不变
+)
+
+// redactedSecret 导出文件中脱敏后的敏感配置值
+const redactedSecret = "<redacted>"
+
+// encryptedSecretPrefix 导出文件中加密后的敏感配置值前缀
+const encryptedSecretPrefix = "encrypted:"
+
+// ConfigExportOptions 配置导出选项
+type ConfigExportOptions struct {
+	Format     string   // 文件格式: toml/json，为空时按文件扩展名判断
+	Sections   []string // 导出的配置段，为空时导出全部
+	Secrets    string   // 敏感配置项处理方式: redact/encrypt，默认 redact
+	Passphrase string   // 加密敏感配置项的口令
+}
+
+// ConfigImportPreview 配置导入预览
+type ConfigImportPreview struct {
+	Mode     string              // 导入模式: merge/replace
+	Sections []string            // 导入文件包含的配置段
+	Changes  []ConfigFieldChange // 导入后将发生变化的配置项
+	Errors   []ValidationError   // 导入后导入配置段中的校验错误，存在错误时不能导入
+	Redacted []string            // 导入文件中已脱敏、将保留当前值的敏感配置项
+}
+
+// configExportInfo 导出文件中的 [export] 段，记录导出时间和敏感配置项的处理方式
+type configExportInfo struct {
+	ExportedAt string   `toml:"exported_at" json:"exported_at"`
+	Sections   []string `toml:"sections" json:"sections"`
+	Secrets    string   `toml:"secrets" json:"secrets"`
+	Salt       string   `toml:"salt,omitempty" json:"salt,omitempty"` // 加密口令派生密钥使用的盐
+}
+
+// configSectionNames 返回配置中所有配置段的名称
+func configSectionNames() []string {
+	var names []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() == reflect.Struct {
+			names = append(names, configSectionName(t.Field(i)))
+		}
+	}
+	return names
+}
+
+// configFileFormat 确定文件格式，未指定时按扩展名判断，默认 TOML
+func configFileFormat(path, format string) (string, error) {
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			return ConfigFormatJSON, nil
+		}
+		return ConfigFormatTOML, nil
+	}
+	if format != ConfigFormatTOML && format != ConfigFormatJSON {
+		return "", fmt.Errorf("unsupported format '%s'", format)
+	}
+	return format, nil
+}
+
+// exportConfig 将配置中选定的配置段编码为导出文件内容
+// resolve 用于解密配置中的密钥引用，仅在加密敏感配置项时调用
+func exportConfig(config *Config, options ConfigExportOptions, format string, resolve func(string) (string, error)) ([]byte, error) {
+	all := configSectionNames()
+	sections := options.Sections
+	if len(sections) == 0 {
+		sections = all
+	}
+	for _, section := range sections {
+		if !containsString(all, section) {
+			return nil, fmt.Errorf("unknown config section '%s'", section)
+		}
+	}
+
+	data, err := encodeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := decodeRawConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config: %v", err)
+	}
+	exported := map[string]interface{}{"schema_version": int64(CurrentSchemaVersion)}
+	for _, section := range sections {
+		if value, exists := raw[section]; exists {
+			exported[section] = value
+		}
+	}
+
+	info := configExportInfo{
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Sections:   sections,
+		Secrets:    options.Secrets,
+	}
+	if info.Secrets == "" {
+		info.Secrets = SecretExportRedact
+	}
+
+	var key []byte
+	switch info.Secrets {
+	case SecretExportRedact:
+	case SecretExportEncrypt:
+		if options.Passphrase == "" {
+			return nil, fmt.Errorf("a passphrase is required to encrypt secrets")
+		}
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %v", err)
+		}
+		info.Salt = base64.StdEncoding.EncodeToString(salt)
+		if key, err = derivePassphraseKey(options.Passphrase, info.Salt); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown secrets option '%s'", info.Secrets)
+	}
+
+	for _, path := range configSecretFields {
+		section, field := splitConfigPath(path)
+		values, _ := exported[section].(map[string]interface{})
+		value, _ := values[field].(string)
+		if value == "" {
+			continue
+		}
+		if key == nil {
+			values[field] = redactedSecret
+			continue
+		}
+		plain, err := resolve(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret '%s': %v", path, err)
+		}
+		sealed, err := sealSecret(key, plain, path)
+		if err != nil {
+			return nil, err
+		}
+		values[field] = encryptedSecretPrefix + sealed
+	}
+	exported["export"] = info
+
+	if format == ConfigFormatJSON {
+		encoded, err := json.MarshalIndent(exported, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config: %v", err)
+		}
+		return encoded, nil
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(exported); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// prepareConfigImport 解析导入文件并与当前配置合并，返回导入后的配置和预览
+// 加密的敏感配置项使用 passphrase 解密，解密后为明文，由调用方保存到密钥存储
+func prepareConfigImport(current *Config, data []byte, format, mode, passphrase string) (*Config, *ConfigImportPreview, error) {
+	if mode == "" {
+		mode = ConfigImportMerge
+	}
+	if mode != ConfigImportMerge && mode != ConfigImportReplace {
+		return nil, nil, fmt.Errorf("unknown import mode '%s'", mode)
+	}
+
+	imported, err := decodeImportData(data, format)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse import file: %v", err)
+	}
+
+	var info configExportInfo
+	if value, exists := imported["export"]; exists {
+		if table, ok := value.(map[string]interface{}); ok {
+			info.Secrets, _ = table["secrets"].(string)
+			info.Salt, _ = table["salt"].(string)
+		}
+		delete(imported, "export")
+	}
+
+	all := configSectionNames()
+	preview := &ConfigImportPreview{Mode: mode, Sections: []string{}, Redacted: []string{}}
+	for name := range imported {
+		if name == "schema_version" {
+			continue
+		}
+		if !containsString(all, name) {
+			return nil, nil, fmt.Errorf("unknown config section '%s'", name)
+		}
+		preview.Sections = append(preview.Sections, name)
+	}
+	sort.Strings(preview.Sections)
+	if len(preview.Sections) == 0 {
+		return nil, nil, fmt.Errorf("import file contains no config sections")
+	}
+
+	// 迁移旧版本的导入文件，迁移中补全的配置段不属于导入内容
+	if _, err := migrateConfig(imported); err != nil {
+		return nil, nil, fmt.Errorf("failed to migrate import file: %v", err)
+	}
+	for name := range imported {
+		if name != "schema_version" && !containsString(preview.Sections, name) {
+			delete(imported, name)
+		}
+	}
+
+	encoded, err := encodeConfig(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := decodeRawConfig(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode config: %v", err)
+	}
+
+	if err := importSecrets(raw, imported, info, passphrase, preview); err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range preview.Sections {
+		section, ok := imported[name].(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("'%s' is not a table", name)
+		}
+		target, _ := raw[name].(map[string]interface{})
+		if mode == ConfigImportReplace || target == nil {
+			raw[name] = section
+		} else {
+			mergeRawTables(target, section)
+		}
+	}
+	raw["schema_version"] = int64(CurrentSchemaVersion)
+
+	next, _, err := buildConfig(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	preview.Changes = diffConfigs(current, next)
+	preview.Errors = append([]ValidationError{}, ValidateConfig(next).inSections(preview.Sections)...)
+	return next, preview, nil
+}
+
+// importSecrets 处理导入文件中的敏感配置项：脱敏的值替换为当前值，加密的值使用口令解密
+func importSecrets(current, imported map[string]interface{}, info configExportInfo, passphrase string, preview *ConfigImportPreview) error {
+	var key []byte
+	for _, path := range configSecretFields {
+		name, field := splitConfigPath(path)
+		section, _ := imported[name].(map[string]interface{})
+		value, _ := section[field].(string)
+
+		switch {
+		case value == redactedSecret:
+			preview.Redacted = append(preview.Redacted, path)
+			if currentSection, ok := current[name].(map[string]interface{}); ok {
+				section[field] = currentSection[field]
+			} else {
+				delete(section, field)
+			}
+		case strings.HasPrefix(value, encryptedSecretPrefix):
+			if key == nil {
+				if passphrase == "" {
+					return fmt.Errorf("import file contains encrypted secrets: a passphrase is required")
+				}
+				var err error
+				if key, err = derivePassphraseKey(passphrase, info.Salt); err != nil {
+					return err
+				}
+			}
+			plain, err := openSecret(key, strings.TrimPrefix(value, encryptedSecretPrefix), path)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt '%s': incorrect passphrase", path)
+			}
+			section[field] = string(plain)
+		}
+	}
+	return nil
+}
+
+// decodeImportData 按格式将导入文件解析为原始键值
+func decodeImportData(data []byte, format string) (map[string]interface{}, error) {
+	if format == ConfigFormatTOML {
+		return decodeRawConfig(data)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return normalizeJSONValue(raw).(map[string]interface{}), nil
+}
+
+// normalizeJSONValue 将 JSON 解析结果转换为与 TOML 解析结果一致的类型（整数为 int64，表数组为 []map）
+func normalizeJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeJSONValue(item)
+		}
+		return v
+	case []interface{}:
+		tables := make([]map[string]interface{}, 0, len(v))
+		for i, item := range v {
+			v[i] = normalizeJSONValue(item)
+			if table, ok := v[i].(map[string]interface{}); ok {
+				tables = append(tables, table)
+			}
+		}
+		if len(v) > 0 && len(tables) == len(v) {
+			return tables
+		}
+		return v
+	}
+	return value
+}
+
+// mergeRawTables 将 src 中的键值合并到 dst，表递归合并，其他值直接覆盖
+func mergeRawTables(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcTable, srcIsTable := value.(map[string]interface{})
+		dstTable, dstIsTable := dst[key].(map[string]interface{})
+		if srcIsTable && dstIsTable {
+			mergeRawTables(dstTable, srcTable)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// splitConfigPath 将配置项路径拆分为配置段和配置项名称
+func splitConfigPath(path string) (string, string) {
+	section, field, _ := strings.Cut(path, ".")
+	return section, field
+}
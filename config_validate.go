@@ -0,0 +1,336 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 配置校验错误代码
+const (
+	ValidationRequired       = "required"        // 必填项为空
+	ValidationInvalidPort    = "invalid_port"    // 端口不是 1-65535 之间的数字
+	ValidationInvalidValue   = "invalid_value"   // 取值不在允许范围内
+	ValidationInvalidFormat  = "invalid_format"  // 格式错误（地址、日期、URL 等）
+	ValidationOutOfRange     = "out_of_range"    // 数值超出范围
+	ValidationDuplicate      = "duplicate"       // 重复的键
+	ValidationNotFound       = "not_found"       // 引用的对象不存在
+	ValidationMissingOptions = "missing_options" // 选择类型的参数没有选项
+	ValidationInvalidDefault = "invalid_default" // 默认值与参数类型或选项不匹配
+)
+
+// configServices 可以出现在服务组和服务相关配置中的服务名称
+var configServices = []string{"workflowui", "edu-tools", "fileserver", "gateway"}
+
+// workflowParameterTypes 工作流参数支持的类型
+var workflowParameterTypes = []string{"text", "textarea", "select", "multiselect", "number", "boolean", "date", "file", "json"}
+
+// ValidationError 配置项校验错误
+type ValidationError struct {
+	Field   string // 配置项路径，如 eduexp.edu_tools_port、workflow.workflows.essay.parameters[0].options
+	Code    string // 错误代码
+	Message string // 错误说明
+}
+
+// ValidationErrors 配置校验错误列表
+type ValidationErrors []ValidationError
+
+// Error 实现 error
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = fmt.Sprintf("%s: %s", err.Field, err.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// inSections 筛选属于指定配置段的错误
+func (errs ValidationErrors) inSections(sections []string) ValidationErrors {
+	var filtered ValidationErrors
+	for _, err := range errs {
+		for _, section := range sections {
+			if err.Field == section || strings.HasPrefix(err.Field, section+".") {
+				filtered = append(filtered, err)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// configValidator 收集校验错误
+type configValidator struct {
+	errs ValidationErrors
+}
+
+// add 记录一条校验错误
+func (v *configValidator) add(field, code, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// port 校验端口，允许为空（使用默认端口）
+func (v *configValidator) port(field, value string) {
+	if value == "" {
+		return
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		v.add(field, ValidationInvalidPort, "'%s' is not a valid port (1-65535)", value)
+	}
+}
+
+// oneOf 校验枚举值，允许为空（使用默认值）
+func (v *configValidator) oneOf(field, value string, allowed ...string) {
+	if value == "" || containsString(allowed, value) {
+		return
+	}
+	v.add(field, ValidationInvalidValue, "unknown value '%s', expected one of: %s", value, strings.Join(allowed, ", "))
+}
+
+// nonNegative 校验数值不小于 0
+func (v *configValidator) nonNegative(field string, value int) {
+	if value < 0 {
+		v.add(field, ValidationOutOfRange, "must not be negative, got %d", value)
+	}
+}
+
+// ipAddress 校验 IP 地址，允许为空
+func (v *configValidator) ipAddress(field, value string) {
+	if value != "" && net.ParseIP(value) == nil {
+		v.add(field, ValidationInvalidFormat, "'%s' is not a valid IP address", value)
+	}
+}
+
+// service 校验服务名称
+func (v *configValidator) service(field, name string) {
+	if !containsString(configServices, name) {
+		v.add(field, ValidationInvalidValue, "unknown service '%s'", name)
+	}
+}
+
+// ValidateConfig 校验完整配置，返回所有配置段的错误
+func ValidateConfig(config *Config) ValidationErrors {
+	v := &configValidator{}
+	v.validateGlobal(config.Global)
+	v.validateEduExp(config.EduExp)
+	v.validateWorkflow(config.Workflow)
+	v.validateLicense(config.License)
+	v.validateServices(config.Services)
+	v.validateFileServer(config.FileServer)
+	v.validateBinaries(config.Binaries)
+	v.validateGateway(config.Gateway)
+	v.validateLAN(config.LAN)
+	v.validateTLS(config.TLS)
+	return v.errs
+}
+
+// validateGlobal 校验全局配置
+func (v *configValidator) validateGlobal(global GlobalConfig) {
+	v.oneOf("global.theme", global.Theme, "light", "dark")
+	v.nonNegative("global.history_limit", global.HistoryLimit)
+}
+
+// validateEduExp 校验 EduExp 配置
+func (v *configValidator) validateEduExp(eduexp EduExpConfig) {
+	v.port("eduexp.edu_tools_port", eduexp.EduToolsPort)
+}
+
+// validateWorkflow 校验工作流配置和每个工作流的参数定义
+func (v *configValidator) validateWorkflow(workflow WorkflowConfig) {
+	v.port("workflow.workflow_ui_port", workflow.WorkflowUIPort)
+
+	for _, key := range sortedKeys(workflow.Workflows) {
+		def := workflow.Workflows[key]
+		prefix := "workflow.workflows." + key
+		if strings.TrimSpace(key) == "" {
+			v.add(prefix, ValidationRequired, "workflow key is empty")
+		}
+		if strings.TrimSpace(def.Name) == "" {
+			v.add(prefix+".name", ValidationRequired, "workflow name is required")
+		}
+
+		seen := make(map[string]bool)
+		for i, parameter := range def.Parameters {
+			v.validateWorkflowParameter(fmt.Sprintf("%s.parameters[%d]", prefix, i), parameter, seen)
+		}
+		for i, parameter := range def.Parameters {
+			v.validateWorkflowConditions(fmt.Sprintf("%s.parameters[%d]", prefix, i), parameter, seen)
+		}
+	}
+}
+
+// validateWorkflowParameter 校验工作流参数：键唯一、类型有效、选择类型有选项、约束有效、默认值满足类型和约束
+func (v *configValidator) validateWorkflowParameter(prefix string, parameter WorkflowParameter, seen map[string]bool) {
+	if strings.TrimSpace(parameter.Key) == "" {
+		v.add(prefix+".key", ValidationRequired, "parameter key is required")
+	} else if seen[parameter.Key] {
+		v.add(prefix+".key", ValidationDuplicate, "duplicate parameter key '%s'", parameter.Key)
+	}
+	seen[parameter.Key] = true
+
+	v.oneOf(prefix+".type", parameter.Type, workflowParameterTypes...)
+
+	if (parameter.Type == "select" || parameter.Type == "multiselect") && len(parameter.Options) == 0 {
+		v.add(prefix+".options", ValidationMissingOptions, "%s parameter '%s' has no options", parameter.Type, parameter.Key)
+	}
+	if parameter.Min != nil && parameter.Max != nil && *parameter.Min > *parameter.Max {
+		v.add(prefix+".min", ValidationOutOfRange, "min %s is greater than max %s", formatWorkflowNumber(*parameter.Min), formatWorkflowNumber(*parameter.Max))
+	}
+	v.nonNegative(prefix+".min_length", parameter.MinLength)
+	v.nonNegative(prefix+".max_length", parameter.MaxLength)
+	if parameter.MaxLength > 0 && parameter.MinLength > parameter.MaxLength {
+		v.add(prefix+".min_length", ValidationOutOfRange, "min_length %d is greater than max_length %d", parameter.MinLength, parameter.MaxLength)
+	}
+	validPattern := true
+	if parameter.Pattern != "" {
+		if _, err := compileWorkflowPattern(parameter.Pattern); err != nil {
+			v.add(prefix+".pattern", ValidationInvalidFormat, "invalid pattern: %v", err)
+			validPattern = false
+		}
+	}
+
+	// 默认值需满足参数类型和约束，文件是否存在在使用时检查
+	if parameter.DefaultValue != "" && validPattern && containsString(workflowParameterTypes, parameter.Type) {
+		if _, err := checkWorkflowValue(parameter, parameter.DefaultValue, false); err != nil {
+			v.add(prefix+".default_value", ValidationInvalidDefault, "invalid default value: %v", err)
+		}
+	}
+}
+
+// validateWorkflowConditions 校验参数的显示条件：依赖的参数存在且不是参数本身，条件有取值
+func (v *configValidator) validateWorkflowConditions(prefix string, parameter WorkflowParameter, keys map[string]bool) {
+	for i, condition := range parameter.VisibleWhen {
+		field := fmt.Sprintf("%s.visible_when[%d]", prefix, i)
+		switch {
+		case condition.Key == parameter.Key:
+			v.add(field+".key", ValidationInvalidValue, "parameter '%s' cannot depend on itself", parameter.Key)
+		case !keys[condition.Key]:
+			v.add(field+".key", ValidationNotFound, "parameter '%s' not found", condition.Key)
+		}
+		if len(condition.Values) == 0 {
+			v.add(field+".values", ValidationRequired, "condition values are required")
+		}
+	}
+}
+
+// validateLicense 校验许可配置
+func (v *configValidator) validateLicense(license LicenseConfig) {
+	v.nonNegative("license.user_limit", license.UserLimit)
+	if license.ExpiryDate != "" {
+		if _, err := time.Parse("2006-01-02", license.ExpiryDate); err != nil {
+			v.add("license.expiry_date", ValidationInvalidFormat, "'%s' is not a date in YYYY-MM-DD format", license.ExpiryDate)
+		}
+	}
+}
+
+// validateServices 校验服务编排配置
+func (v *configValidator) validateServices(services ServicesConfig) {
+	if services.DefaultProfile != "" {
+		if _, exists := services.Profiles[services.DefaultProfile]; !exists {
+			v.add("services.default_profile", ValidationNotFound, "default profile '%s' not found", services.DefaultProfile)
+		}
+	}
+	v.oneOf("services.restart_mode", services.RestartMode, RestartModeStopStart, RestartModeBlueGreen)
+	v.nonNegative("services.ready_timeout", services.ReadyTimeout)
+	v.nonNegative("services.drain_timeout", services.DrainTimeout)
+
+	for _, key := range sortedKeys(services.Profiles) {
+		prefix := "services.profiles." + key
+		for i, name := range services.Profiles[key].Services {
+			v.service(fmt.Sprintf("%s.services[%d]", prefix, i), name)
+		}
+	}
+	for _, name := range sortedKeys(services.HealthPaths) {
+		field := "services.health_paths." + name
+		v.service(field, name)
+		if path := services.HealthPaths[name]; path != "" && !strings.HasPrefix(path, "/") {
+			v.add(field, ValidationInvalidFormat, "health check path '%s' must start with '/'", path)
+		}
+	}
+	for _, name := range sortedKeys(services.BindAddresses) {
+		field := "services.bind_addresses." + name
+		v.service(field, name)
+		v.ipAddress(field, services.BindAddresses[name])
+	}
+}
+
+// validateFileServer 校验文件服务器配置
+func (v *configValidator) validateFileServer(fileServer FileServerConfig) {
+	v.port("fileserver.port", fileServer.Port)
+}
+
+// validateBinaries 校验二进制文件管理配置
+func (v *configValidator) validateBinaries(binaries BinariesConfig) {
+	if binaries.ManifestURL != "" {
+		parsed, err := url.Parse(binaries.ManifestURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			v.add("binaries.manifest_url", ValidationInvalidFormat, "'%s' is not an http(s) URL", binaries.ManifestURL)
+		}
+	}
+	v.oneOf("binaries.signature_policy", binaries.SignaturePolicy, SignaturePolicyOff, SignaturePolicyWarn, SignaturePolicyEnforce)
+	v.nonNegative("binaries.keep_versions", binaries.KeepVersions)
+	for _, name := range sortedKeys(binaries.Pinned) {
+		if !containsString(managedComponents, name) {
+			v.add("binaries.pinned."+name, ValidationInvalidValue, "unknown component '%s'", name)
+		}
+	}
+	for _, name := range sortedKeys(binaries.Trusted) {
+		if !containsString(managedComponents, name) {
+			v.add("binaries.trusted."+name, ValidationInvalidValue, "unknown component '%s'", name)
+		} else if hash, err := hex.DecodeString(binaries.Trusted[name]); err != nil || len(hash) != sha256.Size {
+			v.add("binaries.trusted."+name, ValidationInvalidFormat, "must be a SHA-256 hash in hex")
+		}
+	}
+}
+
+// validateGateway 校验网关配置
+func (v *configValidator) validateGateway(gateway GatewayConfig) {
+	v.port("gateway.port", gateway.Port)
+	v.oneOf("gateway.routing", gateway.Routing, GatewayRoutingPath, GatewayRoutingSubdomain)
+}
+
+// validateLAN 校验局域网共享配置
+func (v *configValidator) validateLAN(lan LANConfig) {
+	v.oneOf("lan.auth_mode", lan.AuthMode, LANAuthToken, LANAuthPassword)
+	v.ipAddress("lan.bind_address", lan.BindAddress)
+	v.nonNegative("lan.session_ttl", lan.SessionTTL)
+	v.nonNegative("lan.max_clients", lan.MaxClients)
+	if lan.Enabled && lan.AuthMode == LANAuthPassword && lan.Password == "" {
+		v.add("lan.password", ValidationRequired, "password authentication requires a password")
+	}
+}
+
+// validateTLS 校验本地 HTTPS 配置
+func (v *configValidator) validateTLS(tlsConfig TLSConfig) {
+	for i, host := range tlsConfig.Hosts {
+		if strings.TrimSpace(host) == "" || (strings.ContainsAny(host, " /:") && net.ParseIP(host) == nil) {
+			v.add(fmt.Sprintf("tls.hosts[%d]", i), ValidationInvalidFormat, "'%s' is not a valid host name or IP address", host)
+		}
+	}
+}
+
+// containsString 判断切片是否包含指定字符串
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys 返回映射的有序键列表，使错误顺序稳定
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ExternalConfigChange 配置文件被外部修改（如手动编辑）的处理结果
+type ExternalConfigChange struct {
+	Sections     []string // 外部修改涉及的配置段
+	Conflicts    []string // 与应用内修改冲突的配置项，冲突时保留应用内的修改
+	ConflictFile string   // 发生冲突或无法解析时保存的外部修改副本
+	Problems     int      // 重新加载后配置中的校验错误数量
+	Error        string   // 外部修改无法解析时的错误
+}
+
+// configFileState 配置文件状态，用于判断文件是否被外部修改
+type configFileState struct {
+	hash    [32]byte  // 内容哈希
+	modTime time.Time // 修改时间
+	size    int64     // 文件大小
+	known   bool      // 是否已记录
+}
+
+// OnExternalChange 注册配置文件被外部修改时的处理函数
+func (cm *ConfigManager) OnExternalChange(handler func(ExternalConfigChange)) {
+	cm.subMu.Lock()
+	defer cm.subMu.Unlock()
+	cm.external = append(cm.external, handler)
+}
+
+// Watch 按间隔轮询配置文件的修改时间和内容哈希，发现外部修改后重新加载，返回停止函数
+func (cm *ConfigManager) Watch(interval time.Duration) func() {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				cm.checkExternalChange()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stop) })
+	}
+}
+
+// checkExternalChange 检查配置文件是否被外部修改，是则校验并重新加载
+// 应用内的修改在保存时立即写入文件，因此重新加载不会丢失未保存的修改
+func (cm *ConfigManager) checkExternalChange() {
+	cm.writeMu.Lock()
+
+	info, err := os.Stat(cm.configFile)
+	if err != nil || (cm.disk.known && info.ModTime().Equal(cm.disk.modTime) && info.Size() == cm.disk.size) {
+		cm.writeMu.Unlock()
+		return
+	}
+	data, err := os.ReadFile(cm.configFile)
+	if err != nil {
+		cm.writeMu.Unlock()
+		return
+	}
+	hash := sha256.Sum256(data)
+	if cm.disk.known && hash == cm.disk.hash {
+		// 内容未变化（如仅修改了时间）
+		cm.recordDiskState(data)
+		cm.writeMu.Unlock()
+		return
+	}
+
+	theirs, err := parseConfigData(data)
+	if err != nil {
+		// 无法解析时保留当前配置，等待用户修正；下次保存前会保存该文件的副本
+		if hash != cm.reportedBad {
+			cm.enqueueNotify(configEvent{external: &ExternalConfigChange{Error: err.Error()}})
+		}
+		cm.reportedBad = hash
+		cm.writeMu.Unlock()
+		cm.deliverNotifications()
+		return
+	}
+
+	previous := cm.snapshot()
+	// 手动填写的明文密钥加密保存后写回配置文件，不写入历史版本
+	if !cm.sealPlaintextSecrets(theirs) || cm.writeConfig(theirs) != nil {
+		cm.recordDiskState(data)
+		cm.recordHistory(data)
+	}
+	cm.assignWorkflowRevisions(theirs, previous)
+	cm.mu.Lock()
+	cm.config = theirs
+	cm.problems = cm.validate(theirs)
+	problems := len(cm.problems)
+	cm.mu.Unlock()
+	cm.enqueueNotify(configEvent{
+		previous: previous,
+		next:     theirs,
+		external: &ExternalConfigChange{
+			Sections: changedSections(previous, theirs),
+			Problems: problems,
+		},
+	})
+	cm.writeMu.Unlock()
+
+	cm.deliverNotifications()
+}
+
+// commit 保存修改后的配置，调用方需持有 cm.writeMu
+// 配置文件在上次读取或写入后被外部修改时，按配置项进行三方合并：
+// 只有一方修改的配置项取修改后的值，双方修改不同的配置项保留应用内的修改，并将外部修改的文件另存为冲突副本
+func (cm *ConfigManager) commit(base, ours *Config) (*Config, *ExternalConfigChange, error) {
+	lock, err := lockFile(cm.configFile+".lock", configLockTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock config file: %v", err)
+	}
+	defer lock.Unlock()
+
+	next := ours
+	var external *ExternalConfigChange
+	if data, err := os.ReadFile(cm.configFile); err == nil && cm.disk.known && sha256.Sum256(data) != cm.disk.hash {
+		external = &ExternalConfigChange{}
+		theirs, err := parseConfigData(data)
+		if err != nil {
+			external.Error = err.Error()
+		} else {
+			// 修订号不写入配置文件，内容未变化的工作流沿用当前修订号
+			carryWorkflowRevisions(theirs, base)
+			external.Sections = changedSections(base, theirs)
+			next, external.Conflicts = mergeConfigs(base, ours, theirs)
+		}
+		if external.Error != "" || len(external.Conflicts) > 0 {
+			if external.ConflictFile, err = cm.writeConflictCopy(data, theirs); err != nil {
+				return nil, nil, err
+			}
+		}
+		// 合并进来的外部修改可能包含明文密钥
+		cm.sealPlaintextSecrets(next)
+	}
+
+	data, err := encodeConfig(next)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cm.writeConfigLocked(data); err != nil {
+		return nil, nil, err
+	}
+	if external != nil {
+		external.Problems = len(cm.validate(next))
+	}
+	return next, external, nil
+}
+
+// parseConfigData 解析并迁移配置文件内容
+func parseConfigData(data []byte) (*Config, error) {
+	raw, err := decodeRawConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	}
+	config, _, err := buildConfig(raw)
+	return config, err
+}
+
+// recordDiskState 记录配置文件当前的内容哈希、修改时间和大小，调用方需持有 cm.writeMu
+func (cm *ConfigManager) recordDiskState(data []byte) {
+	cm.disk = configFileState{hash: sha256.Sum256(data), known: true}
+	if info, err := os.Stat(cm.configFile); err == nil {
+		cm.disk.modTime = info.ModTime()
+		cm.disk.size = info.Size()
+	}
+}
+
+// writeConflictCopy 将外部修改的配置文件另存为冲突副本，theirs 为解析后的外部修改，无法解析时为 nil
+func (cm *ConfigManager) writeConflictCopy(data []byte, theirs *Config) (string, error) {
+	path := fmt.Sprintf("%s.conflict-%s", cm.configFile, time.Now().Format("20060102-150405"))
+	if err := cm.writeConfigCopy(path, data, theirs); err != nil {
+		return "", fmt.Errorf("failed to save conflicting config copy: %v", err)
+	}
+	return path, nil
+}
+
+// notifyExternal 通知外部修改的处理结果
+func (cm *ConfigManager) notifyExternal(change ExternalConfigChange) {
+	cm.subMu.Lock()
+	handlers := append([]func(ExternalConfigChange){}, cm.external...)
+	cm.subMu.Unlock()
+
+	for _, handler := range handlers {
+		handler(change)
+	}
+}
+
+// mergeConfigs 以 base 为共同版本三方合并 ours 和 theirs，返回合并结果和冲突的配置项路径
+func mergeConfigs(base, ours, theirs *Config) (*Config, []string) {
+	merged := ours.Clone()
+	var conflicts []string
+
+	mergeField := func(path string, b, o, t, m reflect.Value) {
+		switch {
+		case reflect.DeepEqual(o.Interface(), b.Interface()):
+			m.Set(t)
+		case reflect.DeepEqual(t.Interface(), b.Interface()), reflect.DeepEqual(o.Interface(), t.Interface()):
+			// 只有应用内修改，或双方修改相同
+		default:
+			conflicts = append(conflicts, path)
+		}
+	}
+
+	bv := reflect.ValueOf(base).Elem()
+	ov := reflect.ValueOf(ours).Elem()
+	tv := reflect.ValueOf(theirs.Clone()).Elem()
+	mv := reflect.ValueOf(merged).Elem()
+	for i := 0; i < mv.NumField(); i++ {
+		field := mv.Type().Field(i)
+		name := configSectionName(field)
+		if field.Type.Kind() != reflect.Struct {
+			mergeField(name, bv.Field(i), ov.Field(i), tv.Field(i), mv.Field(i))
+			continue
+		}
+		for j := 0; j < field.Type.NumField(); j++ {
+			path := name + "." + configSectionName(field.Type.Field(j))
+			mergeField(path, bv.Field(i).Field(j), ov.Field(i).Field(j), tv.Field(i).Field(j), mv.Field(i).Field(j))
+		}
+	}
+	return merged, conflicts
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// configEdit 修改配置的函数，nil 表示不修改
+type configEdit func(config *Config)
+
+func TestMergeConfigs(t *testing.T) {
+	tests := []struct {
+		name          string
+		ours          configEdit
+		theirs        configEdit
+		want          configEdit
+		wantConflicts []string
+	}{
+		{
+			name: "only ours changed",
+			ours: func(c *Config) { c.Global.Theme = "dark" },
+			want: func(c *Config) { c.Global.Theme = "dark" },
+		},
+		{
+			name:   "only theirs changed",
+			theirs: func(c *Config) { c.FileServer.Port = "9002" },
+			want:   func(c *Config) { c.FileServer.Port = "9002" },
+		},
+		{
+			name:   "different fields changed",
+			ours:   func(c *Config) { c.Global.Theme = "dark" },
+			theirs: func(c *Config) { c.FileServer.Port = "9002" },
+			want: func(c *Config) {
+				c.Global.Theme = "dark"
+				c.FileServer.Port = "9002"
+			},
+		},
+		{
+			name:   "both changed the same way",
+			ours:   func(c *Config) { c.FileServer.Port = "9002" },
+			theirs: func(c *Config) { c.FileServer.Port = "9002" },
+			want:   func(c *Config) { c.FileServer.Port = "9002" },
+		},
+		{
+			name: "conflict keeps ours",
+			ours: func(c *Config) { c.FileServer.Port = "9001" },
+			theirs: func(c *Config) {
+				c.FileServer.Port = "9002"
+				c.Global.HistoryLimit = 5
+			},
+			want: func(c *Config) {
+				c.FileServer.Port = "9001"
+				c.Global.HistoryLimit = 5
+			},
+			wantConflicts: []string{"fileserver.port"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := GetDefaultConfig()
+			ours, theirs, want := base.Clone(), base.Clone(), base.Clone()
+			for _, edit := range []struct {
+				apply  configEdit
+				config *Config
+			}{{tt.ours, ours}, {tt.theirs, theirs}, {tt.want, want}} {
+				if edit.apply != nil {
+					edit.apply(edit.config)
+				}
+			}
+
+			merged, conflicts := mergeConfigs(base, ours, theirs)
+			if !reflect.DeepEqual(conflicts, tt.wantConflicts) {
+				t.Fatalf("conflicts = %v, want %v", conflicts, tt.wantConflicts)
+			}
+			if !reflect.DeepEqual(merged, want) {
+				t.Fatalf("merged config differs from the expected config")
+			}
+		})
+	}
+}
+
+func TestCommitMergesExternalEdits(t *testing.T) {
+	tests := []struct {
+		name             string
+		ours             configEdit
+		theirs           configEdit // 保存前对配置文件的外部修改，nil 表示未修改
+		theirsRaw        string     // 保存前写入的无法解析的外部修改
+		want             configEdit
+		wantExternal     bool
+		wantConflicts    []string
+		wantConflictFile bool
+		wantError        bool
+	}{
+		{
+			name: "only ours changed",
+			ours: func(c *Config) { c.Global.Theme = "dark" },
+			want: func(c *Config) { c.Global.Theme = "dark" },
+		},
+		{
+			name:         "only theirs changed",
+			ours:         func(c *Config) {},
+			theirs:       func(c *Config) { c.FileServer.Port = "9002" },
+			want:         func(c *Config) { c.FileServer.Port = "9002" },
+			wantExternal: true,
+		},
+		{
+			name:         "both changed the same way",
+			ours:         func(c *Config) { c.FileServer.Port = "9002" },
+			theirs:       func(c *Config) { c.FileServer.Port = "9002" },
+			want:         func(c *Config) { c.FileServer.Port = "9002" },
+			wantExternal: true,
+		},
+		{
+			name: "conflict keeps ours and saves a copy",
+			ours: func(c *Config) { c.FileServer.Port = "9001" },
+			theirs: func(c *Config) {
+				c.FileServer.Port = "9002"
+				c.Global.HistoryLimit = 5
+			},
+			want: func(c *Config) {
+				c.FileServer.Port = "9001"
+				c.Global.HistoryLimit = 5
+			},
+			wantExternal:     true,
+			wantConflicts:    []string{"fileserver.port"},
+			wantConflictFile: true,
+		},
+		{
+			name:             "unparseable external edit",
+			ours:             func(c *Config) { c.Global.Theme = "dark" },
+			theirsRaw:        "[global\ntheme = ",
+			want:             func(c *Config) { c.Global.Theme = "dark" },
+			wantExternal:     true,
+			wantConflictFile: true,
+			wantError:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cm, err := NewConfigManager(ConfigOptions{File: filepath.Join(dir, "config.toml")})
+			if err != nil {
+				t.Fatalf("failed to create config manager: %v", err)
+			}
+			var changes []ExternalConfigChange
+			cm.OnExternalChange(func(change ExternalConfigChange) {
+				changes = append(changes, change)
+			})
+			base := cm.FileConfig()
+
+			// 模拟在应用读取配置后手动编辑配置文件
+			var external []byte
+			switch {
+			case tt.theirs != nil:
+				theirs := base.Clone()
+				tt.theirs(theirs)
+				if external, err = encodeConfig(theirs); err != nil {
+					t.Fatal(err)
+				}
+			case tt.theirsRaw != "":
+				external = []byte(tt.theirsRaw)
+			}
+			if external != nil {
+				if err := os.WriteFile(cm.configFile, external, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := cm.update(tt.ours); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+
+			want := base.Clone()
+			tt.want(want)
+			if got := cm.FileConfig(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("saved config differs from the expected config")
+			}
+			if got := reloadConfig(t, cm); !reflect.DeepEqual(got, want) {
+				t.Fatalf("config file differs from the expected config")
+			}
+
+			if !tt.wantExternal {
+				if len(changes) != 0 {
+					t.Fatalf("unexpected external change %+v", changes)
+				}
+				return
+			}
+			if len(changes) != 1 {
+				t.Fatalf("got %d external changes, want 1", len(changes))
+			}
+			change := changes[0]
+			if !reflect.DeepEqual(change.Conflicts, tt.wantConflicts) {
+				t.Fatalf("conflicts = %v, want %v", change.Conflicts, tt.wantConflicts)
+			}
+			if (change.Error != "") != tt.wantError {
+				t.Fatalf("unexpected error %q", change.Error)
+			}
+
+			copies, _ := filepath.Glob(cm.configFile + ".conflict-*")
+			if !tt.wantConflictFile {
+				if change.ConflictFile != "" || len(copies) != 0 {
+					t.Fatalf("unexpected conflict copy %q", change.ConflictFile)
+				}
+				return
+			}
+			if len(copies) != 1 || copies[0] != change.ConflictFile {
+				t.Fatalf("conflict copies = %v, reported %q", copies, change.ConflictFile)
+			}
+			saved, err := os.ReadFile(change.ConflictFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(saved) != string(external) {
+				t.Fatalf("conflict copy does not match the external edit")
+			}
+		})
+	}
+}
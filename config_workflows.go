@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WorkflowConflictError 工作流已被其他窗口修改，提交的修订号已过期
+type WorkflowConflictError struct {
+	Key      string // 工作流键
+	Revision int    // 提交的修订号
+	Current  int    // 当前修订号
+}
+
+// Error 实现 error
+func (e *WorkflowConflictError) Error() string {
+	return fmt.Sprintf("workflow '%s' was modified elsewhere (revision %d, current revision %d); reload it and try again", e.Key, e.Revision, e.Current)
+}
+
+// AddWorkflow 添加工作流，返回新工作流的修订号
+func (cm *ConfigManager) AddWorkflow(key string, workflow WorkflowDef) (int, error) {
+	saved, err := cm.commitUpdate(func(config *Config) error {
+		if err := checkWorkflowKey(key); err != nil {
+			return err
+		}
+		if _, exists := config.Workflow.Workflows[key]; exists {
+			return workflowKeyError(key, ValidationDuplicate, "workflow '%s' already exists", key)
+		}
+		if config.Workflow.Workflows == nil {
+			config.Workflow.Workflows = make(map[string]WorkflowDef)
+		}
+		config.Workflow.Workflows[key] = workflow
+		return nil
+	})
+	return savedRevision(saved, key), err
+}
+
+// UpdateWorkflow 修改工作流，revision 与当前修订号不一致时拒绝修改，返回修改后的修订号
+func (cm *ConfigManager) UpdateWorkflow(key string, workflow WorkflowDef, revision int) (int, error) {
+	saved, err := cm.commitUpdate(func(config *Config) error {
+		if _, err := checkWorkflowRevision(config, key, revision); err != nil {
+			return err
+		}
+		config.Workflow.Workflows[key] = workflow
+		return nil
+	})
+	return savedRevision(saved, key), err
+}
+
+// DeleteWorkflow 删除工作流，revision 与当前修订号不一致时拒绝删除
+func (cm *ConfigManager) DeleteWorkflow(key string, revision int) error {
+	return cm.updateChecked(func(config *Config) error {
+		if _, err := checkWorkflowRevision(config, key, revision); err != nil {
+			return err
+		}
+		delete(config.Workflow.Workflows, key)
+		return nil
+	})
+}
+
+// RenameWorkflowKey 修改工作流的键，revision 与当前修订号不一致时拒绝修改，返回修改后的修订号
+func (cm *ConfigManager) RenameWorkflowKey(oldKey, newKey string, revision int) (int, error) {
+	saved, err := cm.commitUpdate(func(config *Config) error {
+		workflow, err := checkWorkflowRevision(config, oldKey, revision)
+		if err != nil {
+			return err
+		}
+		if err := checkWorkflowKey(newKey); err != nil {
+			return err
+		}
+		if _, exists := config.Workflow.Workflows[newKey]; exists {
+			return workflowKeyError(newKey, ValidationDuplicate, "workflow '%s' already exists", newKey)
+		}
+		delete(config.Workflow.Workflows, oldKey)
+		config.Workflow.Workflows[newKey] = workflow
+		return nil
+	})
+	return savedRevision(saved, newKey), err
+}
+
+// DuplicateWorkflow 复制工作流到新的键，副本名称加上“副本”后缀，返回副本的修订号
+func (cm *ConfigManager) DuplicateWorkflow(key, newKey string) (int, error) {
+	saved, err := cm.commitUpdate(func(config *Config) error {
+		source, exists := config.Workflow.Workflows[key]
+		if !exists {
+			return workflowKeyError(key, ValidationNotFound, "workflow '%s' not found", key)
+		}
+		if err := checkWorkflowKey(newKey); err != nil {
+			return err
+		}
+		if _, exists := config.Workflow.Workflows[newKey]; exists {
+			return workflowKeyError(newKey, ValidationDuplicate, "workflow '%s' already exists", newKey)
+		}
+
+		duplicate := source
+		duplicate.Name = source.Name + " 副本"
+		duplicate.Parameters = cloneWorkflowParameters(source.Parameters)
+		config.Workflow.Workflows[newKey] = duplicate
+		return nil
+	})
+	return savedRevision(saved, newKey), err
+}
+
+// savedRevision 获取保存后工作流的修订号，保存失败时返回 0
+func savedRevision(saved *Config, key string) int {
+	if saved == nil {
+		return 0
+	}
+	return saved.Workflow.Workflows[key].Revision
+}
+
+// checkWorkflowRevision 检查工作流存在且修订号未过期，返回当前定义
+func checkWorkflowRevision(config *Config, key string, revision int) (WorkflowDef, error) {
+	workflow, exists := config.Workflow.Workflows[key]
+	if !exists {
+		return WorkflowDef{}, workflowKeyError(key, ValidationNotFound, "workflow '%s' not found", key)
+	}
+	if workflow.Revision != revision {
+		return WorkflowDef{}, &WorkflowConflictError{Key: key, Revision: revision, Current: workflow.Revision}
+	}
+	return workflow, nil
+}
+
+// checkWorkflowKey 校验工作流键
+func checkWorkflowKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return workflowKeyError(key, ValidationRequired, "workflow key is required")
+	}
+	if key != strings.TrimSpace(key) {
+		return workflowKeyError(key, ValidationInvalidFormat, "workflow key '%s' has leading or trailing spaces", key)
+	}
+	return nil
+}
+
+// workflowKeyError 工作流键相关的校验错误
+func workflowKeyError(key, code, format string, args ...interface{}) ValidationErrors {
+	return ValidationErrors{{Field: "workflow.workflows." + key, Code: code, Message: fmt.Sprintf(format, args...)}}
+}
+
+// assignWorkflowRevisions 为工作流分配修订号：内容与 sources 中同一键的工作流相同时沿用其修订号，否则分配新的修订号
+// 新的修订号来自只增不减的计数器，删除后重新添加、恢复历史版本或外部修改的工作流不会复用旧的修订号
+func (cm *ConfigManager) assignWorkflowRevisions(next *Config, sources ...*Config) {
+	for key, workflow := range next.Workflow.Workflows {
+		revision := 0
+		for _, source := range sources {
+			if source == nil {
+				continue
+			}
+			if current, exists := source.Workflow.Workflows[key]; exists && current.Revision > 0 && workflowContentEqual(current, workflow) {
+				revision = current.Revision
+				break
+			}
+		}
+		if revision == 0 {
+			revision = cm.nextWorkflowRevision()
+		}
+		workflow.Revision = revision
+		next.Workflow.Workflows[key] = workflow
+	}
+}
+
+// carryWorkflowRevisions 从配置文件读取的工作流没有修订号，内容与 base 相同的工作流沿用 base 的修订号
+func carryWorkflowRevisions(config, base *Config) {
+	for key, workflow := range config.Workflow.Workflows {
+		if current, exists := base.Workflow.Workflows[key]; exists && workflowContentEqual(current, workflow) {
+			workflow.Revision = current.Revision
+			config.Workflow.Workflows[key] = workflow
+		}
+	}
+}
+
+// nextWorkflowRevision 分配新的工作流修订号，计数器保存在配置目录中，应用重启后继续递增
+func (cm *ConfigManager) nextWorkflowRevision() int {
+	cm.revisionMu.Lock()
+	defer cm.revisionMu.Unlock()
+
+	path := filepath.Join(cm.configDir, "workflow_revision")
+	if !cm.revisionLoaded {
+		cm.revisionLoaded = true
+		if data, err := os.ReadFile(path); err == nil {
+			cm.revision, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		}
+	}
+	cm.revision++
+	writeFileAtomic(path, []byte(strconv.Itoa(cm.revision)+"\n"), 0644)
+	return cm.revision
+}
+
+// workflowContentEqual 比较工作流定义的内容，忽略修订号
+func workflowContentEqual(a, b WorkflowDef) bool {
+	a.Revision, b.Revision = 0, 0
+	return reflect.DeepEqual(a, b)
+}
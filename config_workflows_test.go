@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestConfigManager 在临时目录创建配置管理器
+func newTestConfigManager(t *testing.T) *ConfigManager {
+	t.Helper()
+
+	cm, err := NewConfigManager(ConfigOptions{File: filepath.Join(t.TempDir(), "config.toml")})
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	return cm
+}
+
+// testWorkflow 创建测试用的工作流定义
+func testWorkflow(name string) WorkflowDef {
+	return WorkflowDef{Name: name, WorkflowID: "wf-" + name}
+}
+
+// assertWorkflowConflict 检查修改因修订号过期被拒绝
+func assertWorkflowConflict(t *testing.T, err error) {
+	t.Helper()
+
+	var conflict *WorkflowConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("got %v, want a WorkflowConflictError", err)
+	}
+}
+
+func TestWorkflowRevisionConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		// change 在编辑器读取修订号之后由其他窗口进行的修改
+		change func(t *testing.T, cm *ConfigManager, revision int)
+	}{
+		{
+			name: "updated elsewhere",
+			change: func(t *testing.T, cm *ConfigManager, revision int) {
+				if _, err := cm.UpdateWorkflow("quiz", testWorkflow("quiz v2"), revision); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "deleted and added again",
+			change: func(t *testing.T, cm *ConfigManager, revision int) {
+				if err := cm.DeleteWorkflow("quiz", revision); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := cm.AddWorkflow("quiz", testWorkflow("quiz")); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "deleted and duplicated onto the key",
+			change: func(t *testing.T, cm *ConfigManager, revision int) {
+				if err := cm.DeleteWorkflow("quiz", revision); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := cm.AddWorkflow("other", testWorkflow("quiz")); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := cm.DuplicateWorkflow("other", "quiz"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "old config restored",
+			change: func(t *testing.T, cm *ConfigManager, revision int) {
+				snapshot := cm.FileConfig()
+				if _, err := cm.UpdateWorkflow("quiz", testWorkflow("quiz v2"), revision); err != nil {
+					t.Fatal(err)
+				}
+				// 恢复历史版本、导入配置和切换配置档案都会整体替换配置
+				if err := cm.ReplaceConfig(snapshot); err != nil {
+					t.Fatal(err)
+				}
+				current := cm.FileConfig().Workflow.Workflows["quiz"]
+				if current.Name != "quiz" || current.Revision == revision {
+					t.Fatalf("restored workflow %+v reuses revision %d", current, revision)
+				}
+			},
+		},
+		{
+			name: "edited externally",
+			change: func(t *testing.T, cm *ConfigManager, revision int) {
+				edited := cm.FileConfig()
+				edited.Workflow.Workflows["quiz"] = testWorkflow("quiz edited")
+				data, err := encodeConfig(edited)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(cm.configFile, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+				cm.checkExternalChange()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newTestConfigManager(t)
+			revision, err := cm.AddWorkflow("quiz", testWorkflow("quiz"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(t, cm, revision)
+
+			_, err = cm.UpdateWorkflow("quiz", testWorkflow("stale edit"), revision)
+			assertWorkflowConflict(t, err)
+			if err := cm.DeleteWorkflow("quiz", revision); err == nil {
+				t.Fatalf("stale delete should fail")
+			}
+		})
+	}
+}
+
+func TestWorkflowRevisionsIncreaseAcrossRestarts(t *testing.T) {
+	cm := newTestConfigManager(t)
+	first, err := cm.AddWorkflow("quiz", testWorkflow("quiz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewConfigManager(ConfigOptions{File: cm.configFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := reopened.FileConfig().Workflow.Workflows["quiz"].Revision
+	if loaded <= first {
+		t.Fatalf("revision after restart = %d, want more than %d", loaded, first)
+	}
+	if _, err := reopened.UpdateWorkflow("quiz", testWorkflow("stale edit"), first); err == nil {
+		t.Fatalf("revision from before the restart should be rejected")
+	}
+}
+
+func TestUpdateWorkflowConfigKeepsWorkflows(t *testing.T) {
+	cm := newTestConfigManager(t)
+	if _, err := cm.AddWorkflow("quiz", testWorkflow("quiz")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 设置页读取配置后，其他窗口添加了工作流
+	stale := cm.FileConfig().Workflow
+	if _, err := cm.AddWorkflow("essay", testWorkflow("essay")); err != nil {
+		t.Fatal(err)
+	}
+
+	stale.ApiKey = "pat_123"
+	stale.WorkflowUIPort = "9000"
+	delete(stale.Workflows, "quiz")
+	if err := cm.UpdateWorkflowConfig(stale); err != nil {
+		t.Fatalf("failed to update workflow config: %v", err)
+	}
+
+	saved := cm.FileConfig().Workflow
+	if saved.WorkflowUIPort != "9000" {
+		t.Fatalf("port = %s, want 9000", saved.WorkflowUIPort)
+	}
+	for _, key := range []string{"quiz", "essay"} {
+		if _, exists := saved.Workflows[key]; !exists {
+			t.Fatalf("workflow '%s' was dropped by a bulk save", key)
+		}
+	}
+}
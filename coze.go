@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// cozeAPIBase 扣子开放平台 API 地址
+var cozeAPIBase = "https://api.coze.cn"
+
+// cozeClient 调用扣子 API 使用的 HTTP 客户端
+var cozeClient = &http.Client{Timeout: 10 * time.Minute}
+
+// cozeWorkflowRunRequest 运行工作流的请求
+type cozeWorkflowRunRequest struct {
+	WorkflowID string                 `json:"workflow_id"`
+	AppID      string                 `json:"app_id,omitempty"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// cozeWorkflowRunResponse 运行工作流的响应，Code 不为 0 表示失败
+type cozeWorkflowRunResponse struct {
+	Code     int    `json:"code"`
+	Msg      string `json:"msg"`
+	Data     string `json:"data"`
+	DebugURL string `json:"debug_url"`
+}
+
+// runCozeWorkflow 调用扣子 API 同步运行工作流，parameters 需已按参数定义校验
+func runCozeWorkflow(ctx context.Context, apiKey string, workflow WorkflowDef, parameters map[string]interface{}) (*cozeWorkflowRunResponse, error) {
+	body, err := json.Marshal(cozeWorkflowRunRequest{
+		WorkflowID: workflow.WorkflowID,
+		AppID:      workflow.AppID,
+		Parameters: parameters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode parameters: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cozeAPIBase+"/v1/workflow/run", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := cozeClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	var result cozeWorkflowRunResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unexpected response (HTTP %d): %.200s", resp.StatusCode, data)
+	}
+	if result.Code != 0 {
+		return &result, fmt.Errorf("coze error %d: %s", result.Code, result.Msg)
+	}
+	return &result, nil
+}
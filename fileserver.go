@@ -0,0 +1,283 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileServer 内置的静态文件服务器，替代外部的 caddy 进程
+// 作为进程内服务注册到进程管理器，支持 Range 请求、gzip 压缩、目录浏览开关和访问日志
+type FileServer struct {
+	mu     sync.Mutex
+	server *http.Server // 当前运行的 HTTP 服务，未运行时为 nil
+}
+
+// fileServerOptions 文件服务器启动参数
+type fileServerOptions struct {
+	listen    string
+	root      string
+	browse    bool
+	gzip      bool
+	accessLog bool
+	tlsCert   string
+	tlsKey    string
+}
+
+// NewFileServer 创建文件服务器
+func NewFileServer() *FileServer {
+	return &FileServer{}
+}
+
+// parseFileServerArgs 解析启动参数
+func parseFileServerArgs(args []string) (*fileServerOptions, error) {
+	opts := &fileServerOptions{}
+	flags := flag.NewFlagSet("fileserver", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.listen, "listen", "0.0.0.0:8081", "listen address")
+	flags.StringVar(&opts.root, "root", "", "root directory")
+	flags.BoolVar(&opts.browse, "browse", false, "enable directory listing")
+	flags.BoolVar(&opts.gzip, "gzip", false, "enable gzip compression")
+	flags.BoolVar(&opts.accessLog, "access-log", false, "write access log")
+	flags.StringVar(&opts.tlsCert, "tls-cert", "", "TLS certificate file")
+	flags.StringVar(&opts.tlsKey, "tls-key", "", "TLS key file")
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("invalid file server arguments: %v", err)
+	}
+	if opts.root == "" {
+		return nil, fmt.Errorf("file server root directory is not specified")
+	}
+	return opts, nil
+}
+
+// Start 启动文件服务器，端口被占用等错误会立即返回
+func (fs *FileServer) Start(args []string, output io.Writer) (<-chan error, error) {
+	opts, err := parseFileServerArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(opts.root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create root directory: %v", err)
+	}
+	root, err := filepath.Abs(opts.root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root directory: %v", err)
+	}
+
+	var handler http.Handler
+	if opts.browse {
+		handler = http.FileServer(http.Dir(root))
+	} else {
+		handler = http.FileServer(noListingFileSystem{http.Dir(root)})
+	}
+	if opts.gzip {
+		handler = gzipHandler(handler)
+	}
+	if opts.accessLog {
+		handler = accessLogHandler(handler, output)
+	}
+
+	listener, err := net.Listen("tcp", opts.listen)
+	if err != nil {
+		return nil, err
+	}
+	if opts.tlsCert != "" {
+		if listener, err = tlsListenerOrClose(listener, opts.tlsCert, opts.tlsKey); err != nil {
+			return nil, err
+		}
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fs.mu.Lock()
+	fs.server = server
+	fs.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(output, "File server listening on %s, serving %s\n", listener.Addr(), root)
+		err := server.Serve(listener)
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+		done <- err
+	}()
+	return done, nil
+}
+
+// Stop 停止文件服务器，等待进行中的请求完成直到 ctx 超时
+func (fs *FileServer) Stop(ctx context.Context) error {
+	fs.mu.Lock()
+	server := fs.server
+	fs.server = nil
+	fs.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		// 超时后强制关闭剩余连接
+		server.Close()
+		return err
+	}
+	return nil
+}
+
+// noListingFileSystem 禁止目录浏览的文件系统，没有 index.html 的目录返回 404
+type noListingFileSystem struct {
+	fs http.FileSystem
+}
+
+// Open 实现 http.FileSystem
+func (nfs noListingFileSystem) Open(name string) (http.File, error) {
+	file, err := nfs.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		index, err := nfs.fs.Open(path.Join(name, "index.html"))
+		if err != nil {
+			file.Close()
+			return nil, os.ErrNotExist
+		}
+		index.Close()
+	}
+	return file, nil
+}
+
+// compressibleType 判断内容类型是否值得压缩
+func compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml",
+		"application/wasm", "image/svg+xml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// gzipHandler 对支持 gzip 的客户端压缩文本类响应
+// Range 请求不压缩，以保证返回的字节区间与原文件一致
+func gzipHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || r.Header.Get("Range") != "" ||
+			!strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// gzipResponseWriter 在写入响应头时决定是否压缩
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer      *gzip.Writer
+	wroteHeader bool
+}
+
+// WriteHeader 实现 http.ResponseWriter
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding")
+	if status == http.StatusOK && header.Get("Content-Encoding") == "" && compressibleType(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		header.Del("Accept-Ranges")
+		w.writer = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write 实现 http.ResponseWriter
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(data))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.writer != nil {
+		return w.writer.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Close 结束 gzip 数据流
+func (w *gzipResponseWriter) Close() error {
+	if w.writer != nil {
+		return w.writer.Close()
+	}
+	return nil
+}
+
+// accessLogHandler 以 Common Log Format 记录每个请求
+func accessLogHandler(next http.Handler, output io.Writer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		fmt.Fprintf(output, "%s - - [%s] \"%s %s %s\" %d %d\n",
+			host, time.Now().Format("02/Jan/2006:15:04:05 -0700"),
+			r.Method, r.URL.RequestURI(), r.Proto, recorder.status, recorder.bytes)
+	})
+}
+
+// statusRecorder 记录响应状态码和写入的字节数
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader 实现 http.ResponseWriter
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write 实现 http.ResponseWriter
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(data)
+	r.bytes += int64(n)
+	return n, err
+}
@@ -1,5 +1,5 @@
 import { useState, useEffect } from 'react';
-import { StartEduTools, StopEduTools, GetEduToolsStatus, GetEduToolsOutput, GetEduExpConfig, UpdateEduExpConfig } from "../../wailsjs/go/main/App";
+import { StartEduTools, StopEduTools, GetEduToolsStatus, GetEduToolsOutput, GetEduExpConfig, UpdateEduExpConfig, GetServiceURL } from "../../wailsjs/go/main/App";
 import { AlertTriangle, Info, Copy, ExternalLink, Edit } from 'lucide-react';
 
 interface EduToolsService {
@@ -23,10 +23,13 @@ export default function ApiServerPage() {
   const [tempPort, setTempPort] = useState('8080');
   const [logs, setLogs] = useState<string[]>([]);
   const [isLoading, setIsLoading] = useState(false);
+  const [serviceUrl, setServiceUrl] = useState('http://localhost:8080');
 
   // EduTools 状态检查
   const checkEduToolsStatus = async () => {
     try {
+      // 网关运行时通过网关地址访问服务
+      setServiceUrl(await GetServiceURL('edu-tools'));
       const status = await GetEduToolsStatus();
       if (status.includes('running') || status.includes('started')) {
         setEduToolsService(prev => ({ ...prev, status: 'running' }));
@@ -222,7 +225,7 @@ export default function ApiServerPage() {
               <button
                 className="btn btn-outline"
                 onClick={() => {
-                  const url = `http://localhost:${eduToolsService.port}`;
+                  const url = serviceUrl;
                   console.log('尝试打开URL:', url);
                   
                   // 尝试打开新窗口
@@ -237,7 +240,7 @@ export default function ApiServerPage() {
                   }, 500);
                 }}
                 disabled={eduToolsService.status !== 'running'}
-                title={eduToolsService.status !== 'running' ? '服务未运行' : `访问 http://localhost:${eduToolsService.port}`}
+                title={eduToolsService.status !== 'running' ? '服务未运行' : `访问 ${serviceUrl}`}
               >
                 <svg className="w-4 h-4 mr-2" fill="none" stroke="currentColor" viewBox="0 0 24 24">
                   <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M10 6H6a2 2 0 00-2 2v10a2 2 0 002 2h10a2 2 0 002-2v-4M14 4h6m0 0v6m0-6L10 14" />
@@ -388,13 +391,13 @@ export default function ApiServerPage() {
                     <input 
                       type="text" 
                       className="input input-bordered flex-1 font-mono text-sm"
-                      value={`http://localhost:${eduToolsService.port}`}
+                      value={serviceUrl}
                       readOnly
                     />
                     <button 
                       className="btn btn-square btn-outline btn-sm"
                       onClick={() => {
-                        const url = `http://localhost:${eduToolsService.port}`;
+                        const url = serviceUrl;
                         if (navigator.clipboard) {
                           navigator.clipboard.writeText(url).then(() => {
                             alert('地址已复制到剪贴板！');
@@ -427,7 +430,7 @@ export default function ApiServerPage() {
                   <button 
                     className="btn btn-outline"
                     onClick={() => {
-                      const url = `http://localhost:${eduToolsService.port}`;
+                      const url = serviceUrl;
                       window.location.href = url;
                     }}
                   >
@@ -437,7 +440,7 @@ export default function ApiServerPage() {
                   <button 
                     className="btn btn-outline"
                     onClick={() => {
-                      const url = `http://localhost:${eduToolsService.port}`;
+                      const url = serviceUrl;
                       try {
                         window.open(url, '_blank', 'noopener,noreferrer');
                       } catch (error) {
@@ -35,7 +35,7 @@ export default function ProcessManager() {
     const nameMap: Record<string, string> = {
       'workflowui': 'WorkflowUI 服务',
       'edu-tools': 'EduTools 工具',
-      'caddy-fileserver': 'Caddy 文件服务器'
+      'fileserver': '文件服务器'
     };
     return nameMap[processName] || processName;
   };
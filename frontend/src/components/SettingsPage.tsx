@@ -1,5 +1,5 @@
 import { useState, useEffect } from 'react';
-import { GetGlobalConfig, UpdateGlobalConfig, GetConfigInfo, GetEduExpConfig, UpdateEduExpConfig, GetWorkflowConfig, UpdateWorkflowConfig, GetLicenseConfig, UpdateLicenseConfig } from '../../wailsjs/go/main/App';
+import { GetGlobalConfig, UpdateGlobalConfig, GetConfigInfo, GetEduExpConfig, UpdateEduExpConfig, GetWorkflowConfig, UpdateWorkflowConfig, AddWorkflow, UpdateWorkflow, DeleteWorkflow, GetLicenseConfig, UpdateLicenseConfig } from '../../wailsjs/go/main/App';
 import { main } from '../../wailsjs/go/models';
 import { Settings, Building, Workflow, Eye, EyeOff, Download, Key, X, Plus, Trash2, Edit } from 'lucide-react';
 
@@ -24,6 +24,7 @@ interface WorkflowDef {
   workflow_id: string;
   app_id: string;
   parameters: WorkflowParameter[];
+  revision?: number; // 后端修订号，修改时提交，工作流已在其他窗口修改时拒绝
 }
 
 interface WorkflowParameter {
@@ -33,6 +34,19 @@ interface WorkflowParameter {
   required: boolean;
   options: string[];
   default_value: string;
+  help_text: string;
+  min?: number;
+  max?: number;
+  min_length: number;
+  max_length: number;
+  pattern: string;
+  visible_when: WorkflowCondition[];
+}
+
+// 参数的显示条件：参数 key 的值为 values 之一
+interface WorkflowCondition {
+  key: string;
+  values: string[];
 }
 
 interface LicenseSettings {
@@ -42,6 +56,79 @@ interface LicenseSettings {
   featureFlags: string[];
 }
 
+// 转换后端工作流数据结构到前端格式
+const toFrontendWorkflows = (backendWorkflows: Record<string, main.WorkflowDef> | undefined) => {
+  const workflows: Record<string, WorkflowDef> = {};
+  Object.entries(backendWorkflows || {}).forEach(([key, backendWorkflow]) => {
+    workflows[key] = {
+      name: backendWorkflow.Name || '',
+      workflow_id: backendWorkflow.WorkflowID || '',
+      app_id: backendWorkflow.AppID || '',
+      parameters: (backendWorkflow.Parameters || []).map(param => ({
+        key: param.Key || '',
+        label: param.Label || '',
+        type: param.Type || 'text',
+        required: param.Required || false,
+        options: param.Options || [],
+        default_value: param.DefaultValue || '',
+        help_text: param.HelpText || '',
+        min: param.Min ?? undefined,
+        max: param.Max ?? undefined,
+        min_length: param.MinLength || 0,
+        max_length: param.MaxLength || 0,
+        pattern: param.Pattern || '',
+        visible_when: (param.VisibleWhen || []).map(condition => ({
+          key: condition.Key || '',
+          values: condition.Values || []
+        }))
+      })),
+      revision: backendWorkflow.Revision || 0
+    };
+  });
+  return workflows;
+};
+
+// 转换前端工作流数据结构到后端格式
+const toBackendWorkflow = (workflow: WorkflowDef): any => ({
+  Name: workflow.name,
+  WorkflowID: workflow.workflow_id,
+  AppID: workflow.app_id,
+  Parameters: workflow.parameters.map((param) => ({
+    Key: param.key,
+    Label: param.label,
+    Type: param.type,
+    Required: param.required,
+    Options: param.options,
+    DefaultValue: param.default_value,
+    HelpText: param.help_text,
+    Min: param.min,
+    Max: param.max,
+    MinLength: param.min_length,
+    MaxLength: param.max_length,
+    Pattern: param.pattern,
+    VisibleWhen: param.visible_when.map((condition) => ({
+      Key: condition.key,
+      Values: condition.values
+    }))
+  }))
+});
+
+// 解析数值输入，空输入表示不限制
+const parseOptionalNumber = (value: string) => (value.trim() === '' ? undefined : Number(value));
+
+// 显示条件的文本格式：每行一个条件，形如“参数键=取值1,取值2”
+const formatConditions = (conditions: WorkflowCondition[]) =>
+  conditions.map((condition) => `${condition.key}=${condition.values.join(',')}`).join('\n');
+
+const parseConditions = (text: string): WorkflowCondition[] =>
+  text.split('\n').filter((line) => line.trim()).map((line) => {
+    const [key, ...rest] = line.split('=');
+    return {
+      key: key.trim(),
+      values: rest.join('=').split(',').map((value) => value.trim()).filter((value) => value)
+    };
+  });
+
 type SettingSection = 'global' | 'eduexp' | 'workflow' | 'license';
 
 export default function SettingsPage() {
@@ -223,29 +310,9 @@ export default function SettingsPage() {
         // 加载工作流配置
         try {
           const workflowConfig = await GetWorkflowConfig();
-          // 转换后端数据结构到前端格式
-          const workflows: Record<string, WorkflowDef> = {};
-          if (workflowConfig.Workflows) {
-            Object.entries(workflowConfig.Workflows).forEach(([key, backendWorkflow]) => {
-              workflows[key] = {
-                name: backendWorkflow.Name || '',
-                workflow_id: backendWorkflow.WorkflowID || '',
-                app_id: backendWorkflow.AppID || '',
-                parameters: (backendWorkflow.Parameters || []).map(param => ({
-                  key: param.Key || '',
-                  label: param.Label || '',
-                  type: param.Type || 'text',
-                  required: param.Required || false,
-                  options: param.Options || [],
-                  default_value: param.DefaultValue || ''
-                }))
-              };
-            });
-          }
-          
           setWorkflowSettings({
             apiKey: workflowConfig.ApiKey || '',
-            workflows: workflows
+            workflows: toFrontendWorkflows(workflowConfig.Workflows)
           });
         } catch (error) {
           console.error('Failed to load workflow config:', error);
@@ -321,28 +388,13 @@ export default function SettingsPage() {
           console.log('Saved eduexp settings:', settings);
           break;
         case 'workflow':
-          // 转换前端工作流数据结构到后端格式
+          // 工作流定义通过单独的接口修改，后端忽略这里提交的工作流定义，只保存 API Key，端口使用后端的当前值
           const workflowSettings = settings as WorkflowSettings;
-          const backendWorkflows: Record<string, any> = {};
-          Object.entries(workflowSettings.workflows).forEach(([key, workflow]) => {
-            backendWorkflows[key] = {
-              Name: workflow.name,
-              WorkflowID: workflow.workflow_id,
-              AppID: workflow.app_id,
-              Parameters: workflow.parameters.map((param) => ({
-                Key: param.key,
-                Label: param.label,
-                Type: param.type,
-                Required: param.required,
-                Options: param.options,
-                DefaultValue: param.default_value
-              }))
-            };
-          });
-          
+          const currentWorkflowConfig = await GetWorkflowConfig();
           backendSettings = {
             ApiKey: workflowSettings.apiKey,
-            Workflows: backendWorkflows
+            WorkflowUIPort: currentWorkflowConfig.WorkflowUIPort,
+            Workflows: currentWorkflowConfig.Workflows
           };
           await UpdateWorkflowConfig(backendSettings);
           console.log('Saved workflow settings:', settings);
@@ -431,36 +483,54 @@ export default function SettingsPage() {
     });
   };
 
-  const saveWorkflow = () => {
+  // 重新加载工作流定义，工作流已在其他窗口修改时使用
+  const reloadWorkflows = async () => {
+    const workflowConfig = await GetWorkflowConfig();
+    setWorkflowSettings(prev => ({ ...prev, workflows: toFrontendWorkflows(workflowConfig.Workflows) }));
+  };
+
+  // 处理工作流修改结果，修订号过期时重新加载
+  const handleWorkflowResult = async (result: main.WorkflowResult) => {
+    if (result.Success) {
+      await reloadWorkflows();
+      return true;
+    }
+    if (result.Conflict) {
+      alert('该工作流已在其他窗口中修改，已重新加载，请再次修改');
+      await reloadWorkflows();
+    } else {
+      alert(result.Message);
+    }
+    return false;
+  };
+
+  const saveWorkflow = async () => {
     if (!editingWorkflow.name.trim()) {
       alert('请输入工作流名称');
       return;
     }
 
-    const newWorkflows = { ...workflowSettings.workflows };
     const workflowKey = editingWorkflowKey || editingWorkflow.name.toLowerCase().replace(/\s+/g, '_');
-    
-    if (editingWorkflowKey && editingWorkflowKey !== workflowKey) {
-      // 如果名称改变了，删除旧的key
-      delete newWorkflows[editingWorkflowKey];
+    try {
+      const result = editingWorkflowKey
+        ? await UpdateWorkflow(workflowKey, toBackendWorkflow(editingWorkflow), editingWorkflow.revision || 0)
+        : await AddWorkflow(workflowKey, toBackendWorkflow(editingWorkflow));
+      if (await handleWorkflowResult(result)) {
+        closeWorkflowModal();
+      }
+    } catch (error) {
+      console.error('Failed to save workflow:', error);
     }
-    
-    newWorkflows[workflowKey] = editingWorkflow;
-    
-    const newSettings = { ...workflowSettings, workflows: newWorkflows };
-    setWorkflowSettings(newSettings);
-    autoSave('workflow', newSettings);
-    closeWorkflowModal();
   };
 
-  const deleteWorkflow = (workflowKey: string) => {
+  const deleteWorkflow = async (workflowKey: string) => {
     if (confirm('确定要删除这个工作流吗？')) {
-      const newWorkflows = { ...workflowSettings.workflows };
-      delete newWorkflows[workflowKey];
-      
-      const newSettings = { ...workflowSettings, workflows: newWorkflows };
-      setWorkflowSettings(newSettings);
-      autoSave('workflow', newSettings);
+      try {
+        const revision = workflowSettings.workflows[workflowKey]?.revision || 0;
+        await handleWorkflowResult(await DeleteWorkflow(workflowKey, revision));
+      } catch (error) {
+        console.error('Failed to delete workflow:', error);
+      }
     }
   };
 
@@ -475,7 +545,12 @@ export default function SettingsPage() {
           type: 'text',
           required: false,
           options: [],
-          default_value: ''
+          default_value: '',
+          help_text: '',
+          min_length: 0,
+          max_length: 0,
+          pattern: '',
+          visible_when: []
         }
       ]
     });
@@ -991,9 +1066,14 @@ export default function SettingsPage() {
                             onChange={(e) => updateParameter(index, 'type', e.target.value)}
                           >
                             <option value="text">文本</option>
+                            <option value="textarea">多行文本</option>
                             <option value="select">选择</option>
+                            <option value="multiselect">多选</option>
                             <option value="number">数字</option>
                             <option value="boolean">布尔值</option>
+                            <option value="date">日期</option>
+                            <option value="file">文件</option>
+                            <option value="json">JSON</option>
                           </select>
                         </div>
 
@@ -1023,7 +1103,109 @@ export default function SettingsPage() {
                         </label>
                       </div>
 
-                      {param.type === 'select' && (
+                      <div className="mt-3">
+                        <label className="label">
+                          <span className="label-text">帮助说明</span>
+                        </label>
+                        <input
+                          type="text"
+                          className="input input-bordered input-sm w-full"
+                          value={param.help_text}
+                          onChange={(e) => updateParameter(index, 'help_text', e.target.value)}
+                          placeholder="填写时显示的说明"
+                        />
+                      </div>
+
+                      {param.type === 'number' && (
+                        <div className="grid grid-cols-2 gap-4 mt-3">
+                          <div>
+                            <label className="label">
+                              <span className="label-text">最小值</span>
+                            </label>
+                            <input
+                              type="number"
+                              className="input input-bordered input-sm w-full"
+                              value={param.min ?? ''}
+                              onChange={(e) => updateParameter(index, 'min', parseOptionalNumber(e.target.value))}
+                              placeholder="不限制"
+                            />
+                          </div>
+                          <div>
+                            <label className="label">
+                              <span className="label-text">最大值</span>
+                            </label>
+                            <input
+                              type="number"
+                              className="input input-bordered input-sm w-full"
+                              value={param.max ?? ''}
+                              onChange={(e) => updateParameter(index, 'max', parseOptionalNumber(e.target.value))}
+                              placeholder="不限制"
+                            />
+                          </div>
+                        </div>
+                      )}
+
+                      {(param.type === 'text' || param.type === 'textarea' || param.type === 'multiselect') && (
+                        <div className="grid grid-cols-2 gap-4 mt-3">
+                          <div>
+                            <label className="label">
+                              <span className="label-text">{param.type === 'multiselect' ? '最少选择' : '最小长度'}</span>
+                            </label>
+                            <input
+                              type="number"
+                              min={0}
+                              className="input input-bordered input-sm w-full"
+                              value={param.min_length || ''}
+                              onChange={(e) => updateParameter(index, 'min_length', parseOptionalNumber(e.target.value) || 0)}
+                              placeholder="不限制"
+                            />
+                          </div>
+                          <div>
+                            <label className="label">
+                              <span className="label-text">{param.type === 'multiselect' ? '最多选择' : '最大长度'}</span>
+                            </label>
+                            <input
+                              type="number"
+                              min={0}
+                              className="input input-bordered input-sm w-full"
+                              value={param.max_length || ''}
+                              onChange={(e) => updateParameter(index, 'max_length', parseOptionalNumber(e.target.value) || 0)}
+                              placeholder="不限制"
+                            />
+                          </div>
+                        </div>
+                      )}
+
+                      {(param.type === 'text' || param.type === 'textarea') && (
+                        <div className="mt-3">
+                          <label className="label">
+                            <span className="label-text">格式（正则表达式，需匹配全部内容）</span>
+                          </label>
+                          <input
+                            type="text"
+                            className="input input-bordered input-sm w-full font-mono"
+                            value={param.pattern}
+                            onChange={(e) => updateParameter(index, 'pattern', e.target.value)}
+                            placeholder="例如 [A-Z]\d+"
+                          />
+                        </div>
+                      )}
+
+                      <div className="mt-3">
+                        <label className="label">
+                          <span className="label-text">显示条件（每行一个，形如 参数键=取值1,取值2，全部满足时显示）</span>
+                        </label>
+                        <textarea
+                          key={`${index}:${formatConditions(param.visible_when)}`}
+                          className="textarea textarea-bordered w-full"
+                          rows={2}
+                          defaultValue={formatConditions(param.visible_when)}
+                          onBlur={(e) => updateParameter(index, 'visible_when', parseConditions(e.target.value))}
+                          placeholder="mode=custom"
+                        />
+                      </div>
+
+                      {(param.type === 'select' || param.type === 'multiselect') && (
                         <div className="mt-3">
                           <label className="label">
                             <span className="label-text">选项列表（每行一个）</span>
@@ -1,5 +1,7 @@
 import { useState, useEffect } from 'react';
-import { GetWorkflowConfig } from '../../wailsjs/go/main/App';
+import { GetWorkflowConfig, RunWorkflow } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 import WorkflowProcessManager from './WorkflowProcessManager';
 
 interface WorkflowItem {
@@ -10,12 +12,134 @@ interface WorkflowItem {
   lastRun: string;
   workflow_id: string;
   app_id: string;
+  parameters: main.WorkflowParameter[];
 }
 
+// 参数的当前取值，未填写时使用默认值
+const parameterValue = (param: main.WorkflowParameter, values: Record<string, any>) => {
+  const value = values[param.Key];
+  if (value === undefined || value === '' || (Array.isArray(value) && value.length === 0)) {
+    return param.DefaultValue || '';
+  }
+  return value;
+};
+
+// 参数是否显示：全部显示条件满足时显示，最终以后端校验为准
+const isParameterVisible = (param: main.WorkflowParameter, parameters: main.WorkflowParameter[], values: Record<string, any>) =>
+  (param.VisibleWhen || []).every((condition) => {
+    const dependency = parameters.find((p) => p.Key === condition.Key);
+    if (!dependency) {
+      return false;
+    }
+    const value = parameterValue(dependency, values);
+    const selected = Array.isArray(value) ? value : String(value).split(',').map((v) => v.trim());
+    return selected.some((v) => (condition.Values || []).includes(String(v)));
+  });
+
 export default function WorkflowPage() {
   const [workflows, setWorkflows] = useState<WorkflowItem[]>([]);
   const [loading, setLoading] = useState(true);
 
+  // 运行工作流弹窗状态
+  const [runningWorkflow, setRunningWorkflow] = useState<WorkflowItem | null>(null);
+  const [runValues, setRunValues] = useState<Record<string, any>>({});
+  const [runResult, setRunResult] = useState<main.WorkflowRunResult | null>(null);
+  const [running, setRunning] = useState(false);
+
+  const openRunModal = (workflow: WorkflowItem) => {
+    setRunningWorkflow(workflow);
+    setRunValues({});
+    setRunResult(null);
+  };
+
+  const closeRunModal = () => {
+    setRunningWorkflow(null);
+    setRunResult(null);
+  };
+
+  // 提交输入，后端按参数定义校验通过后才发送到扣子
+  const runWorkflow = async () => {
+    if (!runningWorkflow) {
+      return;
+    }
+    setRunning(true);
+    try {
+      setRunResult(await RunWorkflow(runningWorkflow.id, runValues));
+    } catch (error) {
+      console.error('Failed to run workflow:', error);
+    } finally {
+      setRunning(false);
+    }
+  };
+
+  const fieldError = (key: string) => runResult?.Errors?.find((error) => error.Field === key)?.Message;
+
+  const renderParameterInput = (param: main.WorkflowParameter) => {
+    const value = runValues[param.Key] ?? '';
+    const setValue = (next: any) => setRunValues({ ...runValues, [param.Key]: next });
+    switch (param.Type) {
+      case 'textarea':
+      case 'json':
+        return (
+          <textarea
+            className={`textarea textarea-bordered w-full ${param.Type === 'json' ? 'font-mono' : ''}`}
+            rows={3}
+            value={value}
+            onChange={(e) => setValue(e.target.value)}
+            placeholder={param.DefaultValue}
+          />
+        );
+      case 'select':
+        return (
+          <select className="select select-bordered select-sm w-full" value={value || param.DefaultValue || ''} onChange={(e) => setValue(e.target.value)}>
+            <option value="">请选择</option>
+            {(param.Options || []).map((option) => (
+              <option key={option} value={option}>{option}</option>
+            ))}
+          </select>
+        );
+      case 'multiselect': {
+        const selected: string[] = Array.isArray(value) ? value : [];
+        return (
+          <div className="flex flex-wrap gap-3">
+            {(param.Options || []).map((option) => (
+              <label key={option} className="label cursor-pointer gap-2">
+                <input
+                  type="checkbox"
+                  className="checkbox checkbox-sm"
+                  checked={selected.includes(option)}
+                  onChange={(e) => setValue(e.target.checked ? [...selected, option] : selected.filter((o) => o !== option))}
+                />
+                <span className="label-text">{option}</span>
+              </label>
+            ))}
+          </div>
+        );
+      }
+      case 'boolean':
+        return (
+          <input
+            type="checkbox"
+            className="toggle toggle-sm"
+            checked={value === '' ? param.DefaultValue === 'true' : value === true}
+            onChange={(e) => setValue(e.target.checked)}
+          />
+        );
+      default:
+        return (
+          <input
+            type={param.Type === 'number' ? 'number' : param.Type === 'date' ? 'date' : 'text'}
+            className="input input-bordered input-sm w-full"
+            value={value}
+            min={param.Min}
+            max={param.Max}
+            onChange={(e) => setValue(e.target.value)}
+            placeholder={param.Type === 'file' ? '文件路径或链接' : param.DefaultValue}
+          />
+        );
+    }
+  };
+
   // 加载工作流配置
   useEffect(() => {
     const loadWorkflows = async () => {
@@ -33,7 +157,8 @@ export default function WorkflowPage() {
               description: workflow.WorkflowID ? `工作流ID: ${workflow.WorkflowID}` : '无工作流ID',
               lastRun: '未调用',
               workflow_id: workflow.WorkflowID || '',
-              app_id: workflow.AppID || ''
+              app_id: workflow.AppID || '',
+              parameters: workflow.Parameters || []
             });
           });
         }
@@ -48,6 +173,13 @@ export default function WorkflowPage() {
     };
 
     loadWorkflows();
+
+    // 工作流配置在其他页面修改后重新加载
+    return EventsOn('config:changed', (section: string) => {
+      if (section === 'workflow') {
+        loadWorkflows();
+      }
+    });
   }, []);
 
   if (loading) {
@@ -85,6 +217,7 @@ export default function WorkflowPage() {
                     <tr>
                       <th>功能名称</th>
                       <th>描述</th>
+                      <th>操作</th>
                     </tr>
                   </thead>
                   <tbody>
@@ -99,6 +232,11 @@ export default function WorkflowPage() {
                         <td>
                           <div className="text-sm text-base-content opacity-70">{workflow.description}</div>
                         </td>
+                        <td>
+                          <button onClick={() => openRunModal(workflow)} className="btn btn-sm btn-outline">
+                            运行
+                          </button>
+                        </td>
                       </tr>
                     ))}
                   </tbody>
@@ -108,6 +246,49 @@ export default function WorkflowPage() {
           </div>
         </div>
       </div>
+
+      {/* 运行工作流弹窗 */}
+      {runningWorkflow && (
+        <div className="modal modal-open">
+          <div className="modal-box max-w-2xl">
+            <h3 className="font-bold text-lg mb-4">运行：{runningWorkflow.name}</h3>
+
+            <div className="space-y-3">
+              {runningWorkflow.parameters
+                .filter((param) => isParameterVisible(param, runningWorkflow.parameters, runValues))
+                .map((param) => (
+                  <div key={param.Key}>
+                    <label className="label">
+                      <span className="label-text">
+                        {param.Label || param.Key}
+                        {param.Required && <span className="text-error"> *</span>}
+                      </span>
+                    </label>
+                    {renderParameterInput(param)}
+                    {param.HelpText && <div className="text-xs text-base-content opacity-60 mt-1">{param.HelpText}</div>}
+                    {fieldError(param.Key) && <div className="text-xs text-error mt-1">{fieldError(param.Key)}</div>}
+                  </div>
+                ))}
+            </div>
+
+            {runResult && (
+              <div className={`alert mt-4 ${runResult.Success ? 'alert-success' : 'alert-error'}`}>
+                <div className="w-full">
+                  <div>{runResult.Message}</div>
+                  {runResult.Output && <pre className="text-xs mt-2 whitespace-pre-wrap break-all">{runResult.Output}</pre>}
+                </div>
+              </div>
+            )}
+
+            <div className="modal-action">
+              <button onClick={closeRunModal} className="btn btn-ghost">关闭</button>
+              <button onClick={runWorkflow} className="btn btn-primary" disabled={running}>
+                {running ? '运行中...' : '运行'}
+              </button>
+            </div>
+          </div>
+        </div>
+      )}
     </div>
   );
 } 
\ No newline at end of file
@@ -5,7 +5,8 @@ import {
   GetWorkflowUIStatus, 
   GetWorkflowUIOutput,
   GetWorkflowConfig,
-  UpdateWorkflowConfig
+  UpdateWorkflowConfig,
+  GetServiceURL
 } from '../../wailsjs/go/main/App';
 
 interface WorkflowService {
@@ -28,6 +29,7 @@ export default function WorkflowProcessManager() {
   const [tempPort, setTempPort] = useState('8080');
   const [logs, setLogs] = useState<string[]>([]);
   const [isLoading, setIsLoading] = useState(false);
+  const [serviceUrl, setServiceUrl] = useState('http://localhost:8080');
 
   const loadPortFromConfig = async () => {
     try {
@@ -45,6 +47,8 @@ export default function WorkflowProcessManager() {
 
   const checkProcessStatus = async () => {
     try {
+      // 网关运行时通过网关地址访问服务
+      setServiceUrl(await GetServiceURL('workflowui'));
       const status = await GetWorkflowUIStatus();
       if (status.includes('running') || status.includes('started')) {
         setWorkflowService(prev => ({ ...prev, status: 'running' }));
@@ -220,7 +224,7 @@ export default function WorkflowProcessManager() {
           <button
             className="btn btn-outline"
             onClick={() => {
-              const url = `http://localhost:${workflowService.port}`;
+              const url = serviceUrl;
               console.log('尝试打开URL:', url);
               
               // 尝试打开新窗口
@@ -235,7 +239,7 @@ export default function WorkflowProcessManager() {
               }, 500);
             }}
             disabled={workflowService.status !== 'running'}
-            title={workflowService.status !== 'running' ? '服务未运行' : `访问 http://localhost:${workflowService.port}`}
+            title={workflowService.status !== 'running' ? '服务未运行' : `访问 ${serviceUrl}`}
           >
             <svg className="w-4 h-4 mr-2" fill="none" stroke="currentColor" viewBox="0 0 24 24">
               <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M10 6H6a2 2 0 00-2 2v10a2 2 0 002 2h10a2 2 0 002-2v-4M14 4h6m0 0v6m0-6L10 14" />
@@ -392,13 +396,13 @@ export default function WorkflowProcessManager() {
                 <input 
                   type="text" 
                   className="input input-bordered flex-1 font-mono text-sm"
-                  value={`http://localhost:${workflowService.port}`}
+                  value={serviceUrl}
                   readOnly
                 />
                 <button 
                   className="btn btn-square btn-outline btn-sm"
                   onClick={() => {
-                    const url = `http://localhost:${workflowService.port}`;
+                    const url = serviceUrl;
                     if (navigator.clipboard) {
                       navigator.clipboard.writeText(url).then(() => {
                         alert('地址已复制到剪贴板！');
@@ -435,7 +439,7 @@ export default function WorkflowProcessManager() {
               <button 
                 className="btn btn-outline"
                 onClick={() => {
-                  const url = `http://localhost:${workflowService.port}`;
+                  const url = serviceUrl;
                   window.location.href = url;
                 }}
               >
@@ -447,7 +451,7 @@ export default function WorkflowProcessManager() {
               <button 
                 className="btn btn-outline"
                 onClick={() => {
-                  const url = `http://localhost:${workflowService.port}`;
+                  const url = serviceUrl;
                   try {
                     window.open(url, '_blank', 'noopener,noreferrer');
                   } catch (error) {
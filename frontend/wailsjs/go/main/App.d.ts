@@ -2,14 +2,50 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function ActivateConfigProfile(arg1:string):Promise<string>;
+
+export function AddWorkflow(arg1:string,arg2:main.WorkflowDef):Promise<main.WorkflowResult>;
+
+export function CloneConfigProfile(arg1:string,arg2:string):Promise<string>;
+
+export function CreateConfigProfile(arg1:string):Promise<string>;
+
+export function DeleteConfigProfile(arg1:string):Promise<string>;
+
+export function DeleteWorkflow(arg1:string,arg2:number):Promise<main.WorkflowResult>;
+
+export function DiffConfigVersions(arg1:string,arg2:string):Promise<Array<main.ConfigFieldChange>>;
+
+export function DuplicateWorkflow(arg1:string,arg2:string):Promise<main.WorkflowResult>;
+
+export function ExportCACertificate(arg1:string):Promise<string>;
+
+export function ExportConfig(arg1:string,arg2:main.ConfigExportOptions):Promise<string>;
+
+export function ExportWorkflows(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function GetActiveConfigProfile():Promise<string>;
+
 export function GetAllProcessStatus():Promise<Record<string, string>>;
 
 export function GetAppDataDir():Promise<string>;
 
+export function GetBinariesConfig():Promise<main.BinariesConfig>;
+
+export function GetCACertificate():Promise<string>;
+
+export function GetCAInfo():Promise<main.CAInfo>;
+
+export function GetComponentVersions(arg1:string):Promise<Array<main.ArchivedVersion>>;
+
 export function GetConfigFilePath():Promise<string>;
 
 export function GetConfigInfo():Promise<Record<string, string>>;
 
+export function GetConfigProblems():Promise<Array<main.ValidationError>>;
+
+export function GetConfigSources():Promise<Array<main.ConfigValueSource>>;
+
 export function GetEduExpConfig():Promise<main.EduExpConfig>;
 
 export function GetEduToolsOutput():Promise<string>;
@@ -18,8 +54,18 @@ export function GetEduToolsStatus():Promise<string>;
 
 export function GetFullConfig():Promise<main.Config>;
 
+export function GetGatewayConfig():Promise<main.GatewayConfig>;
+
+export function GetGatewayInfo():Promise<main.GatewayInfo>;
+
 export function GetGlobalConfig():Promise<main.GlobalConfig>;
 
+export function GetInstalledComponents():Promise<Array<main.InstalledComponent>>;
+
+export function GetLANAccess():Promise<main.LANAccessInfo>;
+
+export function GetLANConfig():Promise<main.LANConfig>;
+
 export function GetLicenseConfig():Promise<main.LicenseConfig>;
 
 export function GetProcessOutput(arg1:string):Promise<string>;
@@ -30,40 +76,134 @@ export function GetRegisteredProcessNames():Promise<Array<string>>;
 
 export function GetRegisteredProcesses():Promise<Array<string>>;
 
+export function GetReleaseManifest():Promise<main.ReleaseManifest>;
+
+export function GetRestartRequired():Promise<Record<string, Array<string>>>;
+
+export function GetSecretsStatus():Promise<main.SecretsStatus>;
+
 export function GetServerOutput():Promise<string>;
 
 export function GetServerStatus():Promise<string>;
 
+export function GetServiceProfiles():Promise<Record<string, main.ServiceProfile>>;
+
+export function GetServiceURL(arg1:string):Promise<string>;
+
+export function GetServicesConfig():Promise<main.ServicesConfig>;
+
+export function GetStartupDiagnostics():Promise<main.StartupDiagnostics>;
+
+export function GetTLSConfig():Promise<main.TLSConfig>;
+
 export function GetWorkflowConfig():Promise<main.WorkflowConfig>;
 
 export function GetWorkflowUIOutput():Promise<string>;
 
 export function GetWorkflowUIStatus():Promise<string>;
 
+export function ImportConfig(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ImportWorkflows(arg1:string,arg2:string):Promise<string>;
+
+export function InstallComponent(arg1:string,arg2:string):Promise<string>;
+
+export function ListConfigHistory():Promise<Array<main.ConfigVersion>>;
+
+export function ListConfigProfiles():Promise<Array<main.ConfigProfile>>;
+
+export function LockSecrets():Promise<string>;
+
+export function PinComponent(arg1:string,arg2:string):Promise<string>;
+
+export function PreviewConfigImport(arg1:string,arg2:string,arg3:string):Promise<main.ConfigImportPreview>;
+
+export function PreviewWorkflowImport(arg1:string,arg2:string):Promise<main.WorkflowImportPreview>;
+
+export function QueryEventJournal(arg1:main.EventQuery):Promise<main.EventQueryResult>;
+
+export function RegenerateCACertificate():Promise<string>;
+
+export function RegenerateLANToken():Promise<string>;
+
 export function RegisterProcess(arg1:string,arg2:main.ProcessConfig):Promise<void>;
 
+export function RenameConfigProfile(arg1:string,arg2:string):Promise<string>;
+
+export function RenameWorkflowKey(arg1:string,arg2:string,arg3:number):Promise<main.WorkflowResult>;
+
 export function ResetConfigToDefault():Promise<string>;
 
+export function RestartService(arg1:string):Promise<string>;
+
+export function RestartServiceBlueGreen(arg1:string):Promise<string>;
+
+export function RestoreConfigVersion(arg1:string):Promise<string>;
+
+export function RevokeLANSessions():Promise<string>;
+
+export function RollbackComponent(arg1:string):Promise<string>;
+
+export function RunWorkflow(arg1:string,arg2:Record<string, any>):Promise<main.WorkflowRunResult>;
+
+export function SetSecretsPassphrase(arg1:string):Promise<string>;
+
 export function StartEduTools(arg1:Array<string>):Promise<string>;
 
+export function StartGateway():Promise<string>;
+
 export function StartGinServer(arg1:string):Promise<string>;
 
 export function StartProcess(arg1:string,arg2:Array<string>):Promise<string>;
 
+export function StartProfile(arg1:string):Promise<main.ProfileResult>;
+
 export function StartWorkflowUI(arg1:Array<string>):Promise<string>;
 
 export function StopEduTools():Promise<string>;
 
+export function StopGateway():Promise<string>;
+
 export function StopGinServer():Promise<string>;
 
 export function StopProcess(arg1:string):Promise<string>;
 
+export function StopProfile(arg1:string):Promise<main.ProfileResult>;
+
 export function StopWorkflowUI():Promise<string>;
 
+export function TrustComponent(arg1:string):Promise<string>;
+
+export function UnlockSecrets(arg1:string):Promise<string>;
+
+export function UnpinComponent(arg1:string):Promise<string>;
+
+export function UntrustComponent(arg1:string):Promise<string>;
+
+export function UpdateBinariesConfig(arg1:main.BinariesConfig):Promise<string>;
+
+export function UpdateComponents():Promise<Array<main.ServiceResult>>;
+
 export function UpdateEduExpConfig(arg1:main.EduExpConfig):Promise<string>;
 
+export function UpdateGatewayConfig(arg1:main.GatewayConfig):Promise<string>;
+
 export function UpdateGlobalConfig(arg1:main.GlobalConfig):Promise<string>;
 
+export function UpdateLANConfig(arg1:main.LANConfig):Promise<string>;
+
 export function UpdateLicenseConfig(arg1:main.LicenseConfig):Promise<string>;
 
+export function UpdateServicesConfig(arg1:main.ServicesConfig):Promise<string>;
+
+export function UpdateTLSConfig(arg1:main.TLSConfig):Promise<string>;
+
+export function UpdateWorkflow(arg1:string,arg2:main.WorkflowDef,arg3:number):Promise<main.WorkflowResult>;
+
 export function UpdateWorkflowConfig(arg1:main.WorkflowConfig):Promise<string>;
+
+export function ValidateConfig(arg1:main.Config):Promise<Array<main.ValidationError>>;
+
+export function ValidateWorkflowInput(arg1:string,arg2:Record<string, any>):Promise<main.WorkflowInputResult>;
+
+export function VerifyInstalledComponents():Promise<Array<main.BinaryVerification>>;
@@ -2,6 +2,54 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ActivateConfigProfile(arg1) {
+  return window['go']['main']['App']['ActivateConfigProfile'](arg1);
+}
+
+export function AddWorkflow(arg1, arg2) {
+  return window['go']['main']['App']['AddWorkflow'](arg1, arg2);
+}
+
+export function CloneConfigProfile(arg1, arg2) {
+  return window['go']['main']['App']['CloneConfigProfile'](arg1, arg2);
+}
+
+export function CreateConfigProfile(arg1) {
+  return window['go']['main']['App']['CreateConfigProfile'](arg1);
+}
+
+export function DeleteConfigProfile(arg1) {
+  return window['go']['main']['App']['DeleteConfigProfile'](arg1);
+}
+
+export function DeleteWorkflow(arg1, arg2) {
+  return window['go']['main']['App']['DeleteWorkflow'](arg1, arg2);
+}
+
+export function DiffConfigVersions(arg1, arg2) {
+  return window['go']['main']['App']['DiffConfigVersions'](arg1, arg2);
+}
+
+export function DuplicateWorkflow(arg1, arg2) {
+  return window['go']['main']['App']['DuplicateWorkflow'](arg1, arg2);
+}
+
+export function ExportCACertificate(arg1) {
+  return window['go']['main']['App']['ExportCACertificate'](arg1);
+}
+
+export function ExportConfig(arg1, arg2) {
+  return window['go']['main']['App']['ExportConfig'](arg1, arg2);
+}
+
+export function ExportWorkflows(arg1, arg2) {
+  return window['go']['main']['App']['ExportWorkflows'](arg1, arg2);
+}
+
+export function GetActiveConfigProfile() {
+  return window['go']['main']['App']['GetActiveConfigProfile']();
+}
+
 export function GetAllProcessStatus() {
   return window['go']['main']['App']['GetAllProcessStatus']();
 }
@@ -10,6 +58,22 @@ export function GetAppDataDir() {
   return window['go']['main']['App']['GetAppDataDir']();
 }
 
+export function GetBinariesConfig() {
+  return window['go']['main']['App']['GetBinariesConfig']();
+}
+
+export function GetCACertificate() {
+  return window['go']['main']['App']['GetCACertificate']();
+}
+
+export function GetCAInfo() {
+  return window['go']['main']['App']['GetCAInfo']();
+}
+
+export function GetComponentVersions(arg1) {
+  return window['go']['main']['App']['GetComponentVersions'](arg1);
+}
+
 export function GetConfigFilePath() {
   return window['go']['main']['App']['GetConfigFilePath']();
 }
@@ -18,6 +82,14 @@ export function GetConfigInfo() {
   return window['go']['main']['App']['GetConfigInfo']();
 }
 
+export function GetConfigProblems() {
+  return window['go']['main']['App']['GetConfigProblems']();
+}
+
+export function GetConfigSources() {
+  return window['go']['main']['App']['GetConfigSources']();
+}
+
 export function GetEduExpConfig() {
   return window['go']['main']['App']['GetEduExpConfig']();
 }
@@ -34,10 +106,30 @@ export function GetFullConfig() {
   return window['go']['main']['App']['GetFullConfig']();
 }
 
+export function GetGatewayConfig() {
+  return window['go']['main']['App']['GetGatewayConfig']();
+}
+
+export function GetGatewayInfo() {
+  return window['go']['main']['App']['GetGatewayInfo']();
+}
+
 export function GetGlobalConfig() {
   return window['go']['main']['App']['GetGlobalConfig']();
 }
 
+export function GetInstalledComponents() {
+  return window['go']['main']['App']['GetInstalledComponents']();
+}
+
+export function GetLANAccess() {
+  return window['go']['main']['App']['GetLANAccess']();
+}
+
+export function GetLANConfig() {
+  return window['go']['main']['App']['GetLANConfig']();
+}
+
 export function GetLicenseConfig() {
   return window['go']['main']['App']['GetLicenseConfig']();
 }
@@ -58,6 +150,18 @@ export function GetRegisteredProcesses() {
   return window['go']['main']['App']['GetRegisteredProcesses']();
 }
 
+export function GetReleaseManifest() {
+  return window['go']['main']['App']['GetReleaseManifest']();
+}
+
+export function GetRestartRequired() {
+  return window['go']['main']['App']['GetRestartRequired']();
+}
+
+export function GetSecretsStatus() {
+  return window['go']['main']['App']['GetSecretsStatus']();
+}
+
 export function GetServerOutput() {
   return window['go']['main']['App']['GetServerOutput']();
 }
@@ -66,6 +170,26 @@ export function GetServerStatus() {
   return window['go']['main']['App']['GetServerStatus']();
 }
 
+export function GetServiceProfiles() {
+  return window['go']['main']['App']['GetServiceProfiles']();
+}
+
+export function GetServiceURL(arg1) {
+  return window['go']['main']['App']['GetServiceURL'](arg1);
+}
+
+export function GetServicesConfig() {
+  return window['go']['main']['App']['GetServicesConfig']();
+}
+
+export function GetStartupDiagnostics() {
+  return window['go']['main']['App']['GetStartupDiagnostics']();
+}
+
+export function GetTLSConfig() {
+  return window['go']['main']['App']['GetTLSConfig']();
+}
+
 export function GetWorkflowConfig() {
   return window['go']['main']['App']['GetWorkflowConfig']();
 }
@@ -78,18 +202,106 @@ export function GetWorkflowUIStatus() {
   return window['go']['main']['App']['GetWorkflowUIStatus']();
 }
 
+export function ImportConfig(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportConfig'](arg1, arg2, arg3);
+}
+
+export function ImportWorkflows(arg1, arg2) {
+  return window['go']['main']['App']['ImportWorkflows'](arg1, arg2);
+}
+
+export function InstallComponent(arg1, arg2) {
+  return window['go']['main']['App']['InstallComponent'](arg1, arg2);
+}
+
+export function ListConfigHistory() {
+  return window['go']['main']['App']['ListConfigHistory']();
+}
+
+export function ListConfigProfiles() {
+  return window['go']['main']['App']['ListConfigProfiles']();
+}
+
+export function LockSecrets() {
+  return window['go']['main']['App']['LockSecrets']();
+}
+
+export function PinComponent(arg1, arg2) {
+  return window['go']['main']['App']['PinComponent'](arg1, arg2);
+}
+
+export function PreviewConfigImport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewConfigImport'](arg1, arg2, arg3);
+}
+
+export function PreviewWorkflowImport(arg1, arg2) {
+  return window['go']['main']['App']['PreviewWorkflowImport'](arg1, arg2);
+}
+
+export function QueryEventJournal(arg1) {
+  return window['go']['main']['App']['QueryEventJournal'](arg1);
+}
+
+export function RegenerateCACertificate() {
+  return window['go']['main']['App']['RegenerateCACertificate']();
+}
+
+export function RegenerateLANToken() {
+  return window['go']['main']['App']['RegenerateLANToken']();
+}
+
 export function RegisterProcess(arg1, arg2) {
   return window['go']['main']['App']['RegisterProcess'](arg1, arg2);
 }
 
+export function RenameConfigProfile(arg1, arg2) {
+  return window['go']['main']['App']['RenameConfigProfile'](arg1, arg2);
+}
+
+export function RenameWorkflowKey(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RenameWorkflowKey'](arg1, arg2, arg3);
+}
+
 export function ResetConfigToDefault() {
   return window['go']['main']['App']['ResetConfigToDefault']();
 }
 
+export function RestartService(arg1) {
+  return window['go']['main']['App']['RestartService'](arg1);
+}
+
+export function RestartServiceBlueGreen(arg1) {
+  return window['go']['main']['App']['RestartServiceBlueGreen'](arg1);
+}
+
+export function RestoreConfigVersion(arg1) {
+  return window['go']['main']['App']['RestoreConfigVersion'](arg1);
+}
+
+export function RevokeLANSessions() {
+  return window['go']['main']['App']['RevokeLANSessions']();
+}
+
+export function RollbackComponent(arg1) {
+  return window['go']['main']['App']['RollbackComponent'](arg1);
+}
+
+export function RunWorkflow(arg1, arg2) {
+  return window['go']['main']['App']['RunWorkflow'](arg1, arg2);
+}
+
+export function SetSecretsPassphrase(arg1) {
+  return window['go']['main']['App']['SetSecretsPassphrase'](arg1);
+}
+
 export function StartEduTools(arg1) {
   return window['go']['main']['App']['StartEduTools'](arg1);
 }
 
+export function StartGateway() {
+  return window['go']['main']['App']['StartGateway']();
+}
+
 export function StartGinServer(arg1) {
   return window['go']['main']['App']['StartGinServer'](arg1);
 }
@@ -98,6 +310,10 @@ export function StartProcess(arg1, arg2) {
   return window['go']['main']['App']['StartProcess'](arg1, arg2);
 }
 
+export function StartProfile(arg1) {
+  return window['go']['main']['App']['StartProfile'](arg1);
+}
+
 export function StartWorkflowUI(arg1) {
   return window['go']['main']['App']['StartWorkflowUI'](arg1);
 }
@@ -106,6 +322,10 @@ export function StopEduTools() {
   return window['go']['main']['App']['StopEduTools']();
 }
 
+export function StopGateway() {
+  return window['go']['main']['App']['StopGateway']();
+}
+
 export function StopGinServer() {
   return window['go']['main']['App']['StopGinServer']();
 }
@@ -114,22 +334,82 @@ export function StopProcess(arg1) {
   return window['go']['main']['App']['StopProcess'](arg1);
 }
 
+export function StopProfile(arg1) {
+  return window['go']['main']['App']['StopProfile'](arg1);
+}
+
 export function StopWorkflowUI() {
   return window['go']['main']['App']['StopWorkflowUI']();
 }
 
+export function TrustComponent(arg1) {
+  return window['go']['main']['App']['TrustComponent'](arg1);
+}
+
+export function UnlockSecrets(arg1) {
+  return window['go']['main']['App']['UnlockSecrets'](arg1);
+}
+
+export function UnpinComponent(arg1) {
+  return window['go']['main']['App']['UnpinComponent'](arg1);
+}
+
+export function UntrustComponent(arg1) {
+  return window['go']['main']['App']['UntrustComponent'](arg1);
+}
+
+export function UpdateBinariesConfig(arg1) {
+  return window['go']['main']['App']['UpdateBinariesConfig'](arg1);
+}
+
+export function UpdateComponents() {
+  return window['go']['main']['App']['UpdateComponents']();
+}
+
 export function UpdateEduExpConfig(arg1) {
   return window['go']['main']['App']['UpdateEduExpConfig'](arg1);
 }
 
+export function UpdateGatewayConfig(arg1) {
+  return window['go']['main']['App']['UpdateGatewayConfig'](arg1);
+}
+
 export function UpdateGlobalConfig(arg1) {
   return window['go']['main']['App']['UpdateGlobalConfig'](arg1);
 }
 
+export function UpdateLANConfig(arg1) {
+  return window['go']['main']['App']['UpdateLANConfig'](arg1);
+}
+
 export function UpdateLicenseConfig(arg1) {
   return window['go']['main']['App']['UpdateLicenseConfig'](arg1);
 }
 
+export function UpdateServicesConfig(arg1) {
+  return window['go']['main']['App']['UpdateServicesConfig'](arg1);
+}
+
+export function UpdateTLSConfig(arg1) {
+  return window['go']['main']['App']['UpdateTLSConfig'](arg1);
+}
+
+export function UpdateWorkflow(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateWorkflow'](arg1, arg2, arg3);
+}
+
 export function UpdateWorkflowConfig(arg1) {
   return window['go']['main']['App']['UpdateWorkflowConfig'](arg1);
 }
+
+export function ValidateConfig(arg1) {
+  return window['go']['main']['App']['ValidateConfig'](arg1);
+}
+
+export function ValidateWorkflowInput(arg1, arg2) {
+  return window['go']['main']['App']['ValidateWorkflowInput'](arg1, arg2);
+}
+
+export function VerifyInstalledComponents() {
+  return window['go']['main']['App']['VerifyInstalledComponents']();
+}
@@ -1,5 +1,253 @@
 export namespace main {
 	
+	export class ArchivedVersion {
+	    Version: string;
+	    Path: string;
+	    SHA256: string;
+	    // Go type: time
+	    ArchivedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchivedVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Version = source["Version"];
+	        this.Path = source["Path"];
+	        this.SHA256 = source["SHA256"];
+	        this.ArchivedAt = this.convertValues(source["ArchivedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BinariesConfig {
+	    ManifestURL: string;
+	    SignaturePolicy: string;
+	    KeepVersions: number;
+	    AutoUpdate: boolean;
+	    Pinned: Record<string, string>;
+	    Trusted: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinariesConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ManifestURL = source["ManifestURL"];
+	        this.SignaturePolicy = source["SignaturePolicy"];
+	        this.KeepVersions = source["KeepVersions"];
+	        this.AutoUpdate = source["AutoUpdate"];
+	        this.Pinned = source["Pinned"];
+	        this.Trusted = source["Trusted"];
+	    }
+	}
+	export class BinaryVerification {
+	    Name: string;
+	    Path: string;
+	    Status: string;
+	    Version: string;
+	    Message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinaryVerification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Path = source["Path"];
+	        this.Status = source["Status"];
+	        this.Version = source["Version"];
+	        this.Message = source["Message"];
+	    }
+	}
+	export class CAInfo {
+	    Subject: string;
+	    Fingerprint: string;
+	    // Go type: time
+	    NotAfter: any;
+	    Path: string;
+	    Constrained: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CAInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Subject = source["Subject"];
+	        this.Fingerprint = source["Fingerprint"];
+	        this.NotAfter = this.convertValues(source["NotAfter"], null);
+	        this.Path = source["Path"];
+	        this.Constrained = source["Constrained"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TLSConfig {
+	    Enabled: boolean;
+	    Hosts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TLSConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Enabled = source["Enabled"];
+	        this.Hosts = source["Hosts"];
+	    }
+	}
+	export class LANConfig {
+	    Enabled: boolean;
+	    BindAddress: string;
+	    AuthMode: string;
+	    Token: string;
+	    Password: string;
+	    SessionTTL: number;
+	    MaxClients: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LANConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Enabled = source["Enabled"];
+	        this.BindAddress = source["BindAddress"];
+	        this.AuthMode = source["AuthMode"];
+	        this.Token = source["Token"];
+	        this.Password = source["Password"];
+	        this.SessionTTL = source["SessionTTL"];
+	        this.MaxClients = source["MaxClients"];
+	    }
+	}
+	export class GatewayConfig {
+	    Enabled: boolean;
+	    Port: string;
+	    Routing: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GatewayConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Enabled = source["Enabled"];
+	        this.Port = source["Port"];
+	        this.Routing = source["Routing"];
+	    }
+	}
+	export class FileServerConfig {
+	    Port: string;
+	    Root: string;
+	    Browse: boolean;
+	    Gzip: boolean;
+	    AccessLog: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileServerConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Port = source["Port"];
+	        this.Root = source["Root"];
+	        this.Browse = source["Browse"];
+	        this.Gzip = source["Gzip"];
+	        this.AccessLog = source["AccessLog"];
+	    }
+	}
+	export class ServiceProfile {
+	    Name: string;
+	    Services: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Services = source["Services"];
+	    }
+	}
+	export class ServicesConfig {
+	    DefaultProfile: string;
+	    Profiles: Record<string, ServiceProfile>;
+	    RestartMode: string;
+	    ReadyTimeout: number;
+	    DrainTimeout: number;
+	    HealthPaths: Record<string, string>;
+	    BindAddresses: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServicesConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.DefaultProfile = source["DefaultProfile"];
+	        this.Profiles = this.convertValues(source["Profiles"], ServiceProfile, true);
+	        this.RestartMode = source["RestartMode"];
+	        this.ReadyTimeout = source["ReadyTimeout"];
+	        this.DrainTimeout = source["DrainTimeout"];
+	        this.HealthPaths = source["HealthPaths"];
+	        this.BindAddresses = source["BindAddresses"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class LicenseConfig {
 	    LicenseKey: string;
 	    ExpiryDate: string;
@@ -18,6 +266,20 @@ export namespace main {
 	        this.FeatureFlags = source["FeatureFlags"];
 	    }
 	}
+	export class WorkflowCondition {
+	    Key: string;
+	    Values: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowCondition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Key = source["Key"];
+	        this.Values = source["Values"];
+	    }
+	}
 	export class WorkflowParameter {
 	    Key: string;
 	    Label: string;
@@ -25,6 +287,13 @@ export namespace main {
 	    Required: boolean;
 	    Options: string[];
 	    DefaultValue: string;
+	    HelpText: string;
+	    Min?: number;
+	    Max?: number;
+	    MinLength: number;
+	    MaxLength: number;
+	    Pattern: string;
+	    VisibleWhen: WorkflowCondition[];
 	
 	    static createFrom(source: any = {}) {
 	        return new WorkflowParameter(source);
@@ -38,13 +307,39 @@ export namespace main {
 	        this.Required = source["Required"];
 	        this.Options = source["Options"];
 	        this.DefaultValue = source["DefaultValue"];
+	        this.HelpText = source["HelpText"];
+	        this.Min = source["Min"];
+	        this.Max = source["Max"];
+	        this.MinLength = source["MinLength"];
+	        this.MaxLength = source["MaxLength"];
+	        this.Pattern = source["Pattern"];
+	        this.VisibleWhen = this.convertValues(source["VisibleWhen"], WorkflowCondition);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class WorkflowDef {
 	    Name: string;
 	    WorkflowID: string;
 	    AppID: string;
 	    Parameters: WorkflowParameter[];
+	    Revision: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new WorkflowDef(source);
@@ -56,6 +351,7 @@ export namespace main {
 	        this.WorkflowID = source["WorkflowID"];
 	        this.AppID = source["AppID"];
 	        this.Parameters = this.convertValues(source["Parameters"], WorkflowParameter);
+	        this.Revision = source["Revision"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -132,6 +428,7 @@ export namespace main {
 	}
 	export class GlobalConfig {
 	    Theme: string;
+	    HistoryLimit: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new GlobalConfig(source);
@@ -140,13 +437,21 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.Theme = source["Theme"];
+	        this.HistoryLimit = source["HistoryLimit"];
 	    }
 	}
 	export class Config {
+	    SchemaVersion: number;
 	    Global: GlobalConfig;
 	    EduExp: EduExpConfig;
 	    Workflow: WorkflowConfig;
 	    License: LicenseConfig;
+	    Services: ServicesConfig;
+	    FileServer: FileServerConfig;
+	    Binaries: BinariesConfig;
+	    Gateway: GatewayConfig;
+	    LAN: LANConfig;
+	    TLS: TLSConfig;
 	
 	    static createFrom(source: any = {}) {
 	        return new Config(source);
@@ -154,10 +459,17 @@ export namespace main {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.SchemaVersion = source["SchemaVersion"];
 	        this.Global = this.convertValues(source["Global"], GlobalConfig);
 	        this.EduExp = this.convertValues(source["EduExp"], EduExpConfig);
 	        this.Workflow = this.convertValues(source["Workflow"], WorkflowConfig);
 	        this.License = this.convertValues(source["License"], LicenseConfig);
+	        this.Services = this.convertValues(source["Services"], ServicesConfig);
+	        this.FileServer = this.convertValues(source["FileServer"], FileServerConfig);
+	        this.Binaries = this.convertValues(source["Binaries"], BinariesConfig);
+	        this.Gateway = this.convertValues(source["Gateway"], GatewayConfig);
+	        this.LAN = this.convertValues(source["LAN"], LANConfig);
+	        this.TLS = this.convertValues(source["TLS"], TLSConfig);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -178,29 +490,928 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class ConfigExportOptions {
+	    Format: string;
+	    Sections: string[];
+	    Secrets: string;
+	    Passphrase: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigExportOptions(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Format = source["Format"];
+	        this.Sections = source["Sections"];
+	        this.Secrets = source["Secrets"];
+	        this.Passphrase = source["Passphrase"];
+	    }
+	}
+	export class ConfigFieldChange {
+	    Path: string;
+	    Action: string;
+	    Old: string;
+	    New: string;
 	
+	    static createFrom(source: any = {}) {
+	        return new ConfigFieldChange(source);
+	    }
 	
-	export class ProcessConfig {
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Action = source["Action"];
+	        this.Old = source["Old"];
+	        this.New = source["New"];
+	    }
+	}
+	export class ValidationError {
+	    Field: string;
+	    Code: string;
+	    Message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Field = source["Field"];
+	        this.Code = source["Code"];
+	        this.Message = source["Message"];
+	    }
+	}
+	export class ConfigImportPreview {
+	    Mode: string;
+	    Sections: string[];
+	    Changes: ConfigFieldChange[];
+	    Errors: ValidationError[];
+	    Redacted: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Mode = source["Mode"];
+	        this.Sections = source["Sections"];
+	        this.Changes = this.convertValues(source["Changes"], ConfigFieldChange);
+	        this.Errors = this.convertValues(source["Errors"], ValidationError);
+	        this.Redacted = source["Redacted"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigProfile {
 	    Name: string;
-	    Command: string;
-	    Args: string[];
-	    WorkDir: string;
+	    Active: boolean;
+	    // Go type: time
+	    UpdatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new ProcessConfig(source);
+	        return new ConfigProfile(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.Name = source["Name"];
-	        this.Command = source["Command"];
-	        this.Args = source["Args"];
-	        this.WorkDir = source["WorkDir"];
+	        this.Active = source["Active"];
+	        this.UpdatedAt = this.convertValues(source["UpdatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigSectionLoss {
+	    Section: string;
+	    Error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigSectionLoss(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Section = source["Section"];
+	        this.Error = source["Error"];
 	    }
 	}
+	export class ConfigRecovery {
+	    // Go type: time
+	    RecoveredAt: any;
+	    BackupFile: string;
+	    Error: string;
+	    Recovered: string[];
+	    Lost: ConfigSectionLoss[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigRecovery(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.RecoveredAt = this.convertValues(source["RecoveredAt"], null);
+	        this.BackupFile = source["BackupFile"];
+	        this.Error = source["Error"];
+	        this.Recovered = source["Recovered"];
+	        this.Lost = this.convertValues(source["Lost"], ConfigSectionLoss);
+	    }
 	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ConfigValueSource {
+	    Path: string;
+	    Value: string;
+	    Source: string;
+	    Origin: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigValueSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Value = source["Value"];
+	        this.Source = source["Source"];
+	        this.Origin = source["Origin"];
+	    }
+	}
+	export class ConfigVersion {
+	    ID: string;
+	    // Go type: time
+	    SavedAt: any;
+	    Sections: string[];
+	    Current: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ID = source["ID"];
+	        this.SavedAt = this.convertValues(source["SavedAt"], null);
+	        this.Sections = source["Sections"];
+	        this.Current = source["Current"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class EventQuery {
+	    Service: string;
+	    RunID: string;
+	    Actor: string;
+	    Types: string[];
+	    Since: string;
+	    Until: string;
+	    Offset: number;
+	    Limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EventQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Service = source["Service"];
+	        this.RunID = source["RunID"];
+	        this.Actor = source["Actor"];
+	        this.Types = source["Types"];
+	        this.Since = source["Since"];
+	        this.Until = source["Until"];
+	        this.Offset = source["Offset"];
+	        this.Limit = source["Limit"];
+	    }
+	}
+	export class JournalEvent {
+	    // Go type: time
+	    timestamp: any;
+	    type: string;
+	    service?: string;
+	    run_id?: string;
+	    actor: string;
+	    reason?: string;
+	    details?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JournalEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.type = source["type"];
+	        this.service = source["service"];
+	        this.run_id = source["run_id"];
+	        this.actor = source["actor"];
+	        this.reason = source["reason"];
+	        this.details = source["details"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EventQueryResult {
+	    Events: JournalEvent[];
+	    Total: number;
+	    Offset: number;
+	    Limit: number;
+	    Error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EventQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Events = this.convertValues(source["Events"], JournalEvent);
+	        this.Total = source["Total"];
+	        this.Offset = source["Offset"];
+	        this.Limit = source["Limit"];
+	        this.Error = source["Error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class GatewayRouteInfo {
+	    Prefix: string;
+	    Service: string;
+	    URL: string;
+	    Target: string;
+	    Running: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GatewayRouteInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Prefix = source["Prefix"];
+	        this.Service = source["Service"];
+	        this.URL = source["URL"];
+	        this.Target = source["Target"];
+	        this.Running = source["Running"];
+	    }
+	}
+	export class GatewayInfo {
+	    Enabled: boolean;
+	    Running: boolean;
+	    BaseURL: string;
+	    Routing: string;
+	    Routes: GatewayRouteInfo[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GatewayInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Enabled = source["Enabled"];
+	        this.Running = source["Running"];
+	        this.BaseURL = source["BaseURL"];
+	        this.Routing = source["Routing"];
+	        this.Routes = this.convertValues(source["Routes"], GatewayRouteInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class InstalledComponent {
+	    Name: string;
+	    Installed: boolean;
+	    Path: string;
+	    Version: string;
+	    Source: string;
+	    SHA256: string;
+	    MinVersion: string;
+	    Compatible: boolean;
+	    Trusted: boolean;
+	    Message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstalledComponent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Installed = source["Installed"];
+	        this.Path = source["Path"];
+	        this.Version = source["Version"];
+	        this.Source = source["Source"];
+	        this.SHA256 = source["SHA256"];
+	        this.MinVersion = source["MinVersion"];
+	        this.Compatible = source["Compatible"];
+	        this.Trusted = source["Trusted"];
+	        this.Message = source["Message"];
+	    }
+	}
+	
+	export class LANURL {
+	    Address: string;
+	    Service: string;
+	    URL: string;
+	    QRCode: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LANURL(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Address = source["Address"];
+	        this.Service = source["Service"];
+	        this.URL = source["URL"];
+	        this.QRCode = source["QRCode"];
+	    }
+	}
+	export class LANAccessInfo {
+	    Enabled: boolean;
+	    Running: boolean;
+	    AuthMode: string;
+	    URLs: LANURL[];
+	    ActiveSessions: number;
+	    UserLimit: number;
+	    Message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LANAccessInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Enabled = source["Enabled"];
+	        this.Running = source["Running"];
+	        this.AuthMode = source["AuthMode"];
+	        this.URLs = this.convertValues(source["URLs"], LANURL);
+	        this.ActiveSessions = source["ActiveSessions"];
+	        this.UserLimit = source["UserLimit"];
+	        this.Message = source["Message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class ManifestAsset {
+	    url: string;
+	    sha256: string;
+	    size?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ManifestAsset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.sha256 = source["sha256"];
+	        this.size = source["size"];
+	    }
+	}
+	export class ManifestRelease {
+	    version: string;
+	    platforms: Record<string, ManifestAsset>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ManifestRelease(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.platforms = this.convertValues(source["platforms"], ManifestAsset, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ManifestComponent {
+	    latest: string;
+	    releases: ManifestRelease[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ManifestComponent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.latest = source["latest"];
+	        this.releases = this.convertValues(source["releases"], ManifestRelease);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ProcessConfig {
+	    Name: string;
+	    Command: string;
+	    Args: string[];
+	    WorkDir: string;
+	    DependsOn: string[];
+	    Generated: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Command = source["Command"];
+	        this.Args = source["Args"];
+	        this.WorkDir = source["WorkDir"];
+	        this.DependsOn = source["DependsOn"];
+	        this.Generated = source["Generated"];
+	    }
+	}
+	export class ServiceResult {
+	    Service: string;
+	    Success: boolean;
+	    Skipped: boolean;
+	    IsDependency: boolean;
+	    Message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Service = source["Service"];
+	        this.Success = source["Success"];
+	        this.Skipped = source["Skipped"];
+	        this.IsDependency = source["IsDependency"];
+	        this.Message = source["Message"];
+	    }
+	}
+	export class ProfileResult {
+	    Profile: string;
+	    Success: boolean;
+	    Message: string;
+	    Results: ServiceResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Profile = source["Profile"];
+	        this.Success = source["Success"];
+	        this.Message = source["Message"];
+	        this.Results = this.convertValues(source["Results"], ServiceResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReleaseManifest {
+	    components: Record<string, ManifestComponent>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReleaseManifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.components = this.convertValues(source["components"], ManifestComponent, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SecretsStatus {
+	    Protection: string;
+	    Locked: boolean;
+	    Count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecretsStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Protection = source["Protection"];
+	        this.Locked = source["Locked"];
+	        this.Count = source["Count"];
+	    }
+	}
+	
+	
+	
+	export class StartupDiagnostics {
+	    ConfigFile: string;
+	    ConfigRecovery?: ConfigRecovery;
+	    ConfigProblems: ValidationError[];
+	    Errors: string[];
+	    Unrecorded: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupDiagnostics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ConfigFile = source["ConfigFile"];
+	        this.ConfigRecovery = this.convertValues(source["ConfigRecovery"], ConfigRecovery);
+	        this.ConfigProblems = this.convertValues(source["ConfigProblems"], ValidationError);
+	        this.Errors = source["Errors"];
+	        this.Unrecorded = source["Unrecorded"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	
+	export class WorkflowImportItem {
+	    SourceKey: string;
+	    Key: string;
+	    Name: string;
+	    Parameters: number;
+	    Conflict: boolean;
+	    Action: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowImportItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.SourceKey = source["SourceKey"];
+	        this.Key = source["Key"];
+	        this.Name = source["Name"];
+	        this.Parameters = source["Parameters"];
+	        this.Conflict = source["Conflict"];
+	        this.Action = source["Action"];
+	    }
+	}
+	export class WorkflowImportPreview {
+	    Source: string;
+	    Conflict: string;
+	    Workflows: WorkflowImportItem[];
+	    Errors: ValidationError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Source = source["Source"];
+	        this.Conflict = source["Conflict"];
+	        this.Workflows = this.convertValues(source["Workflows"], WorkflowImportItem);
+	        this.Errors = this.convertValues(source["Errors"], ValidationError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkflowInputResult {
+	    Valid: boolean;
+	    Values: Record<string, any>;
+	    Errors: ValidationError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowInputResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Valid = source["Valid"];
+	        this.Values = source["Values"];
+	        this.Errors = this.convertValues(source["Errors"], ValidationError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class WorkflowResult {
+	    Success: boolean;
+	    Key: string;
+	    Revision: number;
+	    Conflict: boolean;
+	    Current: number;
+	    Message: string;
+	    Errors: ValidationError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Success = source["Success"];
+	        this.Key = source["Key"];
+	        this.Revision = source["Revision"];
+	        this.Conflict = source["Conflict"];
+	        this.Current = source["Current"];
+	        this.Message = source["Message"];
+	        this.Errors = this.convertValues(source["Errors"], ValidationError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkflowRunResult {
+	    Success: boolean;
+	    Output: string;
+	    DebugURL: string;
+	    Message: string;
+	    Errors: ValidationError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowRunResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Success = source["Success"];
+	        this.Output = source["Output"];
+	        this.DebugURL = source["DebugURL"];
+	        this.Message = source["Message"];
+	        this.Errors = this.convertValues(source["Errors"], ValidationError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
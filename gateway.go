@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 网关路由方式
+const (
+	GatewayRoutingPath      = "path"      // 按路径前缀路由，如 /workflow/
+	GatewayRoutingSubdomain = "subdomain" // 按子域名路由，如 workflow.localhost
+)
+
+// GatewayRoute 网关路由规则
+type GatewayRoute struct {
+	Prefix  string // 路径前缀或子域名
+	Service string // 转发到的服务（进程名称）
+}
+
+// gatewayRoutes 网关的路由表，路径前缀和子域名使用相同的名称
+var gatewayRoutes = []GatewayRoute{
+	{Prefix: "workflow", Service: "workflowui"},
+	{Prefix: "edu", Service: "edu-tools"},
+	{Prefix: "files", Service: "fileserver"},
+}
+
+// Gateway 本地单端口网关，将请求反向代理到各服务实际监听的端口
+// 作为进程内服务注册到进程管理器，支持 WebSocket 和 SSE 透传
+type Gateway struct {
+	mu      sync.RWMutex
+	targets map[string]*url.URL // 服务名称 -> 当前转发地址
+	active  map[string]int      // 转发地址 -> 进行中的请求数，用于切换后等待旧实例排空
+	server  *http.Server        // 当前运行的 HTTP 服务，未运行时为 nil
+	output  io.Writer           // 服务日志输出
+	caCert  string              // 供局域网设备下载的根证书文件，为空时不提供
+	auth    *gatewayAuthenticator
+}
+
+// NewGateway 创建网关
+func NewGateway() *Gateway {
+	return &Gateway{
+		targets: make(map[string]*url.URL),
+		active:  make(map[string]int),
+		output:  io.Discard,
+		auth:    newGatewayAuthenticator(),
+	}
+}
+
+// SetAuth 更新局域网访问的认证设置，立即生效
+func (g *Gateway) SetAuth(auth GatewayAuth) {
+	g.auth.setAuth(auth)
+}
+
+// ActiveSessions 获取当前通过认证的局域网会话数
+func (g *Gateway) ActiveSessions() int {
+	return g.auth.activeSessions()
+}
+
+// RevokeSessions 注销所有局域网会话
+func (g *Gateway) RevokeSessions() {
+	g.auth.revokeAll()
+}
+
+// SetTarget 设置服务的转发地址，服务在新端口启动后调用
+func (g *Gateway) SetTarget(service, address string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.targets[service] = &url.URL{Scheme: "http", Host: address}
+}
+
+// Target 获取服务当前的转发地址，未设置时返回空字符串
+func (g *Gateway) Target(service string) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if target, exists := g.targets[service]; exists {
+		return target.Host
+	}
+	return ""
+}
+
+// ActiveRequests 获取转发到指定地址的进行中请求数
+func (g *Gateway) ActiveRequests(address string) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.active[address]
+}
+
+// Drain 等待转发到指定地址的请求全部完成，超时返回 false
+// 路由切换到新地址后调用，WebSocket 等长连接会一直占用直到超时
+func (g *Gateway) Drain(address string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for g.ActiveRequests(address) > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
+
+// Start 启动网关，端口被占用等错误会立即返回
+func (g *Gateway) Start(args []string, output io.Writer) (<-chan error, error) {
+	var listen, tlsCert, tlsKey, caCert string
+	flags := flag.NewFlagSet("gateway", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&listen, "listen", "127.0.0.1:8000", "listen address")
+	flags.StringVar(&tlsCert, "tls-cert", "", "TLS certificate file")
+	flags.StringVar(&tlsKey, "tls-key", "", "TLS key file")
+	flags.StringVar(&caCert, "ca-cert", "", "CA certificate offered for download")
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("invalid gateway arguments: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCert != "" {
+		if listener, err = tlsListenerOrClose(listener, tlsCert, tlsKey); err != nil {
+			return nil, err
+		}
+	}
+
+	server := &http.Server{
+		Handler:           g,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	g.mu.Lock()
+	g.server = server
+	g.output = output
+	g.caCert = caCert
+	g.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(output, "Gateway listening on %s\n", listener.Addr())
+		err := server.Serve(listener)
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+		done <- err
+	}()
+	return done, nil
+}
+
+// Stop 停止网关，等待进行中的请求完成直到 ctx 超时
+func (g *Gateway) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	server := g.server
+	g.server = nil
+	g.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		// 超时后强制关闭剩余连接（包括 WebSocket 等长连接）
+		server.Close()
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP 按子域名或路径前缀将请求转发到对应的服务
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 根证书是公开信息，学生设备安装证书前无需认证即可下载
+	if r.URL.Path == gatewayCACertPath {
+		g.serveCACert(w, r)
+		return
+	}
+	if g.auth.servesLoginPage(r) {
+		writeLoginPage(w, r.URL.Query().Get("next"), "", http.StatusOK)
+		return
+	}
+	if !g.auth.authorize(w, r) {
+		return
+	}
+
+	route, prefix, ok := matchGatewayRoute(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// /workflow 重定向到 /workflow/，保证服务页面中的相对路径正确
+	if prefix != "" && r.URL.Path == prefix {
+		target := prefix + "/"
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	}
+
+	g.mu.Lock()
+	target := g.targets[route.Service]
+	output := g.output
+	if target != nil {
+		g.active[target.Host]++
+	}
+	g.mu.Unlock()
+
+	if target == nil {
+		http.Error(w, fmt.Sprintf("Service '%s' is not running", route.Service), http.StatusBadGateway)
+		return
+	}
+	defer func() {
+		g.mu.Lock()
+		if g.active[target.Host]--; g.active[target.Host] <= 0 {
+			delete(g.active, target.Host)
+		}
+		g.mu.Unlock()
+	}()
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			if prefix != "" {
+				pr.Out.URL.Path = strings.TrimPrefix(pr.Out.URL.Path, prefix)
+				pr.Out.URL.RawPath = ""
+				pr.Out.Header.Set("X-Forwarded-Prefix", prefix)
+			}
+		},
+		// 立即转发每次写入，保证 SSE 事件不被缓冲
+		FlushInterval: -1,
+		ModifyResponse: func(resp *http.Response) error {
+			// 服务返回的站内重定向需要加上路径前缀
+			if location := resp.Header.Get("Location"); prefix != "" && strings.HasPrefix(location, "/") &&
+				!strings.HasPrefix(location, "//") && !strings.HasPrefix(location, prefix+"/") {
+				resp.Header.Set("Location", prefix+location)
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			fmt.Fprintf(output, "Proxy error for service '%s' (%s): %v\n", route.Service, target.Host, err)
+			http.Error(w, fmt.Sprintf("Service '%s' is unavailable", route.Service), http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// serveCACert 提供根证书下载
+func (g *Gateway) serveCACert(w http.ResponseWriter, r *http.Request) {
+	g.mu.RLock()
+	caCert := g.caCert
+	g.mu.RUnlock()
+
+	if caCert == "" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-x509-ca-cert")
+	w.Header().Set("Content-Disposition", `attachment; filename="eduexp-ca.crt"`)
+	http.ServeFile(w, r, caCert)
+}
+
+// matchGatewayRoute 匹配请求对应的路由
+// 子域名匹配时返回的前缀为空，路径匹配时返回需要去掉的路径前缀
+func matchGatewayRoute(r *http.Request) (GatewayRoute, string, bool) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if label, rest, found := strings.Cut(host, "."); found && rest != "" {
+		for _, route := range gatewayRoutes {
+			if strings.EqualFold(label, route.Prefix) {
+				return route, "", true
+			}
+		}
+	}
+
+	for _, route := range gatewayRoutes {
+		prefix := "/" + route.Prefix
+		if r.URL.Path == prefix || strings.HasPrefix(r.URL.Path, prefix+"/") {
+			return route, prefix, true
+		}
+	}
+	return GatewayRoute{}, "", false
+}
+
+// listenPort 从服务启动参数中解析监听端口，取最后一个 --port 或 --listen 参数
+func listenPort(args []string) string {
+	port := ""
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name != "--port" && name != "--listen" {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				break
+			}
+			i++
+			value = args[i]
+		}
+		if name == "--listen" {
+			if _, p, err := net.SplitHostPort(value); err == nil {
+				value = p
+			}
+		}
+		port = value
+	}
+	return port
+}
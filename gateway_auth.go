@@ -0,0 +1,307 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 局域网访问认证方式
+const (
+	LANAuthToken    = "token"    // 访问地址中携带令牌，扫码即可访问
+	LANAuthPassword = "password" // 首次访问时输入密码
+)
+
+// 网关认证使用的路径和名称
+const (
+	gatewaySessionCookie = "eduexp_session"
+	gatewayLoginPath     = "/_gateway/login"
+	gatewayLogoutPath    = "/_gateway/logout"
+	gatewayCACertPath    = "/_gateway/ca.crt"
+	gatewayTokenParam    = "token"
+)
+
+// GatewayAuth 网关对局域网访问的认证设置
+type GatewayAuth struct {
+	Mode       string        // 认证方式，为空时不认证
+	Token      string        // 访问令牌
+	Password   string        // 访问密码
+	UserLimit  int           // 同时在线的设备数上限，按客户端地址计算，0 表示不限制
+	SessionTTL time.Duration // 会话空闲超时
+}
+
+// gatewaySession 通过认证的局域网会话
+type gatewaySession struct {
+	client   string    // 客户端 IP，同一设备的多个会话只占用一个名额
+	created  time.Time // 创建时间
+	lastSeen time.Time // 最近一次请求时间
+}
+
+// gatewayAuthenticator 网关认证和会话管理
+// 本机回环地址的请求不需要认证，也不占用会话名额
+type gatewayAuthenticator struct {
+	mu       sync.Mutex
+	auth     GatewayAuth
+	sessions map[string]*gatewaySession // 会话ID -> 会话
+}
+
+// newGatewayAuthenticator 创建认证器
+func newGatewayAuthenticator() *gatewayAuthenticator {
+	return &gatewayAuthenticator{sessions: make(map[string]*gatewaySession)}
+}
+
+// setAuth 更新认证设置，令牌或密码变化时清除已有会话
+func (ga *gatewayAuthenticator) setAuth(auth GatewayAuth) {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+
+	if auth.Mode != ga.auth.Mode || auth.Token != ga.auth.Token || auth.Password != ga.auth.Password {
+		ga.sessions = make(map[string]*gatewaySession)
+	}
+	ga.auth = auth
+}
+
+// activeSessions 清理过期会话并返回当前在线的设备数
+func (ga *gatewayAuthenticator) activeSessions() int {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	ga.expireLocked(time.Now())
+	return len(ga.clientsLocked())
+}
+
+// clientsLocked 返回有会话的客户端，调用方需持有 ga.mu
+func (ga *gatewayAuthenticator) clientsLocked() map[string]bool {
+	clients := make(map[string]bool)
+	for _, session := range ga.sessions {
+		clients[session.client] = true
+	}
+	return clients
+}
+
+// revokeAll 注销所有会话
+func (ga *gatewayAuthenticator) revokeAll() {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	ga.sessions = make(map[string]*gatewaySession)
+}
+
+// expireLocked 删除空闲超时的会话，调用方需持有 ga.mu
+func (ga *gatewayAuthenticator) expireLocked(now time.Time) {
+	if ga.auth.SessionTTL <= 0 {
+		return
+	}
+	for id, session := range ga.sessions {
+		if now.Sub(session.lastSeen) > ga.auth.SessionTTL {
+			delete(ga.sessions, id)
+		}
+	}
+}
+
+// authorize 检查请求是否已认证，未认证时写入响应并返回 false
+func (ga *gatewayAuthenticator) authorize(w http.ResponseWriter, r *http.Request) bool {
+	ga.mu.Lock()
+	auth := ga.auth
+	ga.mu.Unlock()
+
+	if auth.Mode == "" || isLoopbackRequest(r) {
+		return true
+	}
+	if r.URL.Path == gatewayLogoutPath {
+		ga.handleLogout(w, r, auth)
+		return false
+	}
+
+	// 已有会话
+	if cookie, err := r.Cookie(gatewaySessionCookie); err == nil && ga.touch(cookie.Value) {
+		return true
+	}
+
+	switch auth.Mode {
+	case LANAuthToken:
+		token := r.URL.Query().Get(gatewayTokenParam)
+		if token == "" {
+			token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if token == "" || !secretEqual(token, auth.Token) {
+			http.Error(w, "Access token required: please use the link or QR code provided by the teacher", http.StatusUnauthorized)
+			return false
+		}
+		if !ga.startSession(w, r) {
+			return false
+		}
+
+		// 去掉地址中的令牌，避免令牌留在浏览器地址栏和历史记录中
+		if r.Method == http.MethodGet && r.URL.Query().Has(gatewayTokenParam) {
+			query := r.URL.Query()
+			query.Del(gatewayTokenParam)
+			target := *r.URL
+			target.RawQuery = query.Encode()
+			http.Redirect(w, r, target.RequestURI(), http.StatusFound)
+			return false
+		}
+		return true
+
+	case LANAuthPassword:
+		if r.URL.Path == gatewayLoginPath && r.Method == http.MethodPost {
+			ga.handleLogin(w, r, auth)
+			return false
+		}
+		if r.Method == http.MethodGet {
+			http.Redirect(w, r, gatewayLoginPath+"?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusFound)
+			return false
+		}
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return false
+	}
+
+	http.Error(w, "Unsupported authentication mode", http.StatusForbidden)
+	return false
+}
+
+// servesLoginPage 判断请求是否为密码登录页面
+func (ga *gatewayAuthenticator) servesLoginPage(r *http.Request) bool {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	return ga.auth.Mode == LANAuthPassword && r.URL.Path == gatewayLoginPath && r.Method == http.MethodGet
+}
+
+// handleLogin 校验密码并创建会话
+func (ga *gatewayAuthenticator) handleLogin(w http.ResponseWriter, r *http.Request, auth GatewayAuth) {
+	next := r.FormValue("next")
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") {
+		next = "/"
+	}
+	if !secretEqual(r.FormValue("password"), auth.Password) {
+		writeLoginPage(w, next, "密码错误", http.StatusUnauthorized)
+		return
+	}
+	if ga.startSession(w, r) {
+		http.Redirect(w, r, next, http.StatusSeeOther)
+	}
+}
+
+// handleLogout 注销当前会话并清除 Cookie，释放设备占用的名额
+func (ga *gatewayAuthenticator) handleLogout(w http.ResponseWriter, r *http.Request, auth GatewayAuth) {
+	if cookie, err := r.Cookie(gatewaySessionCookie); err == nil {
+		ga.mu.Lock()
+		delete(ga.sessions, cookie.Value)
+		ga.mu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     gatewaySessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   r.TLS != nil,
+	})
+
+	if auth.Mode == LANAuthPassword {
+		http.Redirect(w, r, gatewayLoginPath, http.StatusSeeOther)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "Signed out")
+}
+
+// startSession 创建会话并写入 Cookie，在线设备数达到上限时拒绝新设备
+// 已有会话的设备（如重新扫码、换一个浏览器）不占用新的名额
+func (ga *gatewayAuthenticator) startSession(w http.ResponseWriter, r *http.Request) bool {
+	client := clientIP(r)
+
+	ga.mu.Lock()
+	now := time.Now()
+	ga.expireLocked(now)
+	if clients := ga.clientsLocked(); ga.auth.UserLimit > 0 && !clients[client] && len(clients) >= ga.auth.UserLimit {
+		limit := ga.auth.UserLimit
+		ga.mu.Unlock()
+		http.Error(w, fmt.Sprintf("Device limit reached: at most %d LAN device(s) can be signed in at the same time; sign out on another device or ask the teacher to raise the limit", limit), http.StatusForbidden)
+		return false
+	}
+
+	id := randomHex(32)
+	ga.sessions[id] = &gatewaySession{client: client, created: now, lastSeen: now}
+	ga.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     gatewaySessionCookie,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   r.TLS != nil,
+	})
+	return true
+}
+
+// touch 刷新会话的最近访问时间，会话不存在或已过期时返回 false
+func (ga *gatewayAuthenticator) touch(id string) bool {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+
+	now := time.Now()
+	ga.expireLocked(now)
+	session, exists := ga.sessions[id]
+	if !exists {
+		return false
+	}
+	session.lastSeen = now
+	return true
+}
+
+// writeLoginPage 输出密码登录页面
+func writeLoginPage(w http.ResponseWriter, next, message string, status int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>登录</title></head>
+<body style="font-family: sans-serif; max-width: 320px; margin: 80px auto;">
+<h3>请输入访问密码</h3>
+<p style="color: #c00;">%s</p>
+<form method="post" action="%s">
+<input type="hidden" name="next" value="%s">
+<input type="password" name="password" autofocus style="width: 100%%; padding: 8px;">
+<button type="submit" style="margin-top: 12px; padding: 8px 24px;">进入</button>
+</form></body></html>`, html.EscapeString(message), gatewayLoginPath, html.EscapeString(next))
+}
+
+// isLoopbackRequest 判断请求是否来自本机
+func isLoopbackRequest(r *http.Request) bool {
+	ip := net.ParseIP(clientIP(r))
+	return ip != nil && ip.IsLoopback()
+}
+
+// clientIP 返回请求的客户端 IP
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// secretEqual 以固定时间比较令牌或密码，空值不匹配任何输入
+func secretEqual(given, expected string) bool {
+	if expected == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
+
+// randomHex 生成指定字节数的随机十六进制字符串
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
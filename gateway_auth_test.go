@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// gatewayRequest 模拟局域网设备的请求，cookie 为空时不携带会话
+func gatewayRequest(ga *gatewayAuthenticator, remote, target string, cookie *http.Cookie) (*httptest.ResponseRecorder, bool) {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.RemoteAddr = remote
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	return w, ga.authorize(w, r)
+}
+
+// signIn 使用令牌登录，返回会话 Cookie，被拒绝时返回 nil
+func signIn(t *testing.T, ga *gatewayAuthenticator, remote string) *http.Cookie {
+	t.Helper()
+
+	w, _ := gatewayRequest(ga, remote, "/workflow/?token=secret", nil)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == gatewaySessionCookie {
+			return cookie
+		}
+	}
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("sign in from %s returned %d", remote, w.Code)
+	}
+	return nil
+}
+
+func TestGatewayLimitsDistinctClients(t *testing.T) {
+	ga := newGatewayAuthenticator()
+	ga.setAuth(GatewayAuth{Mode: LANAuthToken, Token: "secret", UserLimit: 2})
+
+	first := signIn(t, ga, "192.168.1.10:50000")
+	if first == nil {
+		t.Fatalf("first device was refused")
+	}
+	// 同一设备再次扫码（如换一个浏览器）不占用新的名额
+	again := signIn(t, ga, "192.168.1.10:50001")
+	if again == nil {
+		t.Fatalf("second session of the same device was refused")
+	}
+	if signIn(t, ga, "192.168.1.11:50000") == nil {
+		t.Fatalf("second device was refused")
+	}
+	if got := ga.activeSessions(); got != 2 {
+		t.Fatalf("active devices = %d, want 2", got)
+	}
+	if signIn(t, ga, "192.168.1.12:50000") != nil {
+		t.Fatalf("third device should be refused")
+	}
+
+	// 本机访问不需要认证，也不占用名额
+	if _, ok := gatewayRequest(ga, "127.0.0.1:50000", "/workflow/", nil); !ok {
+		t.Fatalf("loopback request was refused")
+	}
+
+	// 第一台设备的所有会话注销后释放名额
+	w, _ := gatewayRequest(ga, "192.168.1.10:50000", gatewayLogoutPath, first)
+	if w.Code != http.StatusOK {
+		t.Fatalf("logout returned %d", w.Code)
+	}
+	if _, ok := gatewayRequest(ga, "192.168.1.10:50000", "/workflow/", first); ok {
+		t.Fatalf("session is still valid after logout")
+	}
+	if signIn(t, ga, "192.168.1.12:50000") != nil {
+		t.Fatalf("device was admitted while another session of the first device is still active")
+	}
+	gatewayRequest(ga, "192.168.1.10:50001", gatewayLogoutPath, again)
+	if signIn(t, ga, "192.168.1.12:50000") == nil {
+		t.Fatalf("third device was refused after the first device signed out")
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 事件类型
+const (
+	EventStart        = "start"         // 服务启动
+	EventStartFailed  = "start_failed"  // 服务启动失败
+	EventStop         = "stop"          // 服务被请求停止
+	EventExit         = "exit"          // 服务自行正常退出
+	EventCrash        = "crash"         // 服务异常退出
+	EventConfigUpdate = "config_update" // 配置更新
+	EventInstall      = "install"       // 组件安装
+	EventError        = "error"         // 后台操作失败，例如保存配置历史或密钥
+)
+
+// 事件发起者
+const (
+	ActorUI            = "ui"             // 用户界面操作
+	ActorProfile       = "profile"        // 服务组批量操作
+	ActorScheduler     = "scheduler"      // 定时任务
+	ActorRestartPolicy = "restart_policy" // 自动重启策略
+	ActorSystem        = "system"         // 应用自身（启动、退出、进程监控等）
+)
+
+// maxJournalSize 日志文件超过该大小后轮转
+const maxJournalSize = 10 * 1024 * 1024
+
+// EventOrigin 生命周期操作的来源
+type EventOrigin struct {
+	Actor  string // 发起者
+	Reason string // 原因
+}
+
+// JournalEvent 事件日志条目
+type JournalEvent struct {
+	Timestamp time.Time `json:"timestamp"`         // 发生时间
+	Type      string    `json:"type"`              // 事件类型
+	Service   string    `json:"service,omitempty"` // 服务名称，配置事件为空
+	RunID     string    `json:"run_id,omitempty"`  // 服务运行ID，每次启动生成
+	Actor     string    `json:"actor"`             // 发起者
+	Reason    string    `json:"reason,omitempty"`  // 原因
+	Details   string    `json:"details,omitempty"` // 附加信息，例如退出码或配置段名称
+}
+
+// EventQuery 事件查询条件
+type EventQuery struct {
+	Service string   // 按服务过滤
+	RunID   string   // 按运行ID过滤
+	Actor   string   // 按发起者过滤
+	Types   []string // 按事件类型过滤
+	Since   string   // 起始时间（RFC3339），包含
+	Until   string   // 结束时间（RFC3339），不包含
+	Offset  int      // 分页偏移
+	Limit   int      // 分页大小，0 表示默认值
+}
+
+// EventQueryResult 事件查询结果
+type EventQueryResult struct {
+	Events []JournalEvent // 当前页的事件，按时间倒序
+	Total  int            // 符合条件的事件总数
+	Offset int            // 分页偏移
+	Limit  int            // 分页大小
+	Error  string         // 查询失败时的错误信息
+}
+
+// EventJournal 以 JSONL 格式持久化的事件日志
+type EventJournal struct {
+	path string     // 日志文件路径
+	mu   sync.Mutex // 保护文件写入
+}
+
+// NewEventJournal 创建事件日志
+func NewEventJournal(dir string) (*EventJournal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %v", err)
+	}
+	return &EventJournal{path: filepath.Join(dir, "events.jsonl")}, nil
+}
+
+// Record 追加一条事件，时间为空时使用当前时间
+func (j *EventJournal) Record(event JournalEvent) error {
+	if j == nil {
+		return nil
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.rotateIfNeeded()
+
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %v", err)
+	}
+	return nil
+}
+
+// Query 按条件查询事件，结果按时间倒序分页返回
+func (j *EventJournal) Query(query EventQuery) (EventQueryResult, error) {
+	result := EventQueryResult{Events: []JournalEvent{}, Offset: query.Offset, Limit: query.Limit}
+	if result.Limit <= 0 {
+		result.Limit = 100
+	}
+	if result.Offset < 0 {
+		result.Offset = 0
+	}
+
+	match, err := query.matcher()
+	if err != nil {
+		return result, err
+	}
+
+	j.mu.Lock()
+	var matched []JournalEvent
+	for _, path := range []string{j.path + ".1", j.path} {
+		events, err := readJournalFile(path)
+		if err != nil {
+			j.mu.Unlock()
+			return result, err
+		}
+		for _, event := range events {
+			if match(event) {
+				matched = append(matched, event)
+			}
+		}
+	}
+	j.mu.Unlock()
+
+	sort.SliceStable(matched, func(a, b int) bool {
+		return matched[a].Timestamp.After(matched[b].Timestamp)
+	})
+
+	result.Total = len(matched)
+	if result.Offset < len(matched) {
+		end := result.Offset + result.Limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		result.Events = matched[result.Offset:end]
+	}
+	return result, nil
+}
+
+// rotateIfNeeded 日志文件过大时轮转为 .1，只保留一个历史文件
+func (j *EventJournal) rotateIfNeeded() {
+	info, err := os.Stat(j.path)
+	if err != nil || info.Size() < maxJournalSize {
+		return
+	}
+	os.Rename(j.path, j.path+".1")
+}
+
+// matcher 根据查询条件生成过滤函数
+func (q EventQuery) matcher() (func(JournalEvent) bool, error) {
+	var since, until time.Time
+	var err error
+	if q.Since != "" {
+		if since, err = time.Parse(time.RFC3339, q.Since); err != nil {
+			return nil, fmt.Errorf("invalid since time '%s': %v", q.Since, err)
+		}
+	}
+	if q.Until != "" {
+		if until, err = time.Parse(time.RFC3339, q.Until); err != nil {
+			return nil, fmt.Errorf("invalid until time '%s': %v", q.Until, err)
+		}
+	}
+
+	types := make(map[string]bool, len(q.Types))
+	for _, t := range q.Types {
+		types[t] = true
+	}
+
+	return func(event JournalEvent) bool {
+		if q.Service != "" && event.Service != q.Service {
+			return false
+		}
+		if q.RunID != "" && event.RunID != q.RunID {
+			return false
+		}
+		if q.Actor != "" && event.Actor != q.Actor {
+			return false
+		}
+		if len(types) > 0 && !types[event.Type] {
+			return false
+		}
+		if !since.IsZero() && event.Timestamp.Before(since) {
+			return false
+		}
+		if !until.IsZero() && !event.Timestamp.Before(until) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// readJournalFile 读取日志文件中的所有事件，跳过无法解析的行
+func readJournalFile(path string) ([]JournalEvent, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %v", err)
+	}
+	defer file.Close()
+
+	var events []JournalEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event JournalEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %v", err)
+	}
+	return events, nil
+}
+
+// newRunID 生成服务运行ID
+func newRunID() string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(buf)
+}
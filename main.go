@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -13,7 +14,8 @@ var assets embed.FS
 
 func main() {
 	// Create an instance of the app structure
-	app := NewApp()
+	// --config 和 --set 参数覆盖配置文件路径和配置项
+	app := NewApp(ParseConfigFlags(os.Args[1:]))
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -13,45 +16,60 @@ import (
 
 // ProcessConfig 进程配置
 type ProcessConfig struct {
-	Name    string   // 进程名称
-	Command string   // 命令
-	Args    []string // 参数
-	WorkDir string   // 工作目录
+	Name      string          // 进程名称
+	Command   string          // 命令
+	Args      []string        // 参数
+	WorkDir   string          // 工作目录
+	DependsOn []string        // 依赖的进程，启动时先于本进程启动，停止时晚于本进程停止
+	Service   EmbeddedService `json:"-"` // 进程内服务，设置后忽略 Command 和 WorkDir
+	Generated []string        // 启动前生成的文件（如包含明文 API Key 的配置文件），所有实例退出后删除
+}
+
+// EmbeddedService 在应用进程内运行的服务，与外部进程使用相同的生命周期接口管理
+type EmbeddedService interface {
+	// Start 启动服务，日志写入 output；返回的通道在服务退出时发送退出结果
+	Start(args []string, output io.Writer) (<-chan error, error)
+	// Stop 停止服务，等待进行中的请求完成直到 ctx 超时
+	Stop(ctx context.Context) error
 }
 
 // Process 单个进程的管理
 type Process struct {
-	Config  *ProcessConfig
-	cmd     *exec.Cmd
-	running bool
-	output  string
-	mu      sync.Mutex
+	Config   *ProcessConfig
+	cmd      *exec.Cmd
+	service  EmbeddedService // 当前运行的进程内服务，外部进程为 nil
+	running  bool
+	stopping bool          // 是否由管理器主动停止，用于区分异常退出
+	runID    string        // 当前运行ID
+	exited   chan struct{} // 当前运行的进程退出后关闭
+	output   string
+	mu       sync.Mutex
 }
 
 // ProcessManager 进程管理器
 type ProcessManager struct {
-	processes map[string]*Process       // 进程管理器，key为进程名称
-	configs   map[string]*ProcessConfig // 注册的进程配置
-	mu        sync.RWMutex              // 保护进程映射
-	ctx       context.Context           // 上下文
+	processes   map[string]*Process       // 进程管理器，key为进程名称
+	configs     map[string]*ProcessConfig // 注册的进程配置
+	detached    map[*Process]string       // 未注册在进程名称下的实例（蓝绿重启的备用实例和待下线实例）-> 进程名称
+	mu          sync.RWMutex              // 保护进程映射
+	instances   map[string]int            // 进程名称 -> 运行中的实例数量，用于判断何时删除生成的文件
+	instancesMu sync.Mutex                // 保护 instances
+	ctx         context.Context           // 上下文
+	journal     *EventJournal             // 生命周期事件日志
+	onError     func(message string)      // 事件写入失败时的处理函数，为 nil 时忽略
 }
 
 // NewProcessManager 创建进程管理器
-func NewProcessManager(ctx context.Context) *ProcessManager {
+func NewProcessManager(ctx context.Context, journal *EventJournal) *ProcessManager {
 	pm := &ProcessManager{
 		processes: make(map[string]*Process),
 		configs:   make(map[string]*ProcessConfig),
+		detached:  make(map[*Process]string),
+		instances: make(map[string]int),
 		ctx:       ctx,
+		journal:   journal,
 	}
 
-	// 注册默认的Caddy文件服务器进程
-	pm.RegisterProcess("caddy-fileserver", &ProcessConfig{
-		Name:    "caddy-fileserver",
-		Command: "caddy",
-		Args:    []string{"file-server", "-b"},
-		WorkDir: "",
-	})
-
 	return pm
 }
 
@@ -65,6 +83,23 @@ func (pm *ProcessManager) RegisterProcess(name string, config *ProcessConfig) {
 	}
 }
 
+// SetDependencies 修改进程的依赖，替换为新的配置副本，不影响已取得旧配置的调用方
+func (pm *ProcessManager) SetDependencies(name string, dependsOn []string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	config, exists := pm.configs[name]
+	if !exists {
+		return
+	}
+	updated := *config
+	updated.DependsOn = dependsOn
+	pm.configs[name] = &updated
+	if process, exists := pm.processes[name]; exists {
+		process.Config = &updated
+	}
+}
+
 // GetRegisteredProcesses 获取已注册的进程列表
 func (pm *ProcessManager) GetRegisteredProcesses() []string {
 	pm.mu.RLock()
@@ -77,8 +112,36 @@ func (pm *ProcessManager) GetRegisteredProcesses() []string {
 	return names
 }
 
+// GetProcessConfig 获取指定进程的注册配置
+func (pm *ProcessManager) GetProcessConfig(processName string) (*ProcessConfig, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	config, exists := pm.configs[processName]
+	return config, exists
+}
+
+// IsRunning 判断指定进程是否正在运行
+func (pm *ProcessManager) IsRunning(processName string) bool {
+	pm.mu.RLock()
+	process, exists := pm.processes[processName]
+	pm.mu.RUnlock()
+
+	if !exists {
+		return false
+	}
+
+	process.mu.Lock()
+	defer process.mu.Unlock()
+	return process.running
+}
+
 // StartProcess 启动指定进程
 func (pm *ProcessManager) StartProcess(processName string, extraArgs ...string) string {
+	return pm.StartProcessAs(EventOrigin{Actor: ActorUI}, processName, extraArgs...)
+}
+
+// StartProcessAs 以指定来源启动进程，并记录生命周期事件
+func (pm *ProcessManager) StartProcessAs(origin EventOrigin, processName string, extraArgs ...string) string {
 	pm.mu.RLock()
 	process, exists := pm.processes[processName]
 	config, configExists := pm.configs[processName]
@@ -94,83 +157,208 @@ func (pm *ProcessManager) StartProcess(processName string, extraArgs ...string)
 	if process.running {
 		return fmt.Sprintf("Process '%s' is already running!", processName)
 	}
+	return pm.startRun(origin, processName, process, config, extraArgs)
+}
 
+// startRun 启动进程的一次运行并监控其退出
+// 调用方需持有 process.mu
+func (pm *ProcessManager) startRun(origin EventOrigin, processName string, process *Process, config *ProcessConfig, extraArgs []string) string {
 	// 构建完整的参数列表
-	args := append(config.Args, extraArgs...)
-	process.cmd = exec.CommandContext(pm.ctx, config.Command, args...)
+	args := append(append([]string{}, config.Args...), extraArgs...)
+
+	var done <-chan error
+	var err error
+	if config.Service != nil {
+		done, err = process.startEmbedded(config.Service, args)
+	} else {
+		done, err = pm.startCommand(process, processName, config, args)
+	}
+	if err != nil {
+		// 记录详细的启动错误信息
+		errorMsg := fmt.Sprintf("Failed to start process '%s': %v", processName, err)
+		process.output += "[STARTUP_ERROR] " + errorMsg + "\n"
+		pm.record(JournalEvent{
+			Type:    EventStartFailed,
+			Service: processName,
+			Actor:   origin.Actor,
+			Reason:  origin.Reason,
+			Details: err.Error(),
+		})
+		return errorMsg
+	}
+
+	pm.instancesMu.Lock()
+	pm.instances[processName]++
+	pm.instancesMu.Unlock()
+
+	runID := newRunID()
+	exited := make(chan struct{})
+	process.running = true
+	process.stopping = false
+	process.runID = runID
+	process.exited = exited
+	pm.record(JournalEvent{
+		Type:    EventStart,
+		Service: processName,
+		RunID:   runID,
+		Actor:   origin.Actor,
+		Reason:  origin.Reason,
+		Details: strings.TrimSpace(fmt.Sprintf("%s %s", commandName(config), strings.Join(args, " "))),
+	})
+
+	// 监控子进程状态
+	go func() {
+		err := <-done
+		// 先删除生成的文件再通知已退出，避免删除紧接着启动的新实例生成的文件
+		pm.removeGenerated(processName, config)
+		close(exited)
+
+		process.mu.Lock()
+		defer process.mu.Unlock()
+
+		// 进程已被停止并重新启动时，不再修改新一轮运行的状态
+		if process.exited != exited {
+			return
+		}
+		process.running = false
+
+		event := JournalEvent{Service: processName, RunID: runID, Actor: ActorSystem}
+		if err != nil {
+			// 记录进程退出时的错误信息
+			if exitError, ok := err.(*exec.ExitError); ok {
+				process.output += fmt.Sprintf("[PROCESS_EXIT_ERROR] Process '%s' exited with error: %v, exit code: %d\n",
+					processName, err, exitError.ExitCode())
+			} else {
+				process.output += fmt.Sprintf("[PROCESS_EXIT_ERROR] Process '%s' exited with error: %v\n", processName, err)
+			}
+			event.Type = EventCrash
+			event.Reason = "process exited unexpectedly"
+			event.Details = err.Error()
+		} else {
+			process.output += fmt.Sprintf("[PROCESS_EXIT] Process '%s' exited normally\n", processName)
+			event.Type = EventExit
+			event.Reason = "process exited normally"
+		}
+
+		// 主动停止的进程在停止时已经记录了事件
+		if !process.stopping {
+			pm.record(event)
+		}
+	}()
+
+	return fmt.Sprintf("Process '%s' started successfully", processName)
+}
+
+// removeGenerated 进程的实例退出后，没有其他运行中的实例时删除启动前生成的文件
+func (pm *ProcessManager) removeGenerated(processName string, config *ProcessConfig) {
+	pm.instancesMu.Lock()
+	pm.instances[processName]--
+	remaining := pm.instances[processName]
+	pm.instancesMu.Unlock()
+
+	if remaining > 0 {
+		return
+	}
+	for _, path := range config.Generated {
+		os.Remove(path)
+	}
+}
+
+// commandName 返回用于日志的命令名称，进程内服务使用进程名称
+func commandName(config *ProcessConfig) string {
+	if config.Service != nil {
+		return config.Name + " (built-in)"
+	}
+	return config.Command
+}
+
+// startCommand 启动外部命令，返回的通道在进程退出时发送 Wait 的结果
+// 调用方需持有 process.mu
+func (pm *ProcessManager) startCommand(process *Process, processName string, config *ProcessConfig, args []string) (<-chan error, error) {
+	cmd := exec.CommandContext(pm.ctx, config.Command, args...)
+	process.cmd = cmd
+	process.service = nil
 
 	// 设置工作目录
 	if config.WorkDir != "" {
-		process.cmd.Dir = config.WorkDir
+		cmd.Dir = config.WorkDir
 	}
 
-	process.cmd.SysProcAttr = &syscall.SysProcAttr{
+	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true, // 允许后续杀死整个进程组
 	}
 
 	// 捕获标准输出和错误
-	stdoutPipe, err := process.cmd.StdoutPipe()
+	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
-		return fmt.Sprintf("Failed to create stdout pipe for process '%s': %v", processName, err)
+		return nil, fmt.Errorf("failed to create stdout pipe for process '%s': %v", processName, err)
 	}
 
-	stderrPipe, err := process.cmd.StderrPipe()
+	stderrPipe, err := cmd.StderrPipe()
 	if err != nil {
-		return fmt.Sprintf("Failed to create stderr pipe for process '%s': %v", processName, err)
+		return nil, fmt.Errorf("failed to create stderr pipe for process '%s': %v", processName, err)
 	}
 
 	go func() {
 		scanner := bufio.NewScanner(stdoutPipe)
 		for scanner.Scan() {
-			process.mu.Lock()
-			process.output += "[OUT] " + scanner.Text() + "\n"
-			process.mu.Unlock()
+			process.appendOutput("[OUT] ", scanner.Text())
 		}
 	}()
 	go func() {
 		scanner := bufio.NewScanner(stderrPipe)
 		for scanner.Scan() {
-			process.mu.Lock()
-			process.output += "[ERR] " + scanner.Text() + "\n"
-			process.mu.Unlock()
+			process.appendOutput("[ERR] ", scanner.Text())
 		}
 	}()
 
-	if err := process.cmd.Start(); err != nil {
-		// 记录详细的启动错误信息
-		errorMsg := fmt.Sprintf("Failed to start process '%s': %v", processName, err)
-		process.mu.Lock()
-		process.output += "[STARTUP_ERROR] " + errorMsg + "\n"
-		process.mu.Unlock()
-		return errorMsg
+	if err := cmd.Start(); err != nil {
+		return nil, err
 	}
 
-	process.running = true
-
-	// 监控子进程状态
+	done := make(chan error, 1)
 	go func() {
-		err := process.cmd.Wait()
-		process.mu.Lock()
-		process.running = false
-		if err != nil {
-			// 记录进程退出时的错误信息
-			if exitError, ok := err.(*exec.ExitError); ok {
-				process.output += fmt.Sprintf("[PROCESS_EXIT_ERROR] Process '%s' exited with error: %v, exit code: %d\n",
-					processName, err, exitError.ExitCode())
-			} else {
-				process.output += fmt.Sprintf("[PROCESS_EXIT_ERROR] Process '%s' exited with error: %v\n", processName, err)
-			}
-		} else {
-			process.output += fmt.Sprintf("[PROCESS_EXIT] Process '%s' exited normally\n", processName)
-		}
-		process.mu.Unlock()
+		done <- cmd.Wait()
 	}()
+	return done, nil
+}
 
-	return fmt.Sprintf("Process '%s' started successfully", processName)
+// startEmbedded 启动进程内服务，服务日志写入进程输出
+// 调用方需持有 process.mu
+func (p *Process) startEmbedded(service EmbeddedService, args []string) (<-chan error, error) {
+	p.cmd = nil
+	p.service = service
+	return service.Start(args, &processOutputWriter{process: p, prefix: "[OUT] "})
+}
+
+// appendOutput 追加一行输出日志
+func (p *Process) appendOutput(prefix, line string) {
+	p.mu.Lock()
+	p.output += prefix + line + "\n"
+	p.mu.Unlock()
+}
+
+// processOutputWriter 将进程内服务写入的日志按行追加到进程输出
+type processOutputWriter struct {
+	process *Process
+	prefix  string
+}
+
+// Write 实现 io.Writer
+func (w *processOutputWriter) Write(data []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		w.process.appendOutput(w.prefix, line)
+	}
+	return len(data), nil
 }
 
 // StopProcess 停止指定进程
 func (pm *ProcessManager) StopProcess(processName string) string {
+	return pm.StopProcessAs(EventOrigin{Actor: ActorUI}, processName)
+}
+
+// StopProcessAs 以指定来源停止进程，并记录生命周期事件
+func (pm *ProcessManager) StopProcessAs(origin EventOrigin, processName string) string {
 	pm.mu.RLock()
 	process, exists := pm.processes[processName]
 	pm.mu.RUnlock()
@@ -180,6 +368,9 @@ func (pm *ProcessManager) StopProcess(processName string) string {
 	}
 
 	process.mu.Lock()
+	if process.running && process.service != nil {
+		return pm.stopEmbedded(origin, processName, process)
+	}
 	defer process.mu.Unlock()
 
 	if !process.running || process.cmd == nil || process.cmd.Process == nil {
@@ -203,15 +394,70 @@ func (pm *ProcessManager) StopProcess(processName string) string {
 		return fmt.Sprintf("Failed to kill process '%s': %v", processName, err)
 	}
 
-	// 等待进程真正退出
-	go func() {
-		process.cmd.Wait()
-	}()
-
+	// 进程的退出由监控协程回收
 	process.running = false
+	process.stopping = true
+	pm.record(JournalEvent{
+		Type:    EventStop,
+		Service: processName,
+		RunID:   process.runID,
+		Actor:   origin.Actor,
+		Reason:  origin.Reason,
+		Details: "forcefully",
+	})
 	return fmt.Sprintf("Process '%s' stopped (forcefully)", processName)
 }
 
+// stopEmbedded 停止进程内服务，等待进行中的请求完成，最多等待5秒
+// 调用方需持有 process.mu，本方法在停止服务前释放锁，以免服务写日志时阻塞
+func (pm *ProcessManager) stopEmbedded(origin EventOrigin, processName string, process *Process) string {
+	service := process.service
+	process.running = false
+	process.stopping = true
+	pm.record(JournalEvent{
+		Type:    EventStop,
+		Service: processName,
+		RunID:   process.runID,
+		Actor:   origin.Actor,
+		Reason:  origin.Reason,
+		Details: "gracefully",
+	})
+	process.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := service.Stop(ctx); err != nil {
+		return fmt.Sprintf("Process '%s' stopped (forcefully after timeout: %v)", processName, err)
+	}
+	return fmt.Sprintf("Process '%s' stopped (gracefully)", processName)
+}
+
+// WaitForExit 等待指定进程的当前运行退出，超时返回 false
+func (pm *ProcessManager) WaitForExit(processName string, timeout time.Duration) bool {
+	pm.mu.RLock()
+	process, exists := pm.processes[processName]
+	pm.mu.RUnlock()
+
+	if !exists {
+		return true
+	}
+
+	process.mu.Lock()
+	exited := process.exited
+	process.mu.Unlock()
+
+	if exited == nil {
+		return true
+	}
+
+	select {
+	case <-exited:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
 // GetProcessStatus 获取指定进程状态
 func (pm *ProcessManager) GetProcessStatus(processName string) string {
 	pm.mu.RLock()
@@ -267,20 +513,45 @@ func (pm *ProcessManager) GetProcessOutput(processName string) string {
 // StopAllProcesses 停止所有运行中的进程
 func (pm *ProcessManager) StopAllProcesses() {
 	pm.mu.RLock()
+	instances := make(map[*Process]string, len(pm.processes)+len(pm.detached))
+	for name, process := range pm.processes {
+		instances[process] = name
+	}
+	for process, name := range pm.detached {
+		instances[process] = name
+	}
+	pm.mu.RUnlock()
+
 	var wg sync.WaitGroup
-	for _, process := range pm.processes {
-		if process.running {
+	for process, name := range instances {
+		process.mu.Lock()
+		running, runID := process.running, process.runID
+		process.mu.Unlock()
+		if running {
 			wg.Add(1)
-			go func(p *Process) {
+			go func(name, runID string, p *Process) {
 				defer wg.Done()
 				p.stopGracefully()
-			}(process)
+				pm.record(JournalEvent{
+					Type:    EventStop,
+					Service: name,
+					RunID:   runID,
+					Actor:   ActorSystem,
+					Reason:  "application shutdown",
+				})
+			}(name, runID, process)
 		}
 	}
-	pm.mu.RUnlock()
 	wg.Wait()
 }
 
+// record 记录生命周期事件，日志写入失败不影响进程管理
+func (pm *ProcessManager) record(event JournalEvent) {
+	if err := pm.journal.Record(event); err != nil && pm.onError != nil {
+		pm.onError(fmt.Sprintf("failed to record %s event for '%s': %v", event.Type, event.Service, err))
+	}
+}
+
 // ReleaseResources 释放所有进程资源
 func (pm *ProcessManager) ReleaseResources() {
 	pm.mu.RLock()
@@ -295,9 +566,28 @@ func (pm *ProcessManager) ReleaseResources() {
 // stopGracefully 优雅地停止单个进程
 func (p *Process) stopGracefully() {
 	p.mu.Lock()
+
+	// 进程内服务：释放锁后关闭服务，最多等待5秒
+	if p.running && p.service != nil {
+		service := p.service
+		p.running = false
+		p.stopping = true
+		p.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		service.Stop(ctx)
+		return
+	}
 	defer p.mu.Unlock()
 
-	if !p.running || p.cmd == nil || p.cmd.Process == nil {
+	if !p.running {
+		return
+	}
+	p.stopping = true
+
+	if p.cmd == nil || p.cmd.Process == nil {
+		p.running = false
 		return
 	}
 
@@ -311,13 +601,8 @@ func (p *Process) stopGracefully() {
 			err = syscall.Kill(-pgid, syscall.SIGTERM)
 			if err == nil {
 				// 等待进程退出，最多等待5秒
-				done := make(chan error, 1)
-				go func() {
-					done <- p.cmd.Wait()
-				}()
-
 				select {
-				case <-done:
+				case <-p.exited:
 					// 进程已经优雅退出
 					p.running = false
 					return
@@ -332,12 +617,6 @@ func (p *Process) stopGracefully() {
 		err = p.cmd.Process.Kill()
 	}
 
-	// 等待进程真正退出
-	if p.cmd.ProcessState == nil {
-		go func() {
-			p.cmd.Wait() // 等待进程退出，清理僵尸进程
-		}()
-	}
-
+	// 进程的退出由监控协程回收
 	p.running = false
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServiceResult 单个服务的启停结果
+type ServiceResult struct {
+	Service      string // 服务名称
+	Success      bool   // 是否成功
+	Skipped      bool   // 是否被跳过（例如依赖启动失败）
+	IsDependency bool   // 是否因依赖关系被自动加入
+	Message      string // 结果信息
+}
+
+// ProfileResult 服务组启停结果
+type ProfileResult struct {
+	Profile string          // 服务组名称
+	Success bool            // 所有服务是否都成功
+	Message string          // 汇总信息
+	Results []ServiceResult // 各服务结果，按执行顺序排列
+}
+
+// ResolveStartOrder 按依赖关系计算服务的启动顺序
+// 返回的列表中依赖总是排在依赖它的服务之前，未在 services 中列出的依赖会被自动加入
+func (pm *ProcessManager) ResolveStartOrder(services []string) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	order := make([]string, 0, len(services))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("circular dependency: %s -> %s", strings.Join(path, " -> "), name)
+		}
+
+		config, exists := pm.GetProcessConfig(name)
+		if !exists {
+			if len(path) > 0 {
+				return fmt.Errorf("process '%s' required by '%s' not found", name, path[len(path)-1])
+			}
+			return fmt.Errorf("process '%s' not found", name)
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range config.DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range services {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// ResolveStopOrder 计算服务的停止顺序
+// 只包含 services 中列出的服务，依赖它们的服务排在前面先停止
+func (pm *ProcessManager) ResolveStopOrder(services []string) ([]string, error) {
+	startOrder, err := pm.ResolveStartOrder(services)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[string]bool, len(services))
+	for _, name := range services {
+		listed[name] = true
+	}
+
+	order := make([]string, 0, len(services))
+	for i := len(startOrder) - 1; i >= 0; i-- {
+		if listed[startOrder[i]] {
+			order = append(order, startOrder[i])
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// SecretRefPrefix 配置文件中密钥引用的前缀，如 secret://workflow.apikey
+const SecretRefPrefix = "secret://"
+
+// 密钥存储的保护方式
+const (
+	SecretProtectionMachine    = "machine"    // 使用本机密钥文件保护，无需输入口令
+	SecretProtectionPassphrase = "passphrase" // 使用用户口令派生的密钥保护，每次启动后需要解锁
+)
+
+// ErrSecretsLocked 使用口令保护的密钥存储尚未解锁
+var ErrSecretsLocked = errors.New("secrets store is locked: enter the passphrase to unlock it")
+
+// secretKeyAAD 加密数据密钥时使用的附加数据
+const secretKeyAAD = "eduexp-secrets-key"
+
+// SecretsStatus 密钥存储状态
+type SecretsStatus struct {
+	Protection string // 保护方式: machine/passphrase
+	Locked     bool   // 是否已锁定
+	Count      int    // 保存的密钥数量
+}
+
+// secretStoreFile 密钥存储文件格式
+// 各密钥使用随机生成的数据密钥加密，数据密钥再由本机密钥或口令派生的密钥加密，修改口令时只需重新加密数据密钥
+type secretStoreFile struct {
+	Version    int               `json:"version"`
+	Protection string            `json:"protection"`
+	Salt       string            `json:"salt,omitempty"` // 口令派生密钥使用的盐
+	WrappedKey string            `json:"wrapped_key"`    // 加密后的数据密钥
+	Secrets    map[string]string `json:"secrets"`        // 密钥ID -> 加密后的值
+}
+
+// SecretStore 加密保存 API Key 等敏感配置
+// 配置文件中只保存 secret://<id> 引用，启动服务时才解密
+// 本机密钥保护时 secrets.key 与 secrets.json 保存在同一目录，只能防止配置文件、历史版本和导出文件泄露明文；
+// 密钥文件不绑定本机硬件，复制整个配置目录即可解密，需要防止这种情况时应设置口令
+type SecretStore struct {
+	file    string          // 密钥存储文件
+	keyFile string          // 本机密钥文件
+	mu      sync.Mutex      // 保护 data 和 key
+	data    secretStoreFile // 存储内容
+	key     []byte          // 已解锁的数据密钥，锁定时为 nil
+}
+
+// NewSecretStore 打开或创建密钥存储，新建的存储使用本机密钥保护
+func NewSecretStore(dir string) (*SecretStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create secrets directory: %v", err)
+	}
+
+	store := &SecretStore{
+		file:    filepath.Join(dir, "secrets.json"),
+		keyFile: filepath.Join(dir, "secrets.key"),
+	}
+
+	data, err := os.ReadFile(store.file)
+	if os.IsNotExist(err) {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate data key: %v", err)
+		}
+		store.key = key
+		store.data = secretStoreFile{Version: 1, Secrets: make(map[string]string)}
+		if err := store.protectWithMachineKey(); err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets store: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &store.data); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets store: %v", err)
+	}
+	if store.data.Secrets == nil {
+		store.data.Secrets = make(map[string]string)
+	}
+
+	// 本机密钥保护的存储直接解锁
+	if store.data.Protection == SecretProtectionMachine {
+		machineKey, err := os.ReadFile(store.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read machine key: %v", err)
+		}
+		if store.key, err = openSecret(machineKey, store.data.WrappedKey, secretKeyAAD); err != nil {
+			return nil, fmt.Errorf("failed to unlock secrets with machine key: %v", err)
+		}
+	}
+	return store, nil
+}
+
+// Status 获取密钥存储状态
+func (s *SecretStore) Status() SecretsStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SecretsStatus{
+		Protection: s.data.Protection,
+		Locked:     s.key == nil,
+		Count:      len(s.data.Secrets),
+	}
+}
+
+// Put 加密保存密钥，返回写入配置文件的引用
+func (s *SecretStore) Put(id, value string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		return "", ErrSecretsLocked
+	}
+	sealed, err := sealSecret(s.key, value, id)
+	if err != nil {
+		return "", err
+	}
+	s.data.Secrets[id] = sealed
+	if err := s.saveLocked(); err != nil {
+		return "", err
+	}
+	return SecretRefPrefix + id, nil
+}
+
+// Delete 删除密钥
+func (s *SecretStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Secrets[id]; !exists {
+		return nil
+	}
+	delete(s.data.Secrets, id)
+	return s.saveLocked()
+}
+
+// Sealed 返回指定密钥的密文，不存在的密钥不返回，不需要解锁
+// 密文由数据密钥加密，修改口令后仍可通过 RestoreSealed 恢复，用于随配置历史版本保存密钥
+func (s *SecretStore) Sealed(ids []string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sealed := make(map[string]string)
+	for _, id := range ids {
+		if value, exists := s.data.Secrets[id]; exists {
+			sealed[id] = value
+		}
+	}
+	return sealed
+}
+
+// RestoreSealed 写回 Sealed 返回的密文，已解锁时先确认密文可以解密
+func (s *SecretStore) RestoreSealed(sealed map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for id, value := range sealed {
+		if s.data.Secrets[id] == value {
+			continue
+		}
+		if s.key != nil {
+			if _, err := openSecret(s.key, value, id); err != nil {
+				return fmt.Errorf("failed to decrypt saved secret '%s': %v", id, err)
+			}
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	for id, value := range sealed {
+		s.data.Secrets[id] = value
+	}
+	return s.saveLocked()
+}
+
+// Resolve 解析配置值，引用返回解密后的密钥，其他值原样返回
+func (s *SecretStore) Resolve(value string) (string, error) {
+	if !IsSecretRef(value) {
+		return value, nil
+	}
+	id := strings.TrimPrefix(value, SecretRefPrefix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		return "", ErrSecretsLocked
+	}
+	sealed, exists := s.data.Secrets[id]
+	if !exists {
+		return "", fmt.Errorf("secret '%s' not found", id)
+	}
+	plain, err := openSecret(s.key, sealed, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret '%s': %v", id, err)
+	}
+	return string(plain), nil
+}
+
+// Unlock 使用口令解锁密钥存储
+func (s *SecretStore) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key != nil {
+		return nil
+	}
+	kek, err := derivePassphraseKey(passphrase, s.data.Salt)
+	if err != nil {
+		return err
+	}
+	key, err := openSecret(kek, s.data.WrappedKey, secretKeyAAD)
+	if err != nil {
+		return fmt.Errorf("incorrect passphrase")
+	}
+	s.key = key
+	return nil
+}
+
+// Lock 锁定使用口令保护的密钥存储，本机密钥保护的存储不能锁定
+func (s *SecretStore) Lock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Protection != SecretProtectionPassphrase {
+		return fmt.Errorf("secrets are protected by the machine key and cannot be locked")
+	}
+	s.key = nil
+	return nil
+}
+
+// SetPassphrase 修改保护方式：口令非空时改为口令保护，为空时改回本机密钥保护
+// 存储需处于解锁状态，已保存的密钥无需重新加密
+func (s *SecretStore) SetPassphrase(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		return ErrSecretsLocked
+	}
+	if passphrase == "" {
+		return s.protectWithMachineKey()
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %v", err)
+	}
+	encodedSalt := base64.StdEncoding.EncodeToString(salt)
+	kek, err := derivePassphraseKey(passphrase, encodedSalt)
+	if err != nil {
+		return err
+	}
+	wrapped, err := sealSecret(kek, string(s.key), secretKeyAAD)
+	if err != nil {
+		return err
+	}
+
+	s.data.Protection = SecretProtectionPassphrase
+	s.data.Salt = encodedSalt
+	s.data.WrappedKey = wrapped
+	if err := s.saveLocked(); err != nil {
+		return err
+	}
+
+	// 口令保护后删除本机密钥文件
+	os.Remove(s.keyFile)
+	return nil
+}
+
+// protectWithMachineKey 使用本机密钥文件加密数据密钥，密钥文件不存在时生成
+// 调用方需持有 s.mu 或确保没有并发访问
+func (s *SecretStore) protectWithMachineKey() error {
+	machineKey, err := os.ReadFile(s.keyFile)
+	if err != nil || len(machineKey) != 32 {
+		machineKey = make([]byte, 32)
+		if _, err := rand.Read(machineKey); err != nil {
+			return fmt.Errorf("failed to generate machine key: %v", err)
+		}
+		if err := writeFileAtomic(s.keyFile, machineKey, 0600); err != nil {
+			return fmt.Errorf("failed to write machine key: %v", err)
+		}
+	}
+
+	wrapped, err := sealSecret(machineKey, string(s.key), secretKeyAAD)
+	if err != nil {
+		return err
+	}
+	s.data.Protection = SecretProtectionMachine
+	s.data.Salt = ""
+	s.data.WrappedKey = wrapped
+	return s.saveLocked()
+}
+
+// saveLocked 写入密钥存储文件，调用方需持有 s.mu
+func (s *SecretStore) saveLocked() error {
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode secrets store: %v", err)
+	}
+	if err := writeFileAtomic(s.file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write secrets store: %v", err)
+	}
+	return nil
+}
+
+// IsSecretRef 判断配置值是否为密钥引用
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretRefPrefix)
+}
+
+// derivePassphraseKey 使用 scrypt 从口令派生密钥
+func derivePassphraseKey(passphrase, encodedSalt string) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %v", err)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %v", err)
+	}
+	return key, nil
+}
+
+// sealSecret 使用 AES-256-GCM 加密，返回 base64 编码的随机数和密文
+// aad 为密钥ID，防止密文被替换到其他配置项
+func sealSecret(key []byte, plain, aad string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), []byte(aad))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openSecret 解密 sealSecret 的结果
+func openSecret(key []byte, sealed, aad string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %v", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, []byte(aad))
+}
+
+// newGCM 创建 AES-GCM 加密器
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestSecretStore 在临时目录创建密钥存储
+func newTestSecretStore(t *testing.T) (*SecretStore, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	store, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create secrets store: %v", err)
+	}
+	return store, dir
+}
+
+// assertResolve 检查引用解析为预期的值
+func assertResolve(t *testing.T, store *SecretStore, ref, want string) {
+	t.Helper()
+
+	got, err := store.Resolve(ref)
+	if err != nil {
+		t.Fatalf("failed to resolve %s: %v", ref, err)
+	}
+	if got != want {
+		t.Fatalf("%s resolved to %q, want %q", ref, got, want)
+	}
+}
+
+func TestSecretPutResolveRoundTrip(t *testing.T) {
+	store, dir := newTestSecretStore(t)
+
+	ref, err := store.Put("workflow.apikey", "pat_123")
+	if err != nil {
+		t.Fatalf("failed to put secret: %v", err)
+	}
+	if ref != "secret://workflow.apikey" {
+		t.Fatalf("unexpected reference %q", ref)
+	}
+	assertResolve(t, store, ref, "pat_123")
+
+	// 非引用原样返回
+	assertResolve(t, store, "plain-value", "plain-value")
+
+	// 重新打开后仍可解密
+	reopened, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen secrets store: %v", err)
+	}
+	assertResolve(t, reopened, ref, "pat_123")
+	if status := reopened.Status(); status.Protection != SecretProtectionMachine || status.Locked || status.Count != 1 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	if _, err := reopened.Resolve("secret://missing"); err == nil {
+		t.Fatalf("resolving a missing secret should fail")
+	}
+}
+
+func TestSecretUnlockRejectsWrongPassphrase(t *testing.T) {
+	store, dir := newTestSecretStore(t)
+	ref, err := store.Put("workflow.apikey", "pat_123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetPassphrase("correct horse"); err != nil {
+		t.Fatalf("failed to set passphrase: %v", err)
+	}
+
+	reopened, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen secrets store: %v", err)
+	}
+	if !reopened.Status().Locked {
+		t.Fatalf("passphrase-protected store should start locked")
+	}
+	if _, err := reopened.Resolve(ref); err != ErrSecretsLocked {
+		t.Fatalf("resolving while locked returned %v, want ErrSecretsLocked", err)
+	}
+
+	if err := reopened.Unlock("wrong horse"); err == nil {
+		t.Fatalf("unlock with a wrong passphrase should fail")
+	}
+	if !reopened.Status().Locked {
+		t.Fatalf("store should stay locked after a wrong passphrase")
+	}
+
+	if err := reopened.Unlock("correct horse"); err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+	assertResolve(t, reopened, ref, "pat_123")
+}
+
+func TestSecretProtectionSwitchKeepsSecrets(t *testing.T) {
+	store, dir := newTestSecretStore(t)
+	ref, err := store.Put("workflow.apikey", "pat_123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 本机密钥 -> 口令
+	if err := store.SetPassphrase("correct horse"); err != nil {
+		t.Fatalf("failed to set passphrase: %v", err)
+	}
+	assertResolve(t, store, ref, "pat_123")
+
+	locked, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := locked.Unlock("correct horse"); err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+	assertResolve(t, locked, ref, "pat_123")
+
+	// 口令 -> 本机密钥
+	if err := locked.SetPassphrase(""); err != nil {
+		t.Fatalf("failed to switch back to the machine key: %v", err)
+	}
+
+	reopened, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen secrets store: %v", err)
+	}
+	if status := reopened.Status(); status.Protection != SecretProtectionMachine || status.Locked {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	assertResolve(t, reopened, ref, "pat_123")
+}
+
+func TestSecretRejectsTamperedCiphertext(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(secrets map[string]string)
+		ref    string
+	}{
+		{
+			name: "modified ciphertext",
+			tamper: func(secrets map[string]string) {
+				data, _ := base64.StdEncoding.DecodeString(secrets["workflow.apikey"])
+				data[len(data)-1] ^= 0xff
+				secrets["workflow.apikey"] = base64.StdEncoding.EncodeToString(data)
+			},
+			ref: "secret://workflow.apikey",
+		},
+		{
+			name: "ciphertext moved to another id",
+			tamper: func(secrets map[string]string) {
+				secrets["lan.password"] = secrets["workflow.apikey"]
+			},
+			ref: "secret://lan.password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, _ := newTestSecretStore(t)
+			if _, err := store.Put("workflow.apikey", "pat_123"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Put("lan.password", "classroom"); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.tamper(store.data.Secrets)
+
+			_, err := store.Resolve(tt.ref)
+			if err == nil {
+				t.Fatalf("resolving %s should fail", tt.ref)
+			}
+			if !strings.Contains(err.Error(), "failed to decrypt") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestOverriddenSecretIsNotSaved(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(configFile, []byte("schema_version = 5\n\n[workflow]\napikey = \"file_key\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := NewConfigManager(ConfigOptions{File: configFile, Overrides: []string{"workflow.apikey=env_key"}})
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	store, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{configManager: cm, secrets: store}
+	cm.sealSecrets = app.sealChangedSecrets
+	app.sealConfigSecrets()
+
+	ref := cm.FileConfig().Workflow.ApiKey
+	if ref != "secret://workflow.apikey" {
+		t.Fatalf("file API key was not sealed: %q", ref)
+	}
+
+	// 界面读取到覆盖后的值并原样提交，只修改端口
+	workflow := *app.GetWorkflowConfig()
+	if workflow.ApiKey != "env_key" {
+		t.Fatalf("effective API key = %q, want the override", workflow.ApiKey)
+	}
+	workflow.WorkflowUIPort = "9000"
+	if result := app.UpdateWorkflowConfig(workflow); result != "Workflow configuration updated successfully" {
+		t.Fatalf("failed to update workflow config: %s", result)
+	}
+	if got := cm.FileConfig().Workflow.ApiKey; got != ref {
+		t.Fatalf("file API key changed to %q", got)
+	}
+	assertResolve(t, store, ref, "file_key")
+
+	// 在界面中修改 API Key 时保存新的值
+	workflow = *app.GetWorkflowConfig()
+	workflow.ApiKey = "new_key"
+	if result := app.UpdateWorkflowConfig(workflow); result != "Workflow configuration updated successfully" {
+		t.Fatalf("failed to update workflow config: %s", result)
+	}
+	assertResolve(t, store, cm.FileConfig().Workflow.ApiKey, "new_key")
+}
+
+func TestPlaintextSecretsAreSealedBeforeWriting(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.toml")
+	data, err := os.ReadFile(filepath.Join("testdata", "config", "v0-baseline.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// 加密保存密钥之前写入的历史版本
+	historyDir := filepath.Join(dir, "history")
+	if err := os.MkdirAll(historyDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(historyDir, "20260101-120000.000.toml"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := newConfigManager(ConfigOptions{File: configFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{configManager: cm, secrets: store}
+	cm.sealSecrets = app.sealChangedSecrets
+	if err := cm.load(); err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	assertResolve(t, store, cm.FileConfig().Workflow.ApiKey, "coze-key")
+	assertResolve(t, store, cm.FileConfig().EduExp.ArkApiKey, "ark-key")
+
+	// 手动编辑配置文件填写新的明文密钥
+	edited := cm.FileConfig()
+	edited.Workflow.ApiKey = "edited-key"
+	external, err := encodeConfig(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configFile, external, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cm.checkExternalChange()
+	assertResolve(t, store, cm.FileConfig().Workflow.ApiKey, "edited-key")
+
+	// 配置文件、历史版本和迁移前备份中都不包含明文，副本仅当前用户可读
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Base(path) == "secrets.json" {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, plain := range []string{"coze-key", "ark-key", "edited-key"} {
+			if strings.Contains(string(content), plain) {
+				t.Errorf("%s contains the plaintext key %q", path, plain)
+			}
+		}
+		isCopy := strings.HasPrefix(path, historyDir) || strings.HasPrefix(path, configFile+".v")
+		if isCopy && info.Mode().Perm()&0077 != 0 {
+			t.Errorf("%s is readable by other users (%v)", path, info.Mode().Perm())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := filepath.Glob(configFile + ".v*.bak"); len(backups) != 1 {
+		t.Fatalf("backups = %v, want one migration backup", backups)
+	}
+}
+
+// newTestSecretsApp 在临时目录创建加密保存密钥的应用
+func newTestSecretsApp(t *testing.T) *App {
+	t.Helper()
+
+	dir := t.TempDir()
+	cm, err := newConfigManager(ConfigOptions{File: filepath.Join(dir, "config.toml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewSecretStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := NewEventJournal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{configManager: cm, secrets: store, journal: journal}
+	cm.sealSecrets = app.sealChangedSecrets
+	cm.secrets = store
+	if err := cm.load(); err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	return app
+}
+
+// setTestAPIKey 通过界面接口修改工作流 API Key，返回保存的历史版本ID
+func setTestAPIKey(t *testing.T, app *App, key string) string {
+	t.Helper()
+
+	workflow := *app.GetWorkflowConfig()
+	workflow.ApiKey = key
+	if result := app.UpdateWorkflowConfig(workflow); result != "Workflow configuration updated successfully" {
+		t.Fatalf("failed to update workflow config: %s", result)
+	}
+	versions := app.ListConfigHistory()
+	if len(versions) == 0 || !versions[0].Current {
+		t.Fatalf("no current config version after update: %+v", versions)
+	}
+	return versions[0].ID
+}
+
+func TestRestoreVersionRestoresSecrets(t *testing.T) {
+	app := newTestSecretsApp(t)
+	cm := app.configManager
+
+	first := setTestAPIKey(t, app, "key-1")
+	ref := cm.FileConfig().Workflow.ApiKey
+	second := setTestAPIKey(t, app, "key-2")
+	if cm.FileConfig().Workflow.ApiKey != ref {
+		t.Fatalf("changing the key should keep the reference %q", ref)
+	}
+
+	// 只修改密钥的版本也记录变化的配置段
+	versions := app.ListConfigHistory()
+	if versions[0].ID != second || !reflect.DeepEqual(versions[0].Sections, []string{"workflow"}) {
+		t.Fatalf("latest version = %+v, want workflow changed", versions[0])
+	}
+
+	if result := app.RestoreConfigVersion(first); !strings.HasPrefix(result, "Configuration restored") {
+		t.Fatalf("failed to restore: %s", result)
+	}
+	assertResolve(t, app.secrets, cm.FileConfig().Workflow.ApiKey, "key-1")
+
+	// 清空密钥后恢复，引用的密钥随版本一并恢复
+	setTestAPIKey(t, app, "")
+	if result := app.RestoreConfigVersion(second); !strings.HasPrefix(result, "Configuration restored") {
+		t.Fatalf("failed to restore: %s", result)
+	}
+	assertResolve(t, app.secrets, cm.FileConfig().Workflow.ApiKey, "key-2")
+
+	// 历史版本和保存的密钥仅当前用户可读，且不包含明文
+	paths, _ := filepath.Glob(filepath.Join(cm.historyDir(), "*"))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm()&0077 != 0 {
+			t.Errorf("%s is readable by other users (%v)", path, info.Mode().Perm())
+		}
+		content, _ := os.ReadFile(path)
+		if strings.Contains(string(content), "key-1") || strings.Contains(string(content), "key-2") {
+			t.Errorf("%s contains a plaintext key", path)
+		}
+	}
+}
+
+func TestConfigHistoryIsKeptPerProfile(t *testing.T) {
+	app := newTestSecretsApp(t)
+	cm := app.configManager
+
+	setTestAPIKey(t, app, "default-key")
+	defaultVersions := len(app.ListConfigHistory())
+
+	if result := app.CreateConfigProfile("exam"); result != "Profile 'exam' created successfully" {
+		t.Fatal(result)
+	}
+	if result := app.ActivateConfigProfile("exam"); !strings.HasPrefix(result, "Profile 'exam' activated") {
+		t.Fatal(result)
+	}
+	setTestAPIKey(t, app, "exam-key")
+
+	if dir := cm.historyDir(); dir != cm.profileHistoryDir("exam") || dir == cm.profileHistoryDir(DefaultConfigProfile) {
+		t.Fatalf("profile history directory = %s", dir)
+	}
+	for _, version := range app.ListConfigHistory() {
+		config, err := cm.LoadVersion(version.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref := config.Workflow.ApiKey; ref != "" && !strings.HasPrefix(ref, "secret://profiles/exam/") {
+			t.Fatalf("version %s of profile exam references %q", version.ID, ref)
+		}
+	}
+
+	if result := app.ActivateConfigProfile(DefaultConfigProfile); !strings.HasPrefix(result, "Profile 'default' activated") {
+		t.Fatal(result)
+	}
+	if got := len(app.ListConfigHistory()); got < defaultVersions {
+		t.Fatalf("default profile has %d versions, want at least %d", got, defaultVersions)
+	}
+	for _, version := range app.ListConfigHistory() {
+		config, err := cm.LoadVersion(version.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref := config.Workflow.ApiKey; strings.HasPrefix(ref, "secret://profiles/") {
+			t.Fatalf("version %s of the default profile references %q", version.ID, ref)
+		}
+	}
+	// 切换档案不会删除原档案的密钥
+	assertResolve(t, app.secrets, cm.FileConfig().Workflow.ApiKey, "default-key")
+}
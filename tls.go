@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 证书有效期
+const (
+	caValidity          = 10 * 365 * 24 * time.Hour // 根证书有效期
+	hostCertValidity    = 825 * 24 * time.Hour      // 服务证书有效期，不超过主流浏览器接受的上限
+	hostCertRenewBefore = 30 * 24 * time.Hour       // 服务证书在到期前多久重新签发
+)
+
+// CAInfo 本地根证书信息
+type CAInfo struct {
+	Subject     string    // 证书主题
+	Fingerprint string    // SHA-256 指纹
+	NotAfter    time.Time // 到期时间
+	Path        string    // 证书文件路径
+	Constrained bool      // 是否限制了可签发的主机名和 IP，早期版本生成的根证书没有限制，可重新生成
+}
+
+// privateIPRanges 回环和局域网地址段，根证书允许为其中的地址签发证书，局域网地址变化（如 DHCP 重新分配）后仍然适用
+var privateIPRanges = []string{
+	"127.0.0.0/8", "::1/128",
+	"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "169.254.0.0/16",
+	"fc00::/7", "fe80::/10",
+}
+
+// CertificateAuthority 本地自签名证书颁发机构
+// 根证书和私钥保存在配置目录下，首次使用时生成；各服务的证书按需签发并在主机名变化或临近到期时重新签发
+type CertificateAuthority struct {
+	dir string     // 证书目录
+	mu  sync.Mutex // 保护证书文件的生成
+
+	// hosts 返回证书需要覆盖的主机名和 IP，生成根证书时据此设置名称限制；为空时只允许本机
+	hosts func() []string
+}
+
+// NewCertificateAuthority 创建证书颁发机构
+func NewCertificateAuthority(dir string) *CertificateAuthority {
+	return &CertificateAuthority{dir: dir}
+}
+
+// caCertPath 返回根证书路径
+func (ca *CertificateAuthority) caCertPath() string {
+	return filepath.Join(ca.dir, "ca.pem")
+}
+
+// caKeyPath 返回根证书私钥路径
+func (ca *CertificateAuthority) caKeyPath() string {
+	return filepath.Join(ca.dir, "ca-key.pem")
+}
+
+// CACertificatePEM 获取根证书（PEM 编码），不存在时生成
+func (ca *CertificateAuthority) CACertificatePEM() ([]byte, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	if _, _, err := ca.loadOrCreateCA(); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(ca.caCertPath())
+}
+
+// Info 获取根证书信息，不存在时生成
+func (ca *CertificateAuthority) Info() (*CAInfo, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	cert, _, err := ca.loadOrCreateCA()
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	return &CAInfo{
+		Subject:     cert.Subject.CommonName,
+		Fingerprint: strings.ToUpper(hex.EncodeToString(fingerprint[:])),
+		NotAfter:    cert.NotAfter,
+		Path:        ca.caCertPath(),
+		Constrained: len(cert.PermittedDNSDomains) > 0 || len(cert.PermittedIPRanges) > 0,
+	}, nil
+}
+
+// Regenerate 删除并重新生成根证书，已签发的服务证书随之失效，学生设备需要重新安装根证书
+func (ca *CertificateAuthority) Regenerate() error {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	for _, path := range []string{ca.caCertPath(), ca.caKeyPath()} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove CA certificate: %v", err)
+		}
+	}
+	_, _, err := ca.loadOrCreateCA()
+	return err
+}
+
+// IssueCertificate 为服务签发覆盖指定主机名和 IP 的证书，返回证书和私钥文件路径
+// 已有证书覆盖所有主机且未临近到期时直接复用
+func (ca *CertificateAuthority) IssueCertificate(name string, hosts []string) (string, string, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	caCert, caKey, err := ca.loadOrCreateCA()
+	if err != nil {
+		return "", "", err
+	}
+	// 名称限制之外的主机即使签发了证书，客户端也会拒绝
+	for _, host := range hosts {
+		if host != "" && !caPermits(caCert, host) {
+			return "", "", fmt.Errorf("'%s' is outside the names the local CA may certify; regenerate the CA certificate and reinstall it on student devices", host)
+		}
+	}
+
+	certFile := filepath.Join(ca.dir, "hosts", name+".pem")
+	keyFile := filepath.Join(ca.dir, "hosts", name+"-key.pem")
+	if existing, err := readCertificate(certFile); err == nil && certificateCovers(existing, caCert, hosts) {
+		if _, err := os.Stat(keyFile); err == nil {
+			return certFile, keyFile, nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate key: %v", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: name, Organization: []string{"EduExp Desktop"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(hostCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign certificate: %v", err)
+	}
+	if err := writeKeyPair(certFile, keyFile, der, key); err != nil {
+		return "", "", err
+	}
+	return certFile, keyFile, nil
+}
+
+// loadOrCreateCA 读取根证书，不存在时生成新的根证书
+// 调用方需持有 ca.mu
+func (ca *CertificateAuthority) loadOrCreateCA() (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	cert, certErr := readCertificate(ca.caCertPath())
+	key, keyErr := readPrivateKey(ca.caKeyPath())
+	if certErr == nil && keyErr == nil {
+		return cert, key, nil
+	}
+	if !os.IsNotExist(certErr) && certErr != nil {
+		return nil, nil, fmt.Errorf("failed to read CA certificate: %v", certErr)
+	}
+	if !os.IsNotExist(keyErr) && keyErr != nil {
+		return nil, nil, fmt.Errorf("failed to read CA key: %v", keyErr)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate CA key: %v", err)
+	}
+
+	hostname, _ := os.Hostname()
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if ca.hosts != nil {
+		hosts = append(hosts, ca.hosts()...)
+	}
+	dnsDomains, ipRanges := caNameConstraints(hosts)
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "EduExp Desktop Local CA " + hostname, Organization: []string{"EduExp Desktop"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		// 学生设备信任该根证书，限制其只能为本机和局域网签发证书，私钥泄露时也无法冒充其他网站
+		PermittedDNSDomainsCritical: true,
+		PermittedDNSDomains:         dnsDomains,
+		PermittedIPRanges:           ipRanges,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+	if err := writeKeyPair(ca.caCertPath(), ca.caKeyPath(), der, key); err != nil {
+		return nil, nil, err
+	}
+
+	// 根证书变化后旧的服务证书不再有效
+	os.RemoveAll(filepath.Join(ca.dir, "hosts"))
+
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// caNameConstraints 根据证书需要覆盖的主机生成根证书的名称限制
+// 域名限制为各主机名（包括其子域名），IP 限制为回环和局域网地址段，以及这些地址段之外的指定 IP
+func caNameConstraints(hosts []string) ([]string, []*net.IPNet) {
+	var ipRanges []*net.IPNet
+	for _, cidr := range privateIPRanges {
+		_, ipRange, _ := net.ParseCIDR(cidr)
+		ipRanges = append(ipRanges, ipRange)
+	}
+
+	var dnsDomains []string
+	for _, host := range certificateHosts(hosts) {
+		ip := net.ParseIP(host)
+		if ip == nil {
+			if !domainPermitted(dnsDomains, host) {
+				dnsDomains = append(dnsDomains, host)
+			}
+			continue
+		}
+		if !ipPermitted(ipRanges, ip) {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ipRanges = append(ipRanges, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return dnsDomains, ipRanges
+}
+
+// caPermits 判断根证书的名称限制是否允许为主机签发证书，没有名称限制的根证书允许所有主机
+func caPermits(caCert *x509.Certificate, host string) bool {
+	if len(caCert.PermittedDNSDomains) == 0 && len(caCert.PermittedIPRanges) == 0 {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ipPermitted(caCert.PermittedIPRanges, ip)
+	}
+	return domainPermitted(caCert.PermittedDNSDomains, host)
+}
+
+// domainPermitted 判断域名是否等于允许的域名或为其子域名
+func domainPermitted(domains []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipPermitted 判断 IP 是否位于允许的地址段中
+func ipPermitted(ranges []*net.IPNet, ip net.IP) bool {
+	for _, ipRange := range ranges {
+		if ipRange.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateCovers 判断证书是否由当前根证书签发、覆盖所有主机且未临近到期
+func certificateCovers(cert, caCert *x509.Certificate, hosts []string) bool {
+	if time.Until(cert.NotAfter) < hostCertRenewBefore || cert.CheckSignatureFrom(caCert) != nil {
+		return false
+	}
+	for _, host := range hosts {
+		if host != "" && cert.VerifyHostname(host) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// certificateHosts 去重并排序证书需要覆盖的主机名
+func certificateHosts(hosts ...[]string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, group := range hosts {
+		for _, host := range group {
+			host = strings.TrimSpace(host)
+			if host == "" || seen[host] {
+				continue
+			}
+			seen[host] = true
+			result = append(result, host)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// tlsListener 使用证书文件将监听器包装为 TLS 监听器
+func tlsListener(listener net.Listener, certFile, keyFile string) (net.Listener, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+	return tls.NewListener(listener, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// tlsListenerOrClose 包装为 TLS 监听器，失败时关闭原监听器
+func tlsListenerOrClose(listener net.Listener, certFile, keyFile string) (net.Listener, error) {
+	wrapped, err := tlsListener(listener, certFile, keyFile)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return wrapped, nil
+}
+
+// readCertificate 读取 PEM 编码的证书
+func readCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("invalid certificate file '%s'", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// readPrivateKey 读取 PEM 编码的 EC 私钥
+func readPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, fmt.Errorf("invalid key file '%s'", path)
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// writeKeyPair 写入证书和私钥，私钥仅当前用户可读
+func writeKeyPair(certFile, keyFile string, der []byte, key *ecdsa.PrivateKey) error {
+	if err := os.MkdirAll(filepath.Dir(certFile), 0700); err != nil {
+		return fmt.Errorf("failed to create certificate directory: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode key: %v", err)
+	}
+
+	var keyPEM bytes.Buffer
+	pem.Encode(&keyPEM, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyFile, keyPEM.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certFile, certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write certificate: %v", err)
+	}
+	return nil
+}
+
+// randomSerial 生成随机证书序列号
+func randomSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		panic(fmt.Sprintf("failed to generate serial number: %v", err))
+	}
+	return serial
+}
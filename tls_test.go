@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalCAIsNameConstrained(t *testing.T) {
+	ca := NewCertificateAuthority(t.TempDir())
+	ca.hosts = func() []string {
+		return []string{"localhost", "workflow.localhost", "classroom.example.org", "192.168.1.20", "203.0.113.5"}
+	}
+
+	info, err := ca.Info()
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	if !info.Constrained {
+		t.Fatalf("new CA has no name constraints")
+	}
+	caCert, caKey, err := ca.loadOrCreateCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	// 本机、局域网（包括 DHCP 重新分配的地址）和配置的主机可以签发并通过校验
+	hosts := []string{"localhost", "edu.localhost", "classroom.example.org", "127.0.0.1", "192.168.1.20", "10.0.0.8", "203.0.113.5"}
+	certFile, _, err := ca.IssueCertificate("gateway", hosts)
+	if err != nil {
+		t.Fatalf("failed to issue certificate: %v", err)
+	}
+	cert, err := readCertificate(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range hosts {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Fatalf("certificate for %s does not verify: %v", host, err)
+		}
+	}
+
+	// 超出名称限制的主机拒绝签发
+	for _, host := range []string{"example.com", "198.51.100.1"} {
+		if _, _, err := ca.IssueCertificate("fileserver", []string{"localhost", host}); err == nil || !strings.Contains(err.Error(), host) {
+			t.Fatalf("issuing a certificate for %s returned %v", host, err)
+		}
+	}
+
+	// 即使用根证书私钥直接签发，客户端也会拒绝名称限制之外的证书
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("198.51.100.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, forged, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"www.example.com", "198.51.100.1"} {
+		if _, err := parsed.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err == nil {
+			t.Fatalf("forged certificate for %s verified", host)
+		}
+	}
+
+	// 重新生成后使用新的根证书
+	if err := ca.Regenerate(); err != nil {
+		t.Fatalf("failed to regenerate CA: %v", err)
+	}
+	regenerated, err := ca.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if regenerated.Fingerprint == info.Fingerprint || !regenerated.Constrained {
+		t.Fatalf("regenerated CA = %+v", regenerated)
+	}
+}
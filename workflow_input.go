@@ -0,0 +1,371 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// WorkflowInputResult 工作流输入的校验结果
+type WorkflowInputResult struct {
+	Valid  bool                   // 是否通过校验
+	Values map[string]interface{} // 规范化后的输入：已应用默认值、去掉不显示的参数，数值、布尔、多选和 JSON 参数转换为对应类型
+	Errors []ValidationError      // 校验错误，Field 为参数键
+}
+
+// workflowValueError 参数值不符合参数定义
+type workflowValueError struct {
+	code    string
+	message string
+}
+
+// Error 实现 error
+func (e *workflowValueError) Error() string {
+	return e.message
+}
+
+// valueError 创建参数值错误
+func valueError(code, format string, args ...interface{}) error {
+	return &workflowValueError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// ValidateWorkflowInput 按参数定义校验工作流输入，不显示的参数不校验也不输出
+func ValidateWorkflowInput(workflow WorkflowDef, values map[string]interface{}) WorkflowInputResult {
+	result := WorkflowInputResult{Values: make(map[string]interface{}), Errors: []ValidationError{}}
+	addError := func(field, code, message string) {
+		result.Errors = append(result.Errors, ValidationError{Field: field, Code: code, Message: message})
+	}
+
+	parameters := make(map[string]WorkflowParameter, len(workflow.Parameters))
+	for _, parameter := range workflow.Parameters {
+		parameters[parameter.Key] = parameter
+	}
+	for _, key := range sortedKeys(values) {
+		if _, exists := parameters[key]; !exists {
+			addError(key, ValidationInvalidValue, fmt.Sprintf("unknown parameter '%s'", key))
+		}
+	}
+
+	// 先转换所有参数的值，显示条件依赖转换后的值
+	normalized := make(map[string]interface{}, len(parameters))
+	failures := make(map[string]error)
+	for _, parameter := range workflow.Parameters {
+		raw := values[parameter.Key]
+		if isEmptyWorkflowValue(raw) && parameter.DefaultValue != "" {
+			raw = parameter.DefaultValue
+		}
+		if isEmptyWorkflowValue(raw) {
+			continue
+		}
+		value, err := checkWorkflowValue(parameter, raw, true)
+		if err != nil {
+			failures[parameter.Key] = err
+			continue
+		}
+		normalized[parameter.Key] = value
+	}
+
+	visibility := newWorkflowVisibility(parameters, normalized)
+	for _, parameter := range workflow.Parameters {
+		if !visibility.visible(parameter.Key) {
+			continue
+		}
+		if err, failed := failures[parameter.Key]; failed {
+			code := ValidationInvalidValue
+			if valueErr, ok := err.(*workflowValueError); ok {
+				code = valueErr.code
+			}
+			addError(parameter.Key, code, err.Error())
+			continue
+		}
+		value, exists := normalized[parameter.Key]
+		if !exists {
+			if parameter.Required {
+				addError(parameter.Key, ValidationRequired, fmt.Sprintf("%s is required", workflowParameterName(parameter)))
+			}
+			continue
+		}
+		result.Values[parameter.Key] = value
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// checkWorkflowValue 按参数类型和约束检查参数值，返回转换后的值
+// checkFiles 为 false 时不检查文件是否存在，用于校验配置中的默认值
+func checkWorkflowValue(parameter WorkflowParameter, raw interface{}, checkFiles bool) (interface{}, error) {
+	switch parameter.Type {
+	case "number":
+		number, err := workflowNumber(raw)
+		if err != nil {
+			return nil, err
+		}
+		if parameter.Min != nil && number < *parameter.Min {
+			return nil, valueError(ValidationOutOfRange, "must be at least %s", formatWorkflowNumber(*parameter.Min))
+		}
+		if parameter.Max != nil && number > *parameter.Max {
+			return nil, valueError(ValidationOutOfRange, "must be at most %s", formatWorkflowNumber(*parameter.Max))
+		}
+		return number, nil
+
+	case "boolean":
+		switch value := raw.(type) {
+		case bool:
+			return value, nil
+		case string:
+			if value == "true" || value == "false" {
+				return value == "true", nil
+			}
+		}
+		return nil, valueError(ValidationInvalidValue, "%s must be true or false", describeWorkflowValue(raw))
+
+	case "select":
+		value, ok := raw.(string)
+		if !ok || !containsString(parameter.Options, value) {
+			return nil, valueError(ValidationInvalidValue, "%s is not one of the options", describeWorkflowValue(raw))
+		}
+		return value, nil
+
+	case "multiselect":
+		selected, err := workflowSelection(raw)
+		if err != nil {
+			return nil, err
+		}
+		for i, value := range selected {
+			if !containsString(parameter.Options, value) {
+				return nil, valueError(ValidationInvalidValue, "'%s' is not one of the options", value)
+			}
+			if containsString(selected[:i], value) {
+				return nil, valueError(ValidationDuplicate, "'%s' is selected more than once", value)
+			}
+		}
+		if err := checkWorkflowLength(parameter, len(selected), "selected items"); err != nil {
+			return nil, err
+		}
+		return selected, nil
+
+	case "date":
+		value, ok := raw.(string)
+		if !ok {
+			return nil, valueError(ValidationInvalidFormat, "%s is not a date in YYYY-MM-DD format", describeWorkflowValue(raw))
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, valueError(ValidationInvalidFormat, "'%s' is not a date in YYYY-MM-DD format", value)
+		}
+		return value, nil
+
+	case "file":
+		value, ok := raw.(string)
+		if !ok {
+			return nil, valueError(ValidationInvalidFormat, "%s is not a file path or URL", describeWorkflowValue(raw))
+		}
+		if parsed, err := url.Parse(value); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" {
+			return value, nil
+		}
+		if checkFiles {
+			info, err := os.Stat(value)
+			if err != nil || info.IsDir() {
+				return nil, valueError(ValidationNotFound, "file '%s' not found", value)
+			}
+		}
+		return value, nil
+
+	case "json":
+		text, ok := raw.(string)
+		if !ok {
+			// 前端直接传入的对象或数组
+			return raw, nil
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return nil, valueError(ValidationInvalidFormat, "invalid JSON: %v", err)
+		}
+		return value, nil
+
+	default:
+		// text、textarea
+		value, ok := raw.(string)
+		if !ok {
+			return nil, valueError(ValidationInvalidValue, "%s is not text", describeWorkflowValue(raw))
+		}
+		if err := checkWorkflowLength(parameter, utf8.RuneCountInString(value), "characters"); err != nil {
+			return nil, err
+		}
+		if parameter.Pattern != "" {
+			pattern, err := compileWorkflowPattern(parameter.Pattern)
+			if err != nil {
+				return nil, valueError(ValidationInvalidFormat, "invalid pattern: %v", err)
+			}
+			if !pattern.MatchString(value) {
+				return nil, valueError(ValidationInvalidFormat, "'%s' does not match the pattern %s", value, parameter.Pattern)
+			}
+		}
+		return value, nil
+	}
+}
+
+// checkWorkflowLength 检查文本长度或多选数量
+func checkWorkflowLength(parameter WorkflowParameter, length int, unit string) error {
+	if parameter.MinLength > 0 && length < parameter.MinLength {
+		return valueError(ValidationOutOfRange, "must have at least %d %s", parameter.MinLength, unit)
+	}
+	if parameter.MaxLength > 0 && length > parameter.MaxLength {
+		return valueError(ValidationOutOfRange, "must have at most %d %s", parameter.MaxLength, unit)
+	}
+	return nil
+}
+
+// compileWorkflowPattern 编译参数的正则表达式，整个文本需匹配
+func compileWorkflowPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// workflowNumber 将数值或数值文本转换为 float64
+func workflowNumber(raw interface{}) (float64, error) {
+	switch value := raw.(type) {
+	case float64:
+		return value, nil
+	case int:
+		return float64(value), nil
+	case json.Number:
+		return value.Float64()
+	case string:
+		if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return number, nil
+		}
+	}
+	return 0, valueError(ValidationInvalidValue, "%s is not a number", describeWorkflowValue(raw))
+}
+
+// workflowSelection 将多选的值转换为字符串列表，文本使用逗号分隔（配置中的默认值）
+func workflowSelection(raw interface{}) ([]string, error) {
+	switch value := raw.(type) {
+	case []string:
+		return value, nil
+	case []interface{}:
+		selected := make([]string, len(value))
+		for i, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				return nil, valueError(ValidationInvalidValue, "%s is not one of the options", describeWorkflowValue(item))
+			}
+			selected[i] = text
+		}
+		return selected, nil
+	case string:
+		var selected []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				selected = append(selected, item)
+			}
+		}
+		return selected, nil
+	}
+	return nil, valueError(ValidationInvalidValue, "%s is not a list of options", describeWorkflowValue(raw))
+}
+
+// isEmptyWorkflowValue 判断参数值是否为空
+func isEmptyWorkflowValue(raw interface{}) bool {
+	switch value := raw.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(value) == ""
+	case []interface{}:
+		return len(value) == 0
+	case []string:
+		return len(value) == 0
+	}
+	return false
+}
+
+// describeWorkflowValue 错误信息中显示的参数值
+func describeWorkflowValue(raw interface{}) string {
+	if text, ok := raw.(string); ok {
+		return fmt.Sprintf("'%s'", text)
+	}
+	data, _ := json.Marshal(raw)
+	return string(data)
+}
+
+// formatWorkflowNumber 格式化数值，整数不显示小数部分
+func formatWorkflowNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// workflowParameterName 错误信息中显示的参数名称
+func workflowParameterName(parameter WorkflowParameter) string {
+	if parameter.Label != "" {
+		return parameter.Label
+	}
+	return parameter.Key
+}
+
+// workflowVisibility 计算参数的显示条件
+type workflowVisibility struct {
+	parameters map[string]WorkflowParameter
+	values     map[string]interface{}
+	results    map[string]bool
+	visiting   map[string]bool
+}
+
+// newWorkflowVisibility 创建显示条件计算器
+func newWorkflowVisibility(parameters map[string]WorkflowParameter, values map[string]interface{}) *workflowVisibility {
+	return &workflowVisibility{
+		parameters: parameters,
+		values:     values,
+		results:    make(map[string]bool),
+		visiting:   make(map[string]bool),
+	}
+}
+
+// visible 参数是否显示：依赖的参数都显示且取值满足条件；条件循环依赖时不显示
+func (w *workflowVisibility) visible(key string) bool {
+	if result, done := w.results[key]; done {
+		return result
+	}
+	if w.visiting[key] {
+		return false
+	}
+	w.visiting[key] = true
+	defer delete(w.visiting, key)
+
+	result := true
+	for _, condition := range w.parameters[key].VisibleWhen {
+		if _, exists := w.parameters[condition.Key]; !exists || !w.visible(condition.Key) || !conditionMatches(w.values[condition.Key], condition.Values) {
+			result = false
+			break
+		}
+	}
+	w.results[key] = result
+	return result
+}
+
+// conditionMatches 参数值是否为条件取值之一，多选参数选中其中任一项即满足
+func conditionMatches(value interface{}, values []string) bool {
+	switch typed := value.(type) {
+	case nil:
+		return false
+	case string:
+		return containsString(values, typed)
+	case bool:
+		return containsString(values, strconv.FormatBool(typed))
+	case float64:
+		return containsString(values, formatWorkflowNumber(typed))
+	case []string:
+		for _, item := range typed {
+			if containsString(values, item) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
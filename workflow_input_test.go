@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// float 返回数值指针，用于参数的最小值和最大值
+func float(value float64) *float64 {
+	return &value
+}
+
+func TestCheckWorkflowValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		parameter WorkflowParameter
+		raw       interface{}
+		want      interface{}
+		wantCode  string // 为空表示校验通过
+	}{
+		{"number in range", WorkflowParameter{Type: "number", Min: float(1), Max: float(10)}, 5.0, 5.0, ""},
+		{"number from text", WorkflowParameter{Type: "number"}, " 2.5 ", 2.5, ""},
+		{"number below min", WorkflowParameter{Type: "number", Min: float(1)}, 0.5, nil, ValidationOutOfRange},
+		{"number above max", WorkflowParameter{Type: "number", Max: float(10)}, 11.0, nil, ValidationOutOfRange},
+		{"number not numeric", WorkflowParameter{Type: "number"}, "abc", nil, ValidationInvalidValue},
+
+		{"text within length", WorkflowParameter{Type: "text", MinLength: 2, MaxLength: 4}, "数学课", "数学课", ""},
+		{"text too short", WorkflowParameter{Type: "text", MinLength: 2}, "a", nil, ValidationOutOfRange},
+		{"text too long", WorkflowParameter{Type: "textarea", MaxLength: 3}, "abcd", nil, ValidationOutOfRange},
+		{"text matches pattern", WorkflowParameter{Type: "text", Pattern: `[A-Z]\d+`}, "A12", "A12", ""},
+		{"pattern matches whole text", WorkflowParameter{Type: "text", Pattern: `[A-Z]\d+`}, "xA12", nil, ValidationInvalidFormat},
+		{"text not a string", WorkflowParameter{Type: "text"}, 3.0, nil, ValidationInvalidValue},
+
+		{"select option", WorkflowParameter{Type: "select", Options: []string{"a", "b"}}, "b", "b", ""},
+		{"select unknown option", WorkflowParameter{Type: "select", Options: []string{"a", "b"}}, "c", nil, ValidationInvalidValue},
+
+		{"multiselect list", WorkflowParameter{Type: "multiselect", Options: []string{"a", "b", "c"}}, []interface{}{"a", "c"}, []string{"a", "c"}, ""},
+		{"multiselect from text", WorkflowParameter{Type: "multiselect", Options: []string{"a", "b"}}, "a, b", []string{"a", "b"}, ""},
+		{"multiselect duplicate", WorkflowParameter{Type: "multiselect", Options: []string{"a", "b"}}, []interface{}{"a", "a"}, nil, ValidationDuplicate},
+		{"multiselect unknown option", WorkflowParameter{Type: "multiselect", Options: []string{"a"}}, []interface{}{"x"}, nil, ValidationInvalidValue},
+		{"multiselect too many", WorkflowParameter{Type: "multiselect", Options: []string{"a", "b", "c"}, MaxLength: 2}, []interface{}{"a", "b", "c"}, nil, ValidationOutOfRange},
+
+		{"date", WorkflowParameter{Type: "date"}, "2026-09-01", "2026-09-01", ""},
+		{"date wrong format", WorkflowParameter{Type: "date"}, "2026/09/01", nil, ValidationInvalidFormat},
+		{"date out of range", WorkflowParameter{Type: "date"}, "2026-02-30", nil, ValidationInvalidFormat},
+
+		{"json text", WorkflowParameter{Type: "json"}, `{"grade": 3}`, map[string]interface{}{"grade": 3.0}, ""},
+		{"json object", WorkflowParameter{Type: "json"}, []interface{}{"a"}, []interface{}{"a"}, ""},
+		{"json invalid", WorkflowParameter{Type: "json"}, `{"grade":`, nil, ValidationInvalidFormat},
+
+		{"boolean", WorkflowParameter{Type: "boolean"}, true, true, ""},
+		{"boolean from text", WorkflowParameter{Type: "boolean"}, "false", false, ""},
+		{"boolean invalid", WorkflowParameter{Type: "boolean"}, "yes", nil, ValidationInvalidValue},
+
+		{"file url", WorkflowParameter{Type: "file"}, "https://example.com/a.pdf", "https://example.com/a.pdf", ""},
+		{"file missing", WorkflowParameter{Type: "file"}, "/nonexistent/a.pdf", nil, ValidationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkWorkflowValue(tt.parameter, tt.raw, true)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("got %#v, want %#v", got, tt.want)
+				}
+				return
+			}
+
+			valueErr, ok := err.(*workflowValueError)
+			if !ok {
+				t.Fatalf("got %#v, %v; want a %s error", got, err, tt.wantCode)
+			}
+			if valueErr.code != tt.wantCode {
+				t.Fatalf("error code = %s, want %s (%v)", valueErr.code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestValidateWorkflowInput(t *testing.T) {
+	workflow := WorkflowDef{
+		Name: "出题",
+		Parameters: []WorkflowParameter{
+			{Key: "mode", Type: "select", Options: []string{"auto", "custom"}, DefaultValue: "auto"},
+			{Key: "prompt", Label: "提示词", Type: "textarea", Required: true,
+				VisibleWhen: []WorkflowCondition{{Key: "mode", Values: []string{"custom"}}}},
+			{Key: "count", Type: "number", Min: float(1), Max: float(20), DefaultValue: "5"},
+			// a、b 互相依赖，始终不显示
+			{Key: "a", Type: "text", Required: true, VisibleWhen: []WorkflowCondition{{Key: "b", Values: []string{"x"}}}},
+			{Key: "b", Type: "text", Required: true, VisibleWhen: []WorkflowCondition{{Key: "a", Values: []string{"x"}}}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		values     map[string]interface{}
+		wantValues map[string]interface{}
+		wantErrors map[string]string // 参数键 -> 错误代码
+	}{
+		{
+			name:       "hidden required parameter is skipped",
+			values:     map[string]interface{}{},
+			wantValues: map[string]interface{}{"mode": "auto", "count": 5.0},
+		},
+		{
+			name:       "hidden parameter value is dropped",
+			values:     map[string]interface{}{"mode": "auto", "prompt": "ignored", "a": "x", "b": "x"},
+			wantValues: map[string]interface{}{"mode": "auto", "count": 5.0},
+		},
+		{
+			name:       "visible required parameter",
+			values:     map[string]interface{}{"mode": "custom"},
+			wantErrors: map[string]string{"prompt": ValidationRequired},
+		},
+		{
+			name:       "visible parameter value is kept",
+			values:     map[string]interface{}{"mode": "custom", "prompt": "两位数加法", "count": "10"},
+			wantValues: map[string]interface{}{"mode": "custom", "prompt": "两位数加法", "count": 10.0},
+		},
+		{
+			name:       "invalid and unknown values",
+			values:     map[string]interface{}{"count": 50.0, "extra": "x"},
+			wantErrors: map[string]string{"count": ValidationOutOfRange, "extra": ValidationInvalidValue},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateWorkflowInput(workflow, tt.values)
+
+			errors := make(map[string]string)
+			for _, err := range result.Errors {
+				errors[err.Field] = err.Code
+			}
+			if len(tt.wantErrors) > 0 {
+				if result.Valid || !reflect.DeepEqual(errors, tt.wantErrors) {
+					t.Fatalf("errors = %v, want %v", result.Errors, tt.wantErrors)
+				}
+				return
+			}
+			if !result.Valid {
+				t.Fatalf("unexpected errors: %v", result.Errors)
+			}
+			if !reflect.DeepEqual(result.Values, tt.wantValues) {
+				t.Fatalf("values = %#v, want %#v", result.Values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestRunWorkflowValidatesBeforeSending(t *testing.T) {
+	var requests []cozeWorkflowRunRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/workflow/run" || r.Header.Get("Authorization") != "Bearer pat_123" {
+			t.Errorf("unexpected request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var request cozeWorkflowRunRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		requests = append(requests, request)
+		json.NewEncoder(w).Encode(cozeWorkflowRunResponse{Data: `{"output":"ok"}`})
+	}))
+	defer server.Close()
+	defer func(base string) { cozeAPIBase = base }(cozeAPIBase)
+	cozeAPIBase = server.URL
+
+	cm := newTestConfigManager(t)
+	if err := cm.UpdateWorkflowConfig(WorkflowConfig{ApiKey: "pat_123", WorkflowUIPort: "8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cm.AddWorkflow("quiz", WorkflowDef{Name: "出题", WorkflowID: "wf-1", Parameters: []WorkflowParameter{
+		{Key: "count", Type: "number", Min: float(1), Max: float(20), Required: true},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	app := &App{configManager: cm}
+
+	result := app.RunWorkflow("quiz", map[string]interface{}{"count": "50"})
+	if result.Success || len(result.Errors) != 1 || result.Errors[0].Field != "count" {
+		t.Fatalf("invalid input was not rejected: %+v", result)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("invalid input was sent to coze: %+v", requests)
+	}
+
+	result = app.RunWorkflow("quiz", map[string]interface{}{"count": "10"})
+	if !result.Success || result.Output != `{"output":"ok"}` {
+		t.Fatalf("failed to run workflow: %+v", result)
+	}
+	want := []cozeWorkflowRunRequest{{WorkflowID: "wf-1", Parameters: map[string]interface{}{"count": 10.0}}}
+	if !reflect.DeepEqual(requests, want) {
+		t.Fatalf("requests = %+v, want %+v", requests, want)
+	}
+}
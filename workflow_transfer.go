@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// workflowFileFormat 工作流定义文件的格式标识
+const workflowFileFormat = "eduexp-workflows"
+
+// workflowFileVersion 工作流定义文件的格式版本
+const workflowFileVersion = 1
+
+// 导入的工作流键与已有工作流冲突时的处理方式
+const (
+	WorkflowConflictSkip      = "skip"      // 保留已有的工作流，不导入
+	WorkflowConflictOverwrite = "overwrite" // 用导入的定义覆盖已有的工作流
+	WorkflowConflictRename    = "rename"    // 导入为新的键，如 essay_2
+)
+
+// 导入的工作流定义来源
+const (
+	WorkflowSourceEduExp = "eduexp" // 本应用导出的工作流定义文件
+	WorkflowSourceCoze   = "coze"   // 扣子导出的工作流信息
+)
+
+// 导入单个工作流时的操作
+const (
+	WorkflowImportAdd       = "add"       // 新增
+	WorkflowImportOverwrite = "overwrite" // 覆盖已有的工作流
+	WorkflowImportRename    = "rename"    // 键冲突，导入为新的键
+	WorkflowImportSkip      = "skip"      // 键冲突，跳过
+)
+
+// WorkflowImportPreview 工作流导入预览
+type WorkflowImportPreview struct {
+	Source    string               // 文件来源: eduexp/coze
+	Conflict  string               // 键冲突处理方式: skip/overwrite/rename
+	Workflows []WorkflowImportItem // 文件中的工作流
+	Errors    []ValidationError    // 导入后工作流配置的校验错误，存在错误时不能导入
+}
+
+// WorkflowImportItem 导入文件中的一个工作流
+type WorkflowImportItem struct {
+	SourceKey  string // 文件中的键
+	Key        string // 导入后的键
+	Name       string // 工作流名称
+	Parameters int    // 参数数量
+	Conflict   bool   // 是否与已有工作流的键冲突
+	Action     string // 操作: add/overwrite/rename/skip
+}
+
+// exportWorkflows 将工作流定义导出为 JSON，键名与配置文件一致，不包含修订号
+func exportWorkflows(workflows map[string]WorkflowDef, keys []string) ([]byte, error) {
+	if len(keys) == 0 {
+		keys = sortedKeys(workflows)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no workflows to export")
+	}
+	selected := make(map[string]WorkflowDef, len(keys))
+	for _, key := range keys {
+		workflow, exists := workflows[key]
+		if !exists {
+			return nil, fmt.Errorf("workflow '%s' not found", key)
+		}
+		workflow.Revision = 0
+		selected[key] = workflow
+	}
+
+	// 通过 TOML 编码得到与配置文件一致的键名
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"workflows": selected}); err != nil {
+		return nil, fmt.Errorf("failed to encode workflows: %v", err)
+	}
+	raw, err := decodeRawConfig(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode workflows: %v", err)
+	}
+	for _, value := range raw {
+		for _, workflow := range value.(map[string]interface{}) {
+			delete(workflow.(map[string]interface{}), "revision")
+		}
+	}
+
+	encoded, err := json.MarshalIndent(map[string]interface{}{
+		"format":      workflowFileFormat,
+		"version":     workflowFileVersion,
+		"exported_at": time.Now().Format(time.RFC3339),
+		"workflows":   raw["workflows"],
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode workflows: %v", err)
+	}
+	return encoded, nil
+}
+
+// parseWorkflowImport 解析工作流定义文件或扣子导出的工作流信息，返回来源和工作流定义
+func parseWorkflowImport(data []byte) (string, map[string]WorkflowDef, error) {
+	raw, err := decodeImportData(data, ConfigFormatJSON)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse workflow file: %v", err)
+	}
+
+	if format, _ := raw["format"].(string); format == workflowFileFormat {
+		if version, _ := raw["version"].(int64); version > workflowFileVersion {
+			return "", nil, fmt.Errorf("workflow file version %d is newer than supported version %d", version, workflowFileVersion)
+		}
+		workflows, err := decodeWorkflowTables(raw["workflows"])
+		if err != nil {
+			return "", nil, err
+		}
+		return WorkflowSourceEduExp, workflows, nil
+	}
+
+	if key, workflow, ok := parseCozeWorkflow(raw); ok {
+		return WorkflowSourceCoze, map[string]WorkflowDef{key: workflow}, nil
+	}
+	return "", nil, fmt.Errorf("unrecognized workflow file: expected an exported workflow file or Coze workflow metadata")
+}
+
+// decodeWorkflowTables 将原始键值解析为工作流定义
+func decodeWorkflowTables(value interface{}) (map[string]WorkflowDef, error) {
+	tables, ok := value.(map[string]interface{})
+	if !ok || len(tables) == 0 {
+		return nil, fmt.Errorf("workflow file contains no workflows")
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"workflows": tables}); err != nil {
+		return nil, fmt.Errorf("invalid workflow definitions: %v", err)
+	}
+	var decoded struct {
+		Workflows map[string]WorkflowDef `toml:"workflows"`
+	}
+	if _, err := toml.Decode(buf.String(), &decoded); err != nil {
+		return nil, fmt.Errorf("invalid workflow definitions: %v", err)
+	}
+	for key, workflow := range decoded.Workflows {
+		workflow.Revision = 0
+		decoded.Workflows[key] = workflow
+	}
+	return decoded.Workflows, nil
+}
+
+// parseCozeWorkflow 从扣子导出的工作流信息中读取工作流和输入变量
+// 支持 OpenAPI 返回的工作流信息（workflow_detail 和 input.parameters）和工作流画布（开始节点的 outputs）
+func parseCozeWorkflow(raw map[string]interface{}) (string, WorkflowDef, bool) {
+	if data, ok := raw["data"].(map[string]interface{}); ok {
+		raw = data
+	}
+	// 画布可能以 JSON 字符串保存在 schema 中
+	if schema, ok := raw["schema"].(string); ok {
+		if decoded, err := decodeImportData([]byte(schema), ConfigFormatJSON); err == nil {
+			mergeRawTables(decoded, raw)
+			raw = decoded
+		}
+	}
+
+	detail, _ := raw["workflow_detail"].(map[string]interface{})
+	if detail == nil {
+		detail = raw
+	}
+	workflow := WorkflowDef{
+		Name:       rawString(detail, "workflow_name", "name"),
+		WorkflowID: rawString(detail, "workflow_id", "id"),
+		AppID:      rawString(detail, "app_id"),
+	}
+
+	var parameters []WorkflowParameter
+	found := false
+	if input, ok := raw["input"].(map[string]interface{}); ok {
+		if variables, ok := input["parameters"].(map[string]interface{}); ok {
+			found = true
+			for _, name := range sortedKeys(variables) {
+				variable, _ := variables[name].(map[string]interface{})
+				parameters = append(parameters, cozeParameter(name, variable))
+			}
+		}
+	}
+	if nodes, ok := raw["nodes"].([]map[string]interface{}); ok {
+		for _, node := range nodes {
+			if !isCozeStartNode(node) {
+				continue
+			}
+			found = true
+			data, _ := node["data"].(map[string]interface{})
+			outputs, _ := data["outputs"].([]map[string]interface{})
+			for _, variable := range outputs {
+				parameters = append(parameters, cozeParameter(rawString(variable, "name"), variable))
+			}
+		}
+	}
+	if !found || workflow.WorkflowID == "" {
+		return "", WorkflowDef{}, false
+	}
+
+	workflow.Parameters = parameters
+	if workflow.Name == "" {
+		workflow.Name = workflow.WorkflowID
+	}
+	return workflowKeyFromName(workflow.Name, workflow.WorkflowID), workflow, true
+}
+
+// isCozeStartNode 判断画布节点是否为开始节点
+func isCozeStartNode(node map[string]interface{}) bool {
+	switch nodeType := node["type"].(type) {
+	case string:
+		return nodeType == "1" || nodeType == "start"
+	case int64:
+		return nodeType == 1
+	}
+	return false
+}
+
+// cozeParameter 将扣子的输入变量转换为工作流参数
+func cozeParameter(name string, variable map[string]interface{}) WorkflowParameter {
+	parameter := WorkflowParameter{
+		Key:      name,
+		Label:    name,
+		Type:     "text",
+		HelpText: rawString(variable, "description"),
+	}
+	if required, ok := variable["required"].(bool); ok {
+		parameter.Required = required
+	}
+
+	switch strings.ToLower(rawString(variable, "type")) {
+	case "integer", "number", "float":
+		parameter.Type = "number"
+	case "boolean":
+		parameter.Type = "boolean"
+	case "object", "list", "array":
+		parameter.Type = "json"
+	case "file", "image", "doc", "audio", "video":
+		parameter.Type = "file"
+	}
+	// 文件类型的变量在扣子中为 string 加 assist_type
+	if rawString(variable, "assist_type", "assistType") != "" {
+		parameter.Type = "file"
+	}
+
+	switch value := firstRawValue(variable, "default_value", "defaultValue").(type) {
+	case string:
+		parameter.DefaultValue = value
+	case bool:
+		parameter.DefaultValue = fmt.Sprint(value)
+	case int64:
+		parameter.DefaultValue = fmt.Sprint(value)
+	case float64:
+		parameter.DefaultValue = formatWorkflowNumber(value)
+	}
+	return parameter
+}
+
+// rawString 返回第一个存在的字符串或整数键值
+func rawString(values map[string]interface{}, keys ...string) string {
+	switch value := firstRawValue(values, keys...).(type) {
+	case string:
+		return value
+	case int64:
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// firstRawValue 返回第一个存在的键值
+func firstRawValue(values map[string]interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		if value, exists := values[key]; exists {
+			return value
+		}
+	}
+	return nil
+}
+
+// workflowKeyPattern 工作流键中需要替换的字符
+var workflowKeyPattern = regexp.MustCompile(`[\s./\\"'\[\]]+`)
+
+// workflowKeyFromName 由工作流名称生成工作流键，名称为空时使用工作流ID
+func workflowKeyFromName(name, workflowID string) string {
+	key := strings.Trim(workflowKeyPattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "_"), "_")
+	if key == "" {
+		key = "workflow_" + workflowID
+	}
+	return key
+}
+
+// mergeImportedWorkflows 将导入的工作流合并到当前工作流，按 conflict 处理键冲突
+func mergeImportedWorkflows(current, imported map[string]WorkflowDef, conflict string) (map[string]WorkflowDef, []WorkflowImportItem, error) {
+	switch conflict {
+	case "":
+		conflict = WorkflowConflictSkip
+	case WorkflowConflictSkip, WorkflowConflictOverwrite, WorkflowConflictRename:
+	default:
+		return nil, nil, fmt.Errorf("unknown conflict option '%s'", conflict)
+	}
+
+	merged := make(map[string]WorkflowDef, len(current)+len(imported))
+	for key, workflow := range current {
+		merged[key] = workflow
+	}
+
+	items := make([]WorkflowImportItem, 0, len(imported))
+	for _, key := range sortedKeys(imported) {
+		workflow := imported[key]
+		item := WorkflowImportItem{SourceKey: key, Key: key, Name: workflow.Name, Parameters: len(workflow.Parameters), Action: WorkflowImportAdd}
+		if _, exists := current[key]; exists {
+			item.Conflict = true
+			switch conflict {
+			case WorkflowConflictSkip:
+				item.Action = WorkflowImportSkip
+			case WorkflowConflictOverwrite:
+				item.Action = WorkflowImportOverwrite
+			case WorkflowConflictRename:
+				item.Action = WorkflowImportRename
+				for i := 2; ; i++ {
+					item.Key = fmt.Sprintf("%s_%d", key, i)
+					if _, taken := merged[item.Key]; !taken {
+						if _, pending := imported[item.Key]; !pending {
+							break
+						}
+					}
+				}
+			}
+		}
+		if item.Action != WorkflowImportSkip {
+			merged[item.Key] = workflow
+		}
+		items = append(items, item)
+	}
+	return merged, items, nil
+}
+
+// ImportWorkflows 导入工作流定义，按 conflict 处理与已有工作流的键冲突
+// 键冲突在写锁内判断，新增和覆盖的工作流分配新的修订号
+func (cm *ConfigManager) ImportWorkflows(imported map[string]WorkflowDef, conflict string) ([]WorkflowImportItem, error) {
+	var items []WorkflowImportItem
+	err := cm.updateChecked(func(config *Config) error {
+		merged, result, err := mergeImportedWorkflows(config.Workflow.Workflows, imported, conflict)
+		if err != nil {
+			return err
+		}
+		config.Workflow.Workflows = merged
+		items = result
+		return nil
+	})
+	return items, err
+}
+
+// previewWorkflowImport 解析导入文件，返回文件中的工作流和导入预览，不修改当前配置
+func previewWorkflowImport(current *Config, data []byte, conflict string) (map[string]WorkflowDef, *WorkflowImportPreview, error) {
+	source, imported, err := parseWorkflowImport(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	merged, items, err := mergeImportedWorkflows(current.Workflow.Workflows, imported, conflict)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next := current.Clone()
+	next.Workflow.Workflows = merged
+	preview := &WorkflowImportPreview{
+		Source:    source,
+		Conflict:  conflict,
+		Workflows: items,
+		Errors:    append([]ValidationError{}, ValidateConfig(next).inSections([]string{"workflow"})...),
+	}
+	if preview.Conflict == "" {
+		preview.Conflict = WorkflowConflictSkip
+	}
+	return imported, preview, nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// parameterSummary 参数中由扣子变量转换而来的字段
+type parameterSummary struct {
+	Key, Type    string
+	Required     bool
+	DefaultValue string
+	HelpText     string
+}
+
+// summarizeParameters 提取参数中由扣子变量转换而来的字段
+func summarizeParameters(parameters []WorkflowParameter) []parameterSummary {
+	summaries := make([]parameterSummary, 0, len(parameters))
+	for _, p := range parameters {
+		summaries = append(summaries, parameterSummary{p.Key, p.Type, p.Required, p.DefaultValue, p.HelpText})
+	}
+	return summaries
+}
+
+func TestParseCozeWorkflow(t *testing.T) {
+	tests := []struct {
+		fixture        string
+		wantKey        string
+		wantName       string
+		wantWorkflowID string
+		wantAppID      string
+		wantParameters []parameterSummary
+	}{
+		{
+			// OpenAPI 返回的工作流信息，变量按名称排序
+			fixture:        "coze-openapi.json",
+			wantKey:        "作文批改",
+			wantName:       "作文批改",
+			wantWorkflowID: "7428837463628939291",
+			wantAppID:      "7428837463628930001",
+			wantParameters: []parameterSummary{
+				{"essay", "text", true, "", "学生作文全文"},
+				{"grade", "number", false, "3", "年级"},
+				{"keywords", "json", false, "", ""},
+				{"photo", "file", false, "", "作文照片"},
+				{"rubric", "json", false, "", "评分标准"},
+				{"score", "number", false, "85.5", ""},
+				{"strict", "boolean", false, "false", "是否严格评分"},
+			},
+		},
+		{
+			// 工作流画布，开始节点的输出按画布中的顺序
+			fixture:        "coze-canvas.json",
+			wantKey:        "课堂出题",
+			wantName:       "课堂出题",
+			wantWorkflowID: "7430012345678901234",
+			wantParameters: []parameterSummary{
+				{"topic", "text", true, "", "知识点"},
+				{"count", "number", false, "5", ""},
+				{"worksheet", "file", false, "", ""},
+				{"types", "json", false, "", ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "workflows", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, workflows, err := parseWorkflowImport(data)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if source != WorkflowSourceCoze || len(workflows) != 1 {
+				t.Fatalf("source = %s, workflows = %v", source, sortedKeys(workflows))
+			}
+			workflow, exists := workflows[tt.wantKey]
+			if !exists {
+				t.Fatalf("workflow keys = %v, want %s", sortedKeys(workflows), tt.wantKey)
+			}
+			if workflow.Name != tt.wantName || workflow.WorkflowID != tt.wantWorkflowID || workflow.AppID != tt.wantAppID {
+				t.Fatalf("workflow = %s/%s/%s, want %s/%s/%s", workflow.Name, workflow.WorkflowID, workflow.AppID,
+					tt.wantName, tt.wantWorkflowID, tt.wantAppID)
+			}
+			if got := summarizeParameters(workflow.Parameters); !reflect.DeepEqual(got, tt.wantParameters) {
+				t.Fatalf("parameters = %+v, want %+v", got, tt.wantParameters)
+			}
+		})
+	}
+}
+
+func TestParseCozeWorkflowRejectsOtherFiles(t *testing.T) {
+	for _, content := range []string{
+		`{"data": {"workflow_detail": {"workflow_name": "无ID"}, "input": {"parameters": {}}}}`,
+		`{"workflow_id": "1", "name": "无开始节点", "nodes": [{"type": "2"}]}`,
+		`{"name": "other"}`,
+	} {
+		if _, _, err := parseWorkflowImport([]byte(content)); err == nil {
+			t.Fatalf("%s should not be recognized", content)
+		}
+	}
+}
+
+func TestCozeParameter(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable map[string]interface{}
+		want     parameterSummary
+	}{
+		{"string", map[string]interface{}{"type": "string"}, parameterSummary{"v", "text", false, "", ""}},
+		{"integer", map[string]interface{}{"type": "Integer", "default_value": int64(3)}, parameterSummary{"v", "number", false, "3", ""}},
+		{"float default", map[string]interface{}{"type": "float", "defaultValue": 2.5}, parameterSummary{"v", "number", false, "2.5", ""}},
+		{"boolean", map[string]interface{}{"type": "boolean", "default_value": true}, parameterSummary{"v", "boolean", false, "true", ""}},
+		{"array", map[string]interface{}{"type": "array"}, parameterSummary{"v", "json", false, "", ""}},
+		{"image", map[string]interface{}{"type": "image"}, parameterSummary{"v", "file", false, "", ""}},
+		{"string with assist type", map[string]interface{}{"type": "string", "assist_type": int64(1)}, parameterSummary{"v", "file", false, "", ""}},
+		{"required with description", map[string]interface{}{"type": "string", "required": true, "description": "说明"}, parameterSummary{"v", "text", true, "", "说明"}},
+		{"unknown type", map[string]interface{}{"type": "time"}, parameterSummary{"v", "text", false, "", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parameter := cozeParameter("v", tt.variable)
+			if parameter.Label != "v" {
+				t.Fatalf("label = %q, want the variable name", parameter.Label)
+			}
+			if got := summarizeParameters([]WorkflowParameter{parameter})[0]; got != tt.want {
+				t.Fatalf("parameter = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeImportedWorkflows(t *testing.T) {
+	current := map[string]WorkflowDef{
+		"essay":   {Name: "作文", WorkflowID: "wf-old", Revision: 3},
+		"essay_2": {Name: "作文二", WorkflowID: "wf-old-2", Revision: 4},
+		"quiz":    {Name: "出题", WorkflowID: "wf-quiz", Revision: 5},
+	}
+	imported := map[string]WorkflowDef{
+		"essay":   {Name: "新作文", WorkflowID: "wf-new"},
+		"essay_3": {Name: "作文三", WorkflowID: "wf-new-3"},
+		"reading": {Name: "阅读", WorkflowID: "wf-reading"},
+	}
+
+	tests := []struct {
+		conflict   string
+		wantItems  []WorkflowImportItem
+		wantMerged map[string]string // 键 -> 工作流ID
+	}{
+		{
+			conflict: "",
+			wantItems: []WorkflowImportItem{
+				{SourceKey: "essay", Key: "essay", Name: "新作文", Conflict: true, Action: WorkflowImportSkip},
+				{SourceKey: "essay_3", Key: "essay_3", Name: "作文三", Action: WorkflowImportAdd},
+				{SourceKey: "reading", Key: "reading", Name: "阅读", Action: WorkflowImportAdd},
+			},
+			wantMerged: map[string]string{"essay": "wf-old", "essay_2": "wf-old-2", "essay_3": "wf-new-3", "quiz": "wf-quiz", "reading": "wf-reading"},
+		},
+		{
+			conflict: WorkflowConflictOverwrite,
+			wantItems: []WorkflowImportItem{
+				{SourceKey: "essay", Key: "essay", Name: "新作文", Conflict: true, Action: WorkflowImportOverwrite},
+				{SourceKey: "essay_3", Key: "essay_3", Name: "作文三", Action: WorkflowImportAdd},
+				{SourceKey: "reading", Key: "reading", Name: "阅读", Action: WorkflowImportAdd},
+			},
+			wantMerged: map[string]string{"essay": "wf-new", "essay_2": "wf-old-2", "essay_3": "wf-new-3", "quiz": "wf-quiz", "reading": "wf-reading"},
+		},
+		{
+			// essay_2 已存在，essay_3 由同一文件导入，重命名为 essay_4
+			conflict: WorkflowConflictRename,
+			wantItems: []WorkflowImportItem{
+				{SourceKey: "essay", Key: "essay_4", Name: "新作文", Conflict: true, Action: WorkflowImportRename},
+				{SourceKey: "essay_3", Key: "essay_3", Name: "作文三", Action: WorkflowImportAdd},
+				{SourceKey: "reading", Key: "reading", Name: "阅读", Action: WorkflowImportAdd},
+			},
+			wantMerged: map[string]string{"essay": "wf-old", "essay_2": "wf-old-2", "essay_3": "wf-new-3", "essay_4": "wf-new", "quiz": "wf-quiz", "reading": "wf-reading"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("conflict "+tt.conflict, func(t *testing.T) {
+			merged, items, err := mergeImportedWorkflows(current, imported, tt.conflict)
+			if err != nil {
+				t.Fatalf("failed to merge: %v", err)
+			}
+			if !reflect.DeepEqual(items, tt.wantItems) {
+				t.Fatalf("items = %+v, want %+v", items, tt.wantItems)
+			}
+			ids := make(map[string]string, len(merged))
+			for key, workflow := range merged {
+				ids[key] = workflow.WorkflowID
+			}
+			if !reflect.DeepEqual(ids, tt.wantMerged) {
+				t.Fatalf("merged = %v, want %v", ids, tt.wantMerged)
+			}
+			// 未导入的已有工作流保持不变，当前工作流不被修改
+			if merged["quiz"].Revision != 5 || current["essay"].WorkflowID != "wf-old" || len(current) != 3 {
+				t.Fatalf("existing workflows were modified")
+			}
+		})
+	}
+
+	if _, _, err := mergeImportedWorkflows(current, imported, "replace"); err == nil {
+		t.Fatalf("an unknown conflict option should be rejected")
+	}
+}